@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -25,8 +26,6 @@ func main() {
 		return
 	}
 
-	time.Sleep(2 * time.Second)
-
 	addressBook, err := dogeTest.SetupAddresses([]dogetest.AddressSetup{
 		{
 			Label:          "test1",
@@ -48,6 +47,15 @@ func main() {
 		return
 	}
 
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	err = dogeTest.WaitForBalance(ctx, address.Address, 100)
+	if err != nil {
+		fmt.Println("Failed to wait for balance:", err)
+		return
+	}
+
 	wallet, err := dogeTest.GetWallet(address.Address)
 	if err != nil {
 		fmt.Println("Failed to get wallet:", err)
@@ -0,0 +1,349 @@
+// rpcgen generates typed RpcTransport bindings from the output of the
+// Dogecoin Core `help` RPC.
+//
+// The input is the concatenated output of `help <command>` for every
+// command listed by `help`, with the category headers (`== Wallet ==`) kept
+// and each command terminated by a line containing only `---`. Methods that
+// are already written by hand in the target package are skipped, so a
+// hand-written binding with a proper result type always wins over the
+// generated one.
+//
+// Usage (see the go:generate directive in pkg/rpc):
+//
+//	go run ./cmd/rpcgen -in testdata/help.txt -out rpc_gen.go
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type argument struct {
+	Name        string
+	Type        string
+	Optional    bool
+	Description string
+	// Default is Core's default for an optional argument as a Go literal,
+	// empty when help does not state one.
+	Default string
+}
+
+type command struct {
+	Name        string
+	Category    string
+	Usage       string
+	Description []string
+	Args        []argument
+}
+
+var (
+	categoryRe = regexp.MustCompile(`^== (.+) ==$`)
+	// sectionRe matches the headers of help text, like "Arguments:" (the
+	// colon is sometimes missing) and "Result (for verbose = true):".
+	sectionRe  = regexp.MustCompile(`^(Arguments|Result|Examples)( \(.*\))?:?$`)
+	argumentRe = regexp.MustCompile(`^(\d+)\.\s+\(?"?([A-Za-z_][A-Za-z0-9_]*)"?\)?:?\s+\(([^)]*)\)\s*(.*)$`)
+)
+
+func main() {
+	in := flag.String("in", "testdata/help.txt", "captured help output")
+	out := flag.String("out", "rpc_gen.go", "generated file")
+	pkg := flag.String("pkg", "rpc", "package name of the generated file")
+	flag.Parse()
+
+	commands, err := parseHelp(*in)
+	if err != nil {
+		log.Fatalf("parse %s: %v", *in, err)
+	}
+
+	existing, err := handWrittenMethods(filepath.Dir(*out), filepath.Base(*out))
+	if err != nil {
+		log.Fatalf("scan package: %v", err)
+	}
+
+	src, err := generate(*pkg, filepath.ToSlash(*in), commands, existing)
+	if err != nil {
+		log.Fatalf("generate: %v", err)
+	}
+
+	err = os.WriteFile(*out, src, 0644)
+	if err != nil {
+		log.Fatalf("write %s: %v", *out, err)
+	}
+}
+
+func parseHelp(path string) ([]command, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var commands []command
+	var current *command
+	category := ""
+	// section is the header the current line is under, "" for the
+	// description that follows the usage line.
+	section := ""
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t")
+
+		if m := categoryRe.FindStringSubmatch(line); m != nil {
+			category = m[1]
+			continue
+		}
+
+		if line == "---" {
+			if current != nil {
+				commands = append(commands, *current)
+			}
+			current = nil
+			section = ""
+			continue
+		}
+
+		if current == nil {
+			if line == "" {
+				continue
+			}
+			current = &command{
+				Name:     strings.Fields(line)[0],
+				Category: category,
+				Usage:    line,
+			}
+			continue
+		}
+
+		if m := sectionRe.FindStringSubmatch(line); m != nil {
+			section = m[1]
+			continue
+		}
+
+		switch {
+		case section == "Arguments":
+			m := argumentRe.FindStringSubmatch(strings.TrimSpace(line))
+			if m == nil {
+				continue
+			}
+			current.Args = append(current.Args, parseArgument(m[2], m[3], m[4]))
+		case section == "" && line != "":
+			current.Description = append(current.Description, strings.TrimSpace(line))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if current != nil {
+		commands = append(commands, *current)
+	}
+
+	return commands, nil
+}
+
+// parseArgument maps the "(type, required|optional, ...)" annotation used by
+// Core's help text onto a Go type.
+func parseArgument(name string, annotation string, description string) argument {
+	parts := strings.Split(annotation, ",")
+	kind := strings.TrimSpace(parts[0])
+
+	arg := argument{
+		Name:        name,
+		Description: strings.TrimSpace(description),
+	}
+	var def string
+	for _, part := range parts[1:] {
+		part = strings.TrimSpace(part)
+		if part == "optional" {
+			arg.Optional = true
+		}
+		if value, ok := strings.CutPrefix(part, "default="); ok {
+			def = value
+		}
+	}
+
+	lowerDesc := strings.ToLower(arg.Description)
+
+	switch {
+	case kind == "boolean" || kind == "bool":
+		arg.Type = "bool"
+	case strings.HasPrefix(kind, "numeric or"):
+		arg.Type = "float64"
+	case kind == "numeric" || kind == "integer":
+		// Core describes both counts and amounts as "numeric".
+		if strings.Contains(name, "amount") || strings.Contains(name, "fee") || strings.Contains(name, "delta") {
+			arg.Type = "float64"
+		} else {
+			arg.Type = "int64"
+		}
+	case kind == "array" || kind == "json array":
+		arg.Type = "[]any"
+	case kind == "object" || kind == "json object" || kind == "json":
+		arg.Type = "map[string]any"
+	case strings.Contains(lowerDesc, "json array"):
+		arg.Type = "[]any"
+	case strings.Contains(lowerDesc, "json object"):
+		arg.Type = "map[string]any"
+	default:
+		arg.Type = "string"
+	}
+
+	if arg.Optional && def != "" {
+		arg.Default = defaultLiteral(arg.Type, def)
+	}
+
+	return arg
+}
+
+// defaultLiteral converts a default from help, like true, 6 or "", into a
+// Go literal of the argument's type. Defaults that do not parse as that
+// type, and those of arrays and objects, are left out.
+func defaultLiteral(typ string, def string) string {
+	switch typ {
+	case "bool":
+		if def == "true" || def == "false" {
+			return def
+		}
+	case "int64":
+		if _, err := strconv.ParseInt(def, 10, 64); err == nil {
+			return def
+		}
+	case "float64":
+		if _, err := strconv.ParseFloat(def, 64); err == nil {
+			return def
+		}
+	case "string":
+		if unquoted, err := strconv.Unquote(def); err == nil {
+			return strconv.Quote(unquoted)
+		}
+		return strconv.Quote(def)
+	}
+
+	return ""
+}
+
+// handWrittenMethods returns the names of RpcTransport methods declared in
+// the package directory, excluding the generated file itself.
+func handWrittenMethods(dir string, generated string) (map[string]bool, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(info os.FileInfo) bool {
+		return info.Name() != generated && !strings.HasSuffix(info.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	methods := map[string]bool{}
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				fn, ok := decl.(*ast.FuncDecl)
+				if !ok || fn.Recv == nil || len(fn.Recv.List) == 0 {
+					continue
+				}
+				star, ok := fn.Recv.List[0].Type.(*ast.StarExpr)
+				if !ok {
+					continue
+				}
+				if ident, ok := star.X.(*ast.Ident); ok && ident.Name == "RpcTransport" {
+					methods[fn.Name.Name] = true
+				}
+			}
+		}
+	}
+
+	return methods, nil
+}
+
+func generate(pkg string, source string, commands []command, existing map[string]bool) ([]byte, error) {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "// Code generated by rpcgen from %s; DO NOT EDIT.\n\n", source)
+	fmt.Fprintf(&buf, "package %s\n\n", pkg)
+	fmt.Fprintf(&buf, "import \"encoding/json\"\n")
+
+	category := ""
+	for _, cmd := range commands {
+		name := goName(cmd.Name)
+		if existing[name] {
+			continue
+		}
+
+		if cmd.Category != category {
+			category = cmd.Category
+			fmt.Fprintf(&buf, "\n// == %s ==\n", category)
+		}
+
+		if len(cmd.Args) > 0 {
+			fmt.Fprintf(&buf, "\n// %sParams holds the arguments of the %s RPC.\n", name, cmd.Name)
+			if hasOptional(cmd.Args) {
+				fmt.Fprintf(&buf, "// Optional arguments left nil are omitted from the request, or sent as\n")
+				fmt.Fprintf(&buf, "// Core's default when a later argument is set.\n")
+			}
+			fmt.Fprintf(&buf, "type %sParams struct {\n", name)
+			for _, arg := range cmd.Args {
+				fieldType := arg.Type
+				if arg.Optional && !strings.HasPrefix(fieldType, "[]") && !strings.HasPrefix(fieldType, "map") {
+					fieldType = "*" + fieldType
+				}
+				fmt.Fprintf(&buf, "\t%s %s", goName(arg.Name), fieldType)
+				if arg.Description != "" {
+					fmt.Fprintf(&buf, " // %s", arg.Description)
+				}
+				fmt.Fprintf(&buf, "\n")
+			}
+			fmt.Fprintf(&buf, "}\n")
+		}
+
+		fmt.Fprintf(&buf, "\n// %s calls the %s RPC.\n//\n//\t%s\n", name, cmd.Name, cmd.Usage)
+		if len(cmd.Description) > 0 {
+			fmt.Fprintf(&buf, "//\n")
+			for _, line := range cmd.Description {
+				fmt.Fprintf(&buf, "// %s\n", line)
+			}
+		}
+
+		if len(cmd.Args) == 0 {
+			fmt.Fprintf(&buf, "func (t *RpcTransport) %s() (json.RawMessage, error) {\n", name)
+			fmt.Fprintf(&buf, "\treturn Call[json.RawMessage](t, %q)\n}\n", cmd.Name)
+			continue
+		}
+
+		fields := make([]string, len(cmd.Args))
+		defaults := make([]string, len(cmd.Args))
+		for i, arg := range cmd.Args {
+			fields[i] = "p." + goName(arg.Name)
+			defaults[i] = "nil"
+			if arg.Default != "" {
+				defaults[i] = arg.Default
+			}
+		}
+		fmt.Fprintf(&buf, "func (t *RpcTransport) %s(p %sParams) (json.RawMessage, error) {\n", name, name)
+		fmt.Fprintf(&buf, "\tparams, err := trimParams(%q, []any{%s}, []any{%s})\n", cmd.Name, strings.Join(fields, ", "), strings.Join(defaults, ", "))
+		fmt.Fprintf(&buf, "\tif err != nil {\n\t\treturn nil, err\n\t}\n\n")
+		fmt.Fprintf(&buf, "\treturn Call[json.RawMessage](t, %q, params...)\n}\n", cmd.Name)
+	}
+
+	return format.Source(buf.Bytes())
+}
+
+func hasOptional(args []argument) bool {
+	for _, arg := range args {
+		if arg.Optional {
+			return true
+		}
+	}
+	return false
+}
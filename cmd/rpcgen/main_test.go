@@ -0,0 +1,248 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// parseText runs parseHelp on help text.
+func parseText(t *testing.T, text string) []command {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "help.txt")
+	if err := os.WriteFile(path, []byte(text), 0644); err != nil {
+		t.Fatal(err)
+	}
+	commands, err := parseHelp(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return commands
+}
+
+func TestParseHelp(t *testing.T) {
+	tests := []struct {
+		name string
+		help string
+		want []command
+	}{
+		{
+			name: "no arguments",
+			help: "== Blockchain ==\n" +
+				"getblockcount\n" +
+				"\n" +
+				"Returns the number of blocks in the longest blockchain.\n" +
+				"\n" +
+				"Result:\n" +
+				"n    (numeric) The current block count\n" +
+				"\n" +
+				"Examples:\n" +
+				"> dogecoin-cli getblockcount \n" +
+				"---\n",
+			want: []command{{
+				Name:        "getblockcount",
+				Category:    "Blockchain",
+				Usage:       "getblockcount",
+				Description: []string{"Returns the number of blocks in the longest blockchain."},
+			}},
+		},
+		{
+			name: "description right after the usage",
+			help: "getblockchaininfo\n" +
+				"Returns an object containing various state info regarding blockchain processing.\n" +
+				"---\n",
+			want: []command{{
+				Name:        "getblockchaininfo",
+				Usage:       "getblockchaininfo",
+				Description: []string{"Returns an object containing various state info regarding blockchain processing."},
+			}},
+		},
+		{
+			name: "a line starting with Result",
+			help: "ping\n" +
+				"\n" +
+				"Requests that a ping be sent to all other nodes, to measure ping time.\n" +
+				"Results provided in getpeerinfo, pingtime and pingwait fields are decimal seconds.\n" +
+				"---\n",
+			want: []command{{
+				Name:  "ping",
+				Usage: "ping",
+				Description: []string{
+					"Requests that a ping be sent to all other nodes, to measure ping time.",
+					"Results provided in getpeerinfo, pingtime and pingwait fields are decimal seconds.",
+				},
+			}},
+		},
+		{
+			name: "arguments with nested and continued lines",
+			help: "== Wallet ==\n" +
+				"listunspent ( minconf maxconf  [\"addresses\",...] [include_unsafe] )\n" +
+				"\n" +
+				"Returns array of unspent transaction outputs\n" +
+				"with between minconf and maxconf (inclusive) confirmations.\n" +
+				"\n" +
+				"Arguments:\n" +
+				"1. minconf          (numeric, optional, default=1) The minimum confirmations to filter\n" +
+				"2. maxconf          (numeric, optional, default=9999999) The maximum confirmations to filter\n" +
+				"3. \"addresses\"    (string) A json array of dogecoin addresses to filter\n" +
+				"    [\n" +
+				"      \"address\"   (string) dogecoin address\n" +
+				"      ,...\n" +
+				"    ]\n" +
+				"4. include_unsafe (bool, optional, default=true) Include outputs that are not safe to spend\n" +
+				"                  because they come from unconfirmed untrusted transactions or unconfirmed\n" +
+				"\n" +
+				"Result\n" +
+				"[                   (array of json object)\n" +
+				"  {\n" +
+				"    \"txid\" : \"txid\",          (string) the transaction id \n" +
+				"---\n",
+			want: []command{{
+				Name:     "listunspent",
+				Category: "Wallet",
+				Usage:    "listunspent ( minconf maxconf  [\"addresses\",...] [include_unsafe] )",
+				Description: []string{
+					"Returns array of unspent transaction outputs",
+					"with between minconf and maxconf (inclusive) confirmations.",
+				},
+				Args: []argument{
+					{Name: "minconf", Type: "int64", Optional: true, Description: "The minimum confirmations to filter", Default: "1"},
+					{Name: "maxconf", Type: "int64", Optional: true, Description: "The maximum confirmations to filter", Default: "9999999"},
+					{Name: "addresses", Type: "[]any", Description: "A json array of dogecoin addresses to filter"},
+					{Name: "include_unsafe", Type: "bool", Optional: true, Description: "Include outputs that are not safe to spend", Default: "true"},
+				},
+			}},
+		},
+		{
+			name: "header without colon and two commands",
+			help: "keypoolrefill ( newsize )\n" +
+				"\n" +
+				"Fills the keypool.\n" +
+				"\n" +
+				"Arguments\n" +
+				"1. newsize     (numeric, optional, default=100) The new keypool size\n" +
+				"---\n" +
+				"estimatefee nblocks\n" +
+				"\n" +
+				"Estimates the approximate fee per kilobyte needed for a transaction to begin\n" +
+				"\n" +
+				"Arguments:\n" +
+				"1. nblocks     (numeric, required)\n",
+			want: []command{
+				{
+					Name:        "keypoolrefill",
+					Usage:       "keypoolrefill ( newsize )",
+					Description: []string{"Fills the keypool."},
+					Args:        []argument{{Name: "newsize", Type: "int64", Optional: true, Description: "The new keypool size", Default: "100"}},
+				},
+				{
+					Name:        "estimatefee",
+					Usage:       "estimatefee nblocks",
+					Description: []string{"Estimates the approximate fee per kilobyte needed for a transaction to begin"},
+					Args:        []argument{{Name: "nblocks", Type: "int64"}},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseText(t, tt.help); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parsed\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseArgument(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotation  string
+		description string
+		want        argument
+	}{
+		{"height", "numeric, required", "The height index", argument{Type: "int64"}},
+		{"amount", "numeric or string, required", "The amount in DOGE to send. eg 0.1", argument{Type: "float64"}},
+		{"fee_delta", "numeric, required", "The fee value (in koinu) to add", argument{Type: "float64"}},
+		{"verbose", "boolean, optional, default=true", "true for a json object", argument{Type: "bool", Optional: true, Default: "true"}},
+		{"include_unsafe", "bool, optional, default=true", "", argument{Type: "bool", Optional: true, Default: "true"}},
+		{"checklevel", "numeric, optional, 0-4, default=3", "How thorough the block verification is.", argument{Type: "int64", Optional: true, Default: "3"}},
+		{"nblocks", "numeric, optional, default=288, 0=all", "The number of blocks to check.", argument{Type: "int64", Optional: true, Default: "288"}},
+		{"maxtries", "numeric, optional", "How many iterations to try (default = 1000000).", argument{Type: "int64", Optional: true}},
+		{"comment", "string, optional", "A comment", argument{Type: "string", Optional: true}},
+		{"account", "string, optional, default=\"\"", "The account name", argument{Type: "string", Optional: true, Default: `""`}},
+		{"sighashtype", "string, optional, default=ALL", "The signature hash type", argument{Type: "string", Optional: true, Default: `"ALL"`}},
+		{"template_request", "json object, optional", "A json object in the following spec", argument{Type: "map[string]any", Optional: true}},
+		{"options", "object, optional", "", argument{Type: "map[string]any", Optional: true}},
+		{"keys", "array, required", "", argument{Type: "[]any"}},
+		{"transactions", "string, optional", "A json array of objects.", argument{Type: "[]any", Optional: true}},
+		{"parameters", "string, optional", "A json object of optional parameters", argument{Type: "map[string]any", Optional: true}},
+		{"minconf", "numeric, optional, default=many", "", argument{Type: "int64", Optional: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := tt.want
+			want.Name = tt.name
+			want.Description = tt.description
+
+			if got := parseArgument(tt.name, tt.annotation, "  "+tt.description+" "); got != want {
+				t.Errorf("parseArgument(%q, %q) = %+v, want %+v", tt.name, tt.annotation, got, want)
+			}
+		})
+	}
+}
+
+func TestDefaultLiteral(t *testing.T) {
+	tests := []struct {
+		typ, def, want string
+	}{
+		{"bool", "false", "false"},
+		{"bool", "1", ""},
+		{"int64", "-1", "-1"},
+		{"int64", "0.5", ""},
+		{"float64", "0.0001", "0.0001"},
+		{"float64", "auto", ""},
+		{"string", `""`, `""`},
+		{"string", `"main"`, `"main"`},
+		{"string", "ALL", `"ALL"`},
+		{"[]any", "[]", ""},
+		{"map[string]any", "{}", ""},
+	}
+
+	for _, tt := range tests {
+		if got := defaultLiteral(tt.typ, tt.def); got != tt.want {
+			t.Errorf("defaultLiteral(%s, %s) = %s, want %s", tt.typ, tt.def, got, tt.want)
+		}
+	}
+}
+
+// TestGeneratedFile regenerates pkg/rpc's bindings as go generate does and
+// checks that the committed rpc_gen.go is up to date.
+func TestGeneratedFile(t *testing.T) {
+	dir := filepath.Join("..", "..", "pkg", "rpc")
+
+	commands, err := parseHelp(filepath.Join(dir, "testdata", "help.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	existing, err := handWrittenMethods(dir, "rpc_gen.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := generate("rpc", "testdata/help.txt", commands, existing)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	committed, err := os.ReadFile(filepath.Join(dir, "rpc_gen.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(src, committed) {
+		t.Error("pkg/rpc/rpc_gen.go is stale: run go generate ./pkg/rpc")
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import "strings"
+
+// words is the vocabulary used to split Core's all-lowercase RPC and
+// argument names into Go identifiers, e.g. getblockchaininfo becomes
+// GetBlockchainInfo.
+var words = []string{
+	"abandon", "absolute", "account", "accounts", "active", "add", "added",
+	"address", "addresses", "all", "allow", "amount", "amounts", "ancestors",
+	"aux", "auxpow", "backup", "balance", "banned", "ban", "best", "block",
+	"blockchain", "blocks", "by", "change", "chain", "check", "clear", "command",
+	"comment", "connection", "count", "create", "data", "decode", "delta",
+	"descendants", "destination", "difficulty", "disconnect", "dogecoin",
+	"dummy", "dump", "empty", "encrypt", "entry", "estimate", "fee", "fees",
+	"filename", "from", "funds", "fund", "generate", "get", "groupings", "hash",
+	"header", "height", "help", "hex", "high", "id", "ids", "import", "include",
+	"info", "key", "keys", "keypool", "label", "level", "list", "lock",
+	"many", "max", "mempool", "memory", "message", "min", "mining", "move", "multi",
+	"multisig", "n", "net", "network", "new", "node", "old", "out", "p2sh",
+	"params", "parameters", "passphrase", "peer", "ping", "precious", "prev",
+	"prioritise", "priority", "priv", "proof", "prune", "pruned", "ps", "pub", "raw",
+	"receive", "received", "refill", "remove", "request", "requests",
+	"required", "rescan", "rules", "script", "send", "set", "sighash", "sign",
+	"signature", "since", "size", "skip", "smart", "state", "stop", "string",
+	"submit", "subnet", "subtract", "target", "template", "time", "timeout",
+	"to", "totals", "transaction", "transactions", "tries", "tx", "txs", "type",
+	"unconfirmed", "unlock", "unsafe", "unspent", "validate", "verbose",
+	"verify", "wallet", "watchonly", "with", "witness", "confirmations",
+	"conf", "tips", "txid", "txids", "nblocks", "nrequired", "hexdata",
+	"hexstring", "privkey", "privkeys", "pubkey",
+}
+
+// initialisms are rendered in upper case, matching TxID in the hand-written
+// types.
+var initialisms = map[string]string{
+	"id":   "ID",
+	"ids":  "IDs",
+	"ps":   "PS",
+	"p2sh": "P2SH",
+}
+
+// compounds are spelled out explicitly where the shortest split would read
+// badly.
+var compounds = map[string][]string{
+	"txid":      {"tx", "id"},
+	"txids":     {"tx", "ids"},
+	"nblocks":   {"n", "blocks"},
+	"nrequired": {"n", "required"},
+	"hexdata":   {"hex", "data"},
+	"hexstring": {"hex", "string"},
+	"privkey":   {"priv", "key"},
+	"privkeys":  {"priv", "keys"},
+	"pubkey":    {"pub", "key"},
+}
+
+var vocabulary = func() map[string]bool {
+	vocab := make(map[string]bool, len(words))
+	for _, w := range words {
+		vocab[w] = true
+	}
+	return vocab
+}()
+
+// goName converts an RPC or argument name into an exported Go identifier.
+func goName(name string) string {
+	var sb strings.Builder
+	for _, part := range strings.Split(name, "_") {
+		for _, word := range split(strings.ToLower(part)) {
+			if upper, ok := initialisms[word]; ok {
+				sb.WriteString(upper)
+				continue
+			}
+			sb.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
+	}
+	return sb.String()
+}
+
+// split segments s into the fewest vocabulary words. Unknown names are
+// returned whole.
+func split(s string) []string {
+	if s == "" {
+		return nil
+	}
+
+	// best[i] holds the shortest segmentation of s[:i].
+	best := make([][]string, len(s)+1)
+	best[0] = []string{}
+	for i := 1; i <= len(s); i++ {
+		for j := 0; j < i; j++ {
+			if best[j] == nil || !vocabulary[s[j:i]] {
+				continue
+			}
+			if best[i] == nil || len(best[j])+1 < len(best[i]) {
+				best[i] = append(append([]string{}, best[j]...), s[j:i])
+			}
+		}
+	}
+
+	if best[len(s)] == nil {
+		return []string{s}
+	}
+
+	var expanded []string
+	for _, word := range best[len(s)] {
+		if parts, ok := compounds[word]; ok {
+			expanded = append(expanded, parts...)
+			continue
+		}
+		expanded = append(expanded, word)
+	}
+	return expanded
+}
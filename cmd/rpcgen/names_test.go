@@ -0,0 +1,34 @@
+package main
+
+import "testing"
+
+func TestGoName(t *testing.T) {
+	tests := []struct {
+		name, want string
+	}{
+		{"getblockcount", "GetBlockCount"},
+		{"getblockchaininfo", "GetBlockchainInfo"},
+		{"listunspent", "ListUnspent"},
+		{"sendtoaddress", "SendToAddress"},
+		{"getrawmempool", "GetRawMempool"},
+		{"createmultisig", "CreateMultisig"},
+		{"gettxoutproof", "GetTxOutProof"},
+		{"txid", "TxID"},
+		{"txids", "TxIDs"},
+		{"nblocks", "NBlocks"},
+		{"privkey", "PrivKey"},
+		{"hexstring", "HexString"},
+		{"include_unsafe", "IncludeUnsafe"},
+		{"comment_to", "CommentTo"},
+		{"p2sh", "P2SH"},
+		// Names outside the vocabulary are only capitalized.
+		{"zzyzx", "Zzyzx"},
+		{"getzzyzx", "Getzzyzx"},
+	}
+
+	for _, tt := range tests {
+		if got := goName(tt.name); got != tt.want {
+			t.Errorf("goName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package datacarrier
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/dogecoinfoundation/dogetest/pkg/doge"
+	"github.com/dogecoinfoundation/dogetest/pkg/rpc"
+	"github.com/dogecoinfoundation/dogetest/pkg/txbuilder"
+)
+
+const (
+	// MaxChunkSize is the largest piece of the body pushed at once.
+	MaxChunkSize = 240
+	// MaxPartialSize bounds the envelope bytes revealed by one transaction,
+	// which keeps its scriptSig under the 1650-byte standardness limit.
+	MaxPartialSize = 1500
+	// DefaultOutputValue is the value in koinu of every P2SH link of the
+	// chain and of the inscribed output, the soft dust limit of Core 1.14.
+	DefaultOutputValue int64 = 1_000_000
+)
+
+// protocolID marks the start of an envelope.
+var protocolID = []byte("ord")
+
+// Inscription is a payload with its content type, as written to the chain
+// by BuildInscription and read back by Parser.
+//
+// The envelope is the push sequence
+//
+//	"ord" <number of chunks> <content type> (<countdown> <chunk>)...
+//
+// with chunks of at most MaxChunkSize bytes counting down to 0. It is split
+// over consecutive transactions: each one locks an output to
+// P2SH(<pubkey> OP_CHECKSIGVERIFY OP_DROP... OP_TRUE), with one OP_DROP per
+// push of its part of the envelope, and the next transaction reveals that
+// part in front of its signature when spending the output as input 0.
+type Inscription struct {
+	ContentType string
+	Body        []byte
+
+	// Genesis is the txid of the first reveal transaction.
+	Genesis string
+	// Reveals lists the txids of all reveal transactions in order. The last
+	// one holds the inscribed output at index 0.
+	Reveals []string
+}
+
+// ID returns the inscription id, "<genesis txid>i0".
+func (i *Inscription) ID() string {
+	return i.Genesis + "i0"
+}
+
+// ChainConfig selects how BuildInscription funds and signs the chain.
+type ChainConfig struct {
+	// Key signs the lock scripts and the funding inputs and receives the
+	// change of every transaction.
+	Key *doge.KeyPair
+	// UTXOs are P2PKH outputs of Key that fund the chain. All of them are
+	// spent by the first transaction.
+	UTXOs []rpc.UTXO
+	// Destination receives the inscribed output. Defaults to Key's address.
+	Destination string
+	// OutputValue defaults to DefaultOutputValue.
+	OutputValue int64
+	// FeeRate in koinu per kB defaults to txbuilder.DefaultFeeRate.
+	FeeRate int64
+}
+
+// BuildInscription returns the signed transactions that inscribe body, in
+// the order they must be broadcast: a commit transaction, then one reveal
+// per part of the envelope. Each transaction spends the one before it, so
+// long chains need a block mined every 24 transactions to stay within the
+// node's unconfirmed ancestor limit of 25.
+func BuildInscription(contentType string, body []byte, config ChainConfig) (*Inscription, []*doge.Tx, error) {
+	if config.Key == nil {
+		return nil, nil, errors.New("no key to sign the inscription")
+	}
+	if len(config.UTXOs) == 0 {
+		return nil, nil, errors.New("no utxos to fund the inscription")
+	}
+	if config.Destination == "" {
+		config.Destination = config.Key.Address().String()
+	}
+	if config.OutputValue == 0 {
+		config.OutputValue = DefaultOutputValue
+	}
+	if config.FeeRate == 0 {
+		config.FeeRate = txbuilder.DefaultFeeRate
+	}
+
+	parts, err := envelopeParts(contentType, body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	key := config.Key
+	change := key.Address().String()
+	funding := config.UTXOs
+
+	ins := &Inscription{ContentType: contentType, Body: body}
+	var txs []*doge.Tx
+
+	var link *rpc.UTXO
+	var linkLock []byte
+	var linkPart [][]byte
+
+	for i := 0; i <= len(parts); i++ {
+		b := txbuilder.New()
+
+		if link != nil {
+			lock, part := linkLock, linkPart
+			b.AddInput(*link, func(tx *doge.Tx, index int) ([]byte, error) {
+				var scriptSig []byte
+				for _, push := range part {
+					scriptSig = append(scriptSig, push...)
+				}
+				sig, err := doge.SignInput(tx, index, lock, key.PrivateKey, doge.SigHashAll)
+				if err != nil {
+					return nil, err
+				}
+				return append(scriptSig, doge.NewScriptBuilder().AddData(sig).AddData(lock).Script()...), nil
+			})
+		}
+		for _, utxo := range funding {
+			if err := b.AddP2PKHInputKey(utxo, key); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		var lock []byte
+		if i < len(parts) {
+			lock = lockScript(key.PubKey(), len(parts[i]))
+			b.AddScriptOutput(doge.PayToScriptHashScript(doge.Hash160(lock)), config.OutputValue)
+		} else if err := b.AddOutput(config.Destination, config.OutputValue); err != nil {
+			return nil, nil, err
+		}
+		if err := b.SetChange(change, config.FeeRate); err != nil {
+			return nil, nil, err
+		}
+
+		tx, err := b.Build()
+		if err != nil {
+			return nil, nil, fmt.Errorf("transaction %d of the chain: %w", i, err)
+		}
+		txs = append(txs, tx)
+		txid, err := tx.TxID()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if link != nil {
+			if ins.Genesis == "" {
+				ins.Genesis = txid
+			}
+			ins.Reveals = append(ins.Reveals, txid)
+		}
+
+		// The change funds the next transaction. Build leaves it out when it
+		// is below the dust limit, and the chain must then make do with the
+		// link alone.
+		funding = nil
+		if len(tx.TxOut) > 1 {
+			out := tx.TxOut[len(tx.TxOut)-1]
+			funding = []rpc.UTXO{{
+				TxID:         txid,
+				Vout:         len(tx.TxOut) - 1,
+				Amount:       doge.ToDoge(out.Value),
+				ScriptPubKey: hex.EncodeToString(out.ScriptPubKey),
+			}}
+		}
+
+		if lock != nil {
+			link = &rpc.UTXO{
+				TxID:         txid,
+				Vout:         0,
+				Amount:       doge.ToDoge(tx.TxOut[0].Value),
+				ScriptPubKey: hex.EncodeToString(tx.TxOut[0].ScriptPubKey),
+			}
+			linkLock, linkPart = lock, parts[i]
+		}
+	}
+
+	return ins, txs, nil
+}
+
+// envelopeParts encodes the envelope and splits it into the parts revealed
+// by successive transactions, as lists of encoded pushes. A part holds as
+// many whole (countdown, chunk) pairs as fit in MaxPartialSize.
+func envelopeParts(contentType string, body []byte) ([][][]byte, error) {
+	if len(contentType) > MaxPartialSize-len(protocolID)-8 {
+		return nil, fmt.Errorf("content type of %d bytes is too long", len(contentType))
+	}
+
+	var chunks [][]byte
+	for rest := body; len(rest) > 0; {
+		n := min(len(rest), MaxChunkSize)
+		chunks = append(chunks, rest[:n])
+		rest = rest[n:]
+	}
+
+	pairs := [][2][]byte{{pushInt(len(chunks)), pushData([]byte(contentType))}}
+	for i, chunk := range chunks {
+		pairs = append(pairs, [2][]byte{pushInt(len(chunks) - i - 1), pushData(chunk)})
+	}
+
+	var parts [][][]byte
+	part := [][]byte{pushData(protocolID)}
+	size := len(part[0])
+	for _, pair := range pairs {
+		pairSize := len(pair[0]) + len(pair[1])
+		if size+pairSize > MaxPartialSize {
+			parts = append(parts, part)
+			part, size = nil, 0
+		}
+		part = append(part, pair[0], pair[1])
+		size += pairSize
+	}
+
+	return append(parts, part), nil
+}
+
+// lockScript returns <pubKey> OP_CHECKSIGVERIFY followed by one OP_DROP per
+// revealed push and OP_TRUE, leaving a clean stack once the pushes are
+// dropped.
+func lockScript(pubKey []byte, pushes int) []byte {
+	b := doge.NewScriptBuilder().AddData(pubKey).AddOp(doge.OP_CHECKSIGVERIFY)
+	for range pushes {
+		b.AddOp(doge.OP_DROP)
+	}
+	return b.AddOp(doge.OP_TRUE).Script()
+}
+
+func pushData(data []byte) []byte {
+	return doge.NewScriptBuilder().AddData(data).Script()
+}
+
+func pushInt(n int) []byte {
+	return doge.NewScriptBuilder().AddInt64(int64(n)).Script()
+}
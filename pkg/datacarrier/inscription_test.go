@@ -0,0 +1,156 @@
+package datacarrier
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/dogecoinfoundation/dogetest/pkg/doge"
+	"github.com/dogecoinfoundation/dogetest/pkg/rpc"
+)
+
+// fundingUTXO returns a P2PKH output of key worth amount DOGE.
+func fundingUTXO(key *doge.KeyPair, amount float64) rpc.UTXO {
+	return rpc.UTXO{
+		TxID:         "4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b",
+		Amount:       amount,
+		ScriptPubKey: hex.EncodeToString(doge.PayToPubKeyHashScript(doge.Hash160(key.PubKey()))),
+	}
+}
+
+func newTestKey(t *testing.T) *doge.KeyPair {
+	t.Helper()
+	key, err := doge.GenerateKeyPair(&doge.RegTestParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+// rawTxn returns tx as getblock reports it at verbosity 2.
+func rawTxn(t *testing.T, tx *doge.Tx) rpc.RawTxn {
+	t.Helper()
+
+	txid, err := tx.TxID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw := rpc.RawTxn{TxID: txid}
+	for _, in := range tx.TxIn {
+		raw.VIn = append(raw.VIn, rpc.RawTxnVIn{
+			TxID:      in.PrevTxID,
+			VOut:      int(in.PrevIndex),
+			ScriptSig: rpc.RawTxnScriptSig{Hex: hex.EncodeToString(in.ScriptSig)},
+		})
+	}
+	for n, out := range tx.TxOut {
+		raw.VOut = append(raw.VOut, rpc.RawTxnVOut{
+			N:            n,
+			ScriptPubKey: rpc.RawTxnScriptPubKey{Hex: hex.EncodeToString(out.ScriptPubKey)},
+		})
+	}
+	return raw
+}
+
+func TestInscriptionRoundTrip(t *testing.T) {
+	key := newTestKey(t)
+	funding := fundingUTXO(key, 100)
+
+	body := make([]byte, 3*MaxPartialSize+100)
+	for i := range body {
+		body[i] = byte(i)
+	}
+
+	ins, txs, err := BuildInscription("application/octet-stream", body, ChainConfig{Key: key, UTXOs: []rpc.UTXO{funding}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ins.Reveals) < 4 || len(txs) != len(ins.Reveals)+1 {
+		t.Fatalf("%d transactions with %d reveals, want a commit and at least 4 reveals", len(txs), len(ins.Reveals))
+	}
+
+	// Every input verifies against the output it spends, the reveals'
+	// input 0 against the P2SH lock of the transaction before.
+	prevOuts := []*doge.TxOut{{Value: doge.KoinuPerDoge * 100, ScriptPubKey: doge.PayToPubKeyHashScript(doge.Hash160(key.PubKey()))}}
+	for i, tx := range txs {
+		if i > 0 {
+			prev := txs[i-1]
+			prevOuts = []*doge.TxOut{prev.TxOut[0], prev.TxOut[len(prev.TxOut)-1]}
+			if len(tx.TxIn[0].ScriptSig) > 1650 {
+				t.Errorf("reveal %d has a scriptSig of %d bytes", i, len(tx.TxIn[0].ScriptSig))
+			}
+		}
+		if err := doge.VerifyTx(tx, prevOuts, doge.StandardVerifyFlags); err != nil {
+			t.Fatalf("transaction %d: %v", i, err)
+		}
+	}
+
+	// The chain is mined over two blocks, among other transactions.
+	other := rawTxn(t, txs[0])
+	other.TxID = "other"
+	other.VIn[0].ScriptSig.Hex = hex.EncodeToString(doge.NewScriptBuilder().AddData([]byte("ord")).AddData([]byte("such noise")).Script())
+
+	var first, second rpc.Block
+	first.Tx = append(first.Tx, other)
+	for i, tx := range txs {
+		if i <= 2 {
+			first.Tx = append(first.Tx, rawTxn(t, tx))
+		} else {
+			second.Tx = append(second.Tx, rawTxn(t, tx))
+		}
+	}
+
+	p := NewParser()
+	if got := p.AddBlock(&first); len(got) != 0 {
+		t.Fatalf("completed %d inscriptions in the first block", len(got))
+	}
+	got := p.AddBlock(&second)
+	if len(got) != 1 {
+		t.Fatalf("completed %d inscriptions, want 1", len(got))
+	}
+
+	parsed := got[0]
+	if parsed.ContentType != ins.ContentType || !bytes.Equal(parsed.Body, body) {
+		t.Errorf("parsed %q with %d bytes, want %q with %d", parsed.ContentType, len(parsed.Body), ins.ContentType, len(body))
+	}
+	if parsed.ID() != ins.ID() {
+		t.Errorf("ID = %s, want %s", parsed.ID(), ins.ID())
+	}
+	if len(parsed.Reveals) != len(ins.Reveals) {
+		t.Fatalf("parsed %d reveals, want %d", len(parsed.Reveals), len(ins.Reveals))
+	}
+	for i := range ins.Reveals {
+		if parsed.Reveals[i] != ins.Reveals[i] {
+			t.Errorf("reveal %d = %s, want %s", i, parsed.Reveals[i], ins.Reveals[i])
+		}
+	}
+
+	// A parser that starts after the genesis reveal finds nothing.
+	if found := FindInscriptions(&second); len(found) != 0 {
+		t.Errorf("found %d inscriptions without the genesis reveal", len(found))
+	}
+}
+
+func TestInscriptionSmall(t *testing.T) {
+	key := newTestKey(t)
+
+	ins, txs, err := BuildInscription("text/plain", []byte("much wow"), ChainConfig{Key: key, UTXOs: []rpc.UTXO{fundingUTXO(key, 10)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txs) != 2 || ins.Genesis != ins.Reveals[0] {
+		t.Fatalf("%d transactions, genesis %s, reveals %v, want a commit and one reveal", len(txs), ins.Genesis, ins.Reveals)
+	}
+
+	block := &rpc.Block{Tx: []rpc.RawTxn{rawTxn(t, txs[0]), rawTxn(t, txs[1])}}
+	found := FindInscriptions(block)
+	if len(found) != 1 || found[0].ContentType != "text/plain" || string(found[0].Body) != "much wow" {
+		t.Fatalf("found %+v, want the text inscription", found)
+	}
+
+	// The inscribed output pays the key's address.
+	want := doge.PayToPubKeyHashScript(doge.Hash160(key.PubKey()))
+	if out := txs[1].TxOut[0]; !bytes.Equal(out.ScriptPubKey, want) || out.Value != DefaultOutputValue {
+		t.Errorf("inscribed output pays %d to %x, want %d to the key", out.Value, out.ScriptPubKey, DefaultOutputValue)
+	}
+}
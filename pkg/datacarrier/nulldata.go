@@ -0,0 +1,78 @@
+// Package datacarrier embeds data in transactions and finds it again in
+// blocks: OP_RETURN outputs, and Doginal-style inscriptions whose payload is
+// revealed in the scriptSigs of a chain of P2SH spends.
+package datacarrier
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+
+	"github.com/dogecoinfoundation/dogetest/pkg/doge"
+	"github.com/dogecoinfoundation/dogetest/pkg/rpc"
+	"github.com/dogecoinfoundation/dogetest/pkg/txbuilder"
+)
+
+// MaxNullDataSize is Dogecoin Core's default -datacarriersize: the largest
+// OP_RETURN payload relayed on mainnet. Regtest does not enforce
+// standardness, so larger payloads are mined there too.
+const MaxNullDataSize = 80
+
+// NullData is an OP_RETURN output found in a block.
+type NullData struct {
+	TxID string
+	Vout int
+	// Pushes holds the values pushed after OP_RETURN, Data their
+	// concatenation.
+	Pushes [][]byte
+	Data   []byte
+}
+
+// BuildNullDataTx returns a signed transaction spending the P2PKH utxos of
+// key to an OP_RETURN output carrying data, with the remainder minus a fee
+// of feeRate koinu per kB returned to key's address.
+func BuildNullDataTx(key *doge.KeyPair, utxos []rpc.UTXO, data []byte, feeRate int64) (*doge.Tx, error) {
+	if len(utxos) == 0 {
+		return nil, errors.New("no utxos to fund the transaction")
+	}
+
+	b := txbuilder.New()
+	for _, utxo := range utxos {
+		if err := b.AddP2PKHInputKey(utxo, key); err != nil {
+			return nil, err
+		}
+	}
+	b.AddScriptOutput(doge.NullDataScript(data), 0)
+	if err := b.SetChange(key.Address().String(), feeRate); err != nil {
+		return nil, err
+	}
+
+	return b.Build()
+}
+
+// FindNullData returns the OP_RETURN outputs of every transaction in block,
+// which must have been fetched with verbosity 2.
+func FindNullData(block *rpc.Block) ([]NullData, error) {
+	var found []NullData
+	for _, tx := range block.Tx {
+		for _, out := range tx.VOut {
+			script, err := hex.DecodeString(out.ScriptPubKey.Hex)
+			if err != nil {
+				return nil, err
+			}
+
+			info := doge.ClassifyScript(script)
+			if info.Class != doge.NullDataTy {
+				continue
+			}
+			found = append(found, NullData{
+				TxID:   tx.TxID,
+				Vout:   out.N,
+				Pushes: info.Data,
+				Data:   bytes.Join(info.Data, nil),
+			})
+		}
+	}
+
+	return found, nil
+}
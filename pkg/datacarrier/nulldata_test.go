@@ -0,0 +1,50 @@
+package datacarrier
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dogecoinfoundation/dogetest/pkg/doge"
+	"github.com/dogecoinfoundation/dogetest/pkg/rpc"
+	"github.com/dogecoinfoundation/dogetest/pkg/txbuilder"
+)
+
+func TestFindNullData(t *testing.T) {
+	key := newTestKey(t)
+
+	tx, err := BuildNullDataTx(key, []rpc.UTXO{fundingUTXO(key, 10)}, []byte("such data"), txbuilder.DefaultFeeRate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.TxOut[0].Value != 0 {
+		t.Errorf("OP_RETURN output carries %d koinu", tx.TxOut[0].Value)
+	}
+
+	// An output pushing twice, next to a P2PKH output.
+	multi := &doge.Tx{Version: 1, TxIn: []*doge.TxIn{{PrevTxID: tx.TxIn[0].PrevTxID}}}
+	multi.TxOut = []*doge.TxOut{
+		{Value: doge.KoinuPerDoge, ScriptPubKey: doge.PayToPubKeyHashScript(doge.Hash160(key.PubKey()))},
+		{ScriptPubKey: doge.NewScriptBuilder().AddOp(doge.OP_RETURN).AddData([]byte("much")).AddData([]byte("wow")).Script()},
+	}
+
+	a, b := rawTxn(t, tx), rawTxn(t, multi)
+	found, err := FindNullData(&rpc.Block{Tx: []rpc.RawTxn{a, b}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 2 {
+		t.Fatalf("found %d outputs, want 2", len(found))
+	}
+	if found[0].TxID != a.TxID || found[0].Vout != 0 || string(found[0].Data) != "such data" {
+		t.Errorf("found %+v, want %q at %s:0", found[0], "such data", a.TxID)
+	}
+	if found[1].TxID != b.TxID || found[1].Vout != 1 || string(found[1].Data) != "muchwow" ||
+		len(found[1].Pushes) != 2 || !bytes.Equal(found[1].Pushes[1], []byte("wow")) {
+		t.Errorf("found %+v, want pushes much, wow at %s:1", found[1], b.TxID)
+	}
+
+	bad := rpc.RawTxn{VOut: []rpc.RawTxnVOut{{ScriptPubKey: rpc.RawTxnScriptPubKey{Hex: "zz"}}}}
+	if _, err := FindNullData(&rpc.Block{Tx: []rpc.RawTxn{bad}}); err == nil {
+		t.Error("got no error for a script that is not hex")
+	}
+}
@@ -0,0 +1,131 @@
+package datacarrier
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/dogecoinfoundation/dogetest/pkg/doge"
+	"github.com/dogecoinfoundation/dogetest/pkg/rpc"
+)
+
+// Parser reassembles inscriptions from the reveal transactions of
+// consecutive blocks. A chain may span several blocks, so the same Parser
+// must see every block from the one holding the genesis reveal onwards, in
+// height order.
+type Parser struct {
+	// pending maps the P2SH outpoint "txid:0" that the next reveal spends
+	// to the inscription it continues.
+	pending map[string]*partial
+}
+
+type partial struct {
+	ins    *Inscription
+	header bool // the chunk count and content type have been read
+	next   int  // the countdown expected on the next chunk
+}
+
+func NewParser() *Parser {
+	return &Parser{pending: map[string]*partial{}}
+}
+
+// AddBlock scans a block fetched with verbosity 2 and returns the
+// inscriptions completed in it. Malformed envelopes are dropped silently,
+// like an indexer would.
+func (p *Parser) AddBlock(block *rpc.Block) []*Inscription {
+	var completed []*Inscription
+	for i := range block.Tx {
+		if ins := p.AddTx(&block.Tx[i]); ins != nil {
+			completed = append(completed, ins)
+		}
+	}
+	return completed
+}
+
+// AddTx scans input 0 of a transaction, e.g. from GetRawTransaction or a
+// mempool, and returns the inscription if tx completes one.
+func (p *Parser) AddTx(tx *rpc.RawTxn) *Inscription {
+	if len(tx.VIn) == 0 || tx.VIn[0].ScriptSig.Hex == "" {
+		return nil
+	}
+	in := tx.VIn[0]
+
+	ops, err := doge.ParseScriptHex(in.ScriptSig.Hex)
+	if err != nil || len(ops) < 2 {
+		return nil
+	}
+	for _, op := range ops {
+		if !op.IsPush() {
+			return nil
+		}
+	}
+	// The last two pushes are the signature and the lock script.
+	pushes := ops[:len(ops)-2]
+
+	outpoint := outpointKey(in.TxID, in.VOut)
+	state, ok := p.pending[outpoint]
+	if ok {
+		delete(p.pending, outpoint)
+	} else {
+		if len(pushes) == 0 || !bytes.Equal(opValue(pushes[0]), protocolID) {
+			return nil
+		}
+		state = &partial{ins: &Inscription{Genesis: tx.TxID}}
+		pushes = pushes[1:]
+	}
+	state.ins.Reveals = append(state.ins.Reveals, tx.TxID)
+
+	if len(pushes)%2 != 0 {
+		return nil
+	}
+	for i := 0; i < len(pushes); i += 2 {
+		n, err := doge.ParseScriptNum(opValue(pushes[i]), false, 4)
+		if err != nil {
+			return nil
+		}
+		value := opValue(pushes[i+1])
+
+		if !state.header {
+			state.ins.ContentType = string(value)
+			state.next = int(n) - 1
+			state.header = true
+			continue
+		}
+		if int(n) != state.next {
+			return nil
+		}
+		state.ins.Body = append(state.ins.Body, value...)
+		state.next--
+	}
+
+	if state.header && state.next < 0 {
+		return state.ins
+	}
+	p.pending[outpointKey(tx.TxID, 0)] = state
+	return nil
+}
+
+// FindInscriptions parses the given consecutive blocks with a new Parser.
+func FindInscriptions(blocks ...*rpc.Block) []*Inscription {
+	p := NewParser()
+	var found []*Inscription
+	for _, block := range blocks {
+		found = append(found, p.AddBlock(block)...)
+	}
+	return found
+}
+
+func outpointKey(txid string, vout int) string {
+	return fmt.Sprintf("%s:%d", txid, vout)
+}
+
+// opValue returns the value a push opcode leaves on the stack, including
+// the small integer opcodes.
+func opValue(op doge.ScriptOp) []byte {
+	switch {
+	case op.Op == doge.OP_1NEGATE:
+		return []byte{0x81}
+	case op.Op >= doge.OP_1 && op.Op <= doge.OP_16:
+		return []byte{op.Op - doge.OP_1 + 1}
+	}
+	return op.Data
+}
@@ -0,0 +1,130 @@
+package doge
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrWrongNetwork = errors.New("address belongs to a different network")
+
+type AddressType int
+
+const (
+	PubKeyHashAddr AddressType = iota + 1
+	ScriptHashAddr
+)
+
+func (t AddressType) String() string {
+	switch t {
+	case PubKeyHashAddr:
+		return "pubkeyhash"
+	case ScriptHashAddr:
+		return "scripthash"
+	}
+	return fmt.Sprintf("AddressType(%d)", int(t))
+}
+
+// Address is a decoded P2PKH or P2SH address.
+type Address struct {
+	Type   AddressType
+	Hash   []byte // Hash160 of the public key or redeem script
+	Params *ChainParams
+}
+
+// NewAddressPubKeyHash returns the P2PKH address of a Hash160 public key hash.
+func NewAddressPubKeyHash(hash []byte, params *ChainParams) (*Address, error) {
+	if len(hash) != 20 {
+		return nil, fmt.Errorf("invalid hash length %d", len(hash))
+	}
+	return &Address{Type: PubKeyHashAddr, Hash: hash, Params: params}, nil
+}
+
+// NewAddressScriptHash returns the P2SH address of a Hash160 script hash.
+func NewAddressScriptHash(hash []byte, params *ChainParams) (*Address, error) {
+	if len(hash) != 20 {
+		return nil, fmt.Errorf("invalid hash length %d", len(hash))
+	}
+	return &Address{Type: ScriptHashAddr, Hash: hash, Params: params}, nil
+}
+
+// NewAddressPubKey returns the P2PKH address of a serialized public key.
+func NewAddressPubKey(pubKey []byte, params *ChainParams) *Address {
+	return &Address{Type: PubKeyHashAddr, Hash: Hash160(pubKey), Params: params}
+}
+
+// NewAddressScript returns the P2SH address of a redeem script.
+func NewAddressScript(redeemScript []byte, params *ChainParams) *Address {
+	return &Address{Type: ScriptHashAddr, Hash: Hash160(redeemScript), Params: params}
+}
+
+// String encodes the address in Base58Check.
+func (a *Address) String() string {
+	version := a.Params.PubKeyHashAddrID
+	if a.Type == ScriptHashAddr {
+		version = a.Params.ScriptHashAddrID
+	}
+	return Base58CheckEncode(version, a.Hash)
+}
+
+// Script returns the output script paying to the address.
+func (a *Address) Script() []byte {
+	if a.Type == ScriptHashAddr {
+		return PayToScriptHashScript(a.Hash)
+	}
+	return PayToPubKeyHashScript(a.Hash)
+}
+
+// DecodeAddress decodes a Base58Check address. With params set, the address
+// must belong to that network (ErrWrongNetwork otherwise). With nil params
+// any known network is accepted; testnet and regtest share the P2SH version
+// byte, so such addresses are reported as testnet.
+func DecodeAddress(address string, params *ChainParams) (*Address, error) {
+	version, hash, err := Base58CheckDecode(address)
+	if err != nil {
+		return nil, fmt.Errorf("address %q: %w", address, err)
+	}
+	if len(hash) != 20 {
+		return nil, fmt.Errorf("address %q: invalid hash length %d", address, len(hash))
+	}
+
+	candidates := AllChainParams
+	if params != nil {
+		candidates = []*ChainParams{params}
+	}
+
+	for _, p := range candidates {
+		switch version {
+		case p.PubKeyHashAddrID:
+			return &Address{Type: PubKeyHashAddr, Hash: hash, Params: p}, nil
+		case p.ScriptHashAddrID:
+			return &Address{Type: ScriptHashAddr, Hash: hash, Params: p}, nil
+		}
+	}
+
+	if params != nil {
+		for _, p := range AllChainParams {
+			if version == p.PubKeyHashAddrID || version == p.ScriptHashAddrID {
+				return nil, fmt.Errorf("address %q: %w: %s, not %s", address, ErrWrongNetwork, p.Name, params.Name)
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("address %q: unknown version byte 0x%02x", address, version)
+}
+
+// IsValidAddress reports whether address decodes for params (or any known
+// network when params is nil).
+func IsValidAddress(address string, params *ChainParams) bool {
+	_, err := DecodeAddress(address, params)
+	return err == nil
+}
+
+// PayToAddrScript returns the output script paying to a P2PKH or P2SH
+// address of any known network.
+func PayToAddrScript(address string) ([]byte, error) {
+	addr, err := DecodeAddress(address, nil)
+	if err != nil {
+		return nil, err
+	}
+	return addr.Script(), nil
+}
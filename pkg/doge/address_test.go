@@ -0,0 +1,274 @@
+package doge
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// keyOne is the private key 1, whose compressed public key hashes to
+// 751e76e8199196d454941c45d1b3a323f1433bd6.
+var keyOne = secp256k1.PrivKeyFromBytes(append(make([]byte, 31), 1))
+
+func TestBase58CheckVectors(t *testing.T) {
+	hash, _ := hex.DecodeString("751e76e8199196d454941c45d1b3a323f1433bd6")
+
+	// Bitcoin's encodings of key 1, which share the Base58Check format.
+	tests := []struct {
+		version byte
+		payload []byte
+		want    string
+	}{
+		{0x00, hash, "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH"},
+		{0x80, append(keyOne.Serialize(), 0x01), "KwDiBf89QgGbjEhKnhXJuH7LrciVrZi3qYjgd9M7rFU73sVHnoWn"},
+	}
+
+	for _, tt := range tests {
+		if got := Base58CheckEncode(tt.version, tt.payload); got != tt.want {
+			t.Errorf("Base58CheckEncode(0x%02x, %x) = %s, want %s", tt.version, tt.payload, got, tt.want)
+		}
+
+		version, payload, err := Base58CheckDecode(tt.want)
+		if err != nil {
+			t.Errorf("Base58CheckDecode(%s): %v", tt.want, err)
+			continue
+		}
+		if version != tt.version || !bytes.Equal(payload, tt.payload) {
+			t.Errorf("Base58CheckDecode(%s) = 0x%02x %x", tt.want, version, payload)
+		}
+	}
+
+	if got := Hash160(SerializePubKey(keyOne, true)); !bytes.Equal(got, hash) {
+		t.Errorf("Hash160 of key 1 = %x, want %x", got, hash)
+	}
+}
+
+func TestBase58LeadingZeros(t *testing.T) {
+	for _, data := range [][]byte{{}, {0}, {0, 0, 1}, {0, 0xff}, bytes.Repeat([]byte{0}, 5)} {
+		encoded := Base58Encode(data)
+		decoded, err := Base58Decode(encoded)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(decoded, data) {
+			t.Errorf("Base58Decode(Base58Encode(%x)) = %x", data, decoded)
+		}
+	}
+}
+
+// addressPrefixes are the leading characters of each network's encodings,
+// as listed in ChainParams.
+var addressPrefixes = map[string]struct{ p2pkh, p2sh, wif string }{
+	"main":    {"D", "9A", "Q6"},
+	"test":    {"n", "2", "c9"},
+	"regtest": {"mn", "2", "c9"},
+}
+
+func TestAddressRoundTrip(t *testing.T) {
+	pubKey := SerializePubKey(keyOne, true)
+	redeemScript := NewScriptBuilder().AddOp(OP_1).AddData(pubKey).AddOps(OP_1, OP_CHECKMULTISIG).Script()
+
+	for _, params := range AllChainParams {
+		t.Run(params.Name, func(t *testing.T) {
+			prefixes := addressPrefixes[params.Name]
+
+			for _, addr := range []*Address{NewAddressPubKey(pubKey, params), NewAddressScript(redeemScript, params)} {
+				encoded := addr.String()
+
+				want := prefixes.p2pkh
+				if addr.Type == ScriptHashAddr {
+					want = prefixes.p2sh
+				}
+				if !strings.ContainsAny(encoded[:1], want) {
+					t.Errorf("%s address %s does not start with one of %q", addr.Type, encoded, want)
+				}
+
+				decoded, err := DecodeAddress(encoded, params)
+				if err != nil {
+					t.Fatalf("DecodeAddress(%s): %v", encoded, err)
+				}
+				if decoded.Type != addr.Type || !bytes.Equal(decoded.Hash, addr.Hash) || decoded.Params != params {
+					t.Errorf("DecodeAddress(%s) = %v %x %s, want %v %x %s",
+						encoded, decoded.Type, decoded.Hash, decoded.Params.Name, addr.Type, addr.Hash, params.Name)
+				}
+				if !bytes.Equal(decoded.Script(), addr.Script()) {
+					t.Errorf("script of %s changed in the round trip", encoded)
+				}
+
+				script, err := PayToAddrScript(encoded)
+				if err != nil {
+					t.Fatalf("PayToAddrScript(%s): %v", encoded, err)
+				}
+				if info := ClassifyScript(script); !bytes.Equal(info.Hash, addr.Hash) {
+					t.Errorf("PayToAddrScript(%s) pays to %x, want %x", encoded, info.Hash, addr.Hash)
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeAddressAnyNetwork(t *testing.T) {
+	pubKey := SerializePubKey(keyOne, true)
+
+	for _, params := range AllChainParams {
+		encoded := NewAddressPubKey(pubKey, params).String()
+		decoded, err := DecodeAddress(encoded, nil)
+		if err != nil {
+			t.Fatalf("DecodeAddress(%s, nil): %v", encoded, err)
+		}
+		if decoded.Params != params {
+			t.Errorf("DecodeAddress(%s, nil) network = %s, want %s", encoded, decoded.Params.Name, params.Name)
+		}
+	}
+
+	// Testnet and regtest share the P2SH version byte.
+	encoded := NewAddressScript([]byte{OP_TRUE}, &RegTestParams).String()
+	decoded, err := DecodeAddress(encoded, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Params != &TestNetParams {
+		t.Errorf("regtest P2SH decoded as %s, want test", decoded.Params.Name)
+	}
+	if !IsValidAddress(encoded, &RegTestParams) {
+		t.Errorf("regtest P2SH %s rejected for regtest", encoded)
+	}
+}
+
+func TestDecodeAddressWrongNetwork(t *testing.T) {
+	pubKey := SerializePubKey(keyOne, true)
+
+	tests := []struct {
+		addr   *Address
+		params *ChainParams
+	}{
+		{NewAddressPubKey(pubKey, &MainNetParams), &RegTestParams},
+		{NewAddressPubKey(pubKey, &MainNetParams), &TestNetParams},
+		{NewAddressPubKey(pubKey, &RegTestParams), &MainNetParams},
+		{NewAddressPubKey(pubKey, &TestNetParams), &RegTestParams},
+		{NewAddressScript([]byte{OP_TRUE}, &MainNetParams), &RegTestParams},
+		{NewAddressScript([]byte{OP_TRUE}, &RegTestParams), &MainNetParams},
+	}
+
+	for _, tt := range tests {
+		encoded := tt.addr.String()
+		_, err := DecodeAddress(encoded, tt.params)
+		if !errors.Is(err, ErrWrongNetwork) {
+			t.Errorf("DecodeAddress(%s, %s) = %v, want ErrWrongNetwork", encoded, tt.params.Name, err)
+		}
+		if IsValidAddress(encoded, tt.params) {
+			t.Errorf("IsValidAddress(%s, %s) = true", encoded, tt.params.Name)
+		}
+	}
+}
+
+func TestDecodeAddressInvalid(t *testing.T) {
+	valid := NewAddressPubKey(SerializePubKey(keyOne, true), &MainNetParams).String()
+
+	tests := []struct {
+		name    string
+		address string
+		want    error
+	}{
+		{"bad checksum", corruptLast(valid), ErrChecksum},
+		{"invalid character 0", "0" + valid[1:], ErrInvalidBase58},
+		{"invalid character l", valid[:5] + "l" + valid[6:], ErrInvalidBase58},
+		{"too short", "1111", ErrTooShort},
+		{"empty", "", ErrTooShort},
+		{"short hash", Base58CheckEncode(MainNetParams.PubKeyHashAddrID, make([]byte, 19)), nil},
+		{"unknown version", Base58CheckEncode(0x00, make([]byte, 20)), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeAddress(tt.address, nil)
+			if err == nil {
+				t.Fatalf("DecodeAddress(%q) succeeded", tt.address)
+			}
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("DecodeAddress(%q) = %v, want %v", tt.address, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestWIFRoundTrip(t *testing.T) {
+	for _, params := range AllChainParams {
+		for _, compressed := range []bool{true, false} {
+			wif := EncodeWIF(keyOne, compressed, params)
+
+			prefixes := addressPrefixes[params.Name].wif
+			if !strings.ContainsAny(wif[:1], prefixes) {
+				t.Errorf("%s WIF %s does not start with one of %q", params.Name, wif, prefixes)
+			}
+
+			key, gotCompressed, gotParams, err := DecodeWIF(wif)
+			if err != nil {
+				t.Fatalf("DecodeWIF(%s): %v", wif, err)
+			}
+			if !bytes.Equal(key.Serialize(), keyOne.Serialize()) || gotCompressed != compressed {
+				t.Errorf("DecodeWIF(%s) = %x compressed %v", wif, key.Serialize(), gotCompressed)
+			}
+			// Testnet and regtest share the WIF version byte.
+			if gotParams.PrivateKeyID != params.PrivateKeyID {
+				t.Errorf("DecodeWIF(%s) network = %s, want %s", wif, gotParams.Name, params.Name)
+			}
+
+			pair, err := KeyPairFromWIF(wif)
+			if err != nil {
+				t.Fatal(err)
+			}
+			pair.Params = params
+			if pair.WIF() != wif {
+				t.Errorf("KeyPair WIF = %s, want %s", pair.WIF(), wif)
+			}
+		}
+	}
+}
+
+func TestWIFInvalid(t *testing.T) {
+	wif := EncodeWIF(keyOne, true, &MainNetParams)
+
+	if _, _, _, err := DecodeWIF(corruptLast(wif)); !errors.Is(err, ErrChecksum) {
+		t.Errorf("DecodeWIF with a bad checksum = %v, want ErrChecksum", err)
+	}
+	if IsValidWIF(wif, &RegTestParams) {
+		t.Errorf("mainnet WIF %s valid for regtest", wif)
+	}
+	if IsValidWIF(EncodeWIF(keyOne, true, &RegTestParams), &MainNetParams) {
+		t.Error("regtest WIF valid for mainnet")
+	}
+	if !IsValidWIF(wif, nil) {
+		t.Errorf("mainnet WIF %s invalid for any network", wif)
+	}
+
+	for _, payload := range [][]byte{
+		make([]byte, 31),
+		append(keyOne.Serialize(), 0x02),
+		append(keyOne.Serialize(), 0x01, 0x00),
+	} {
+		bad := Base58CheckEncode(MainNetParams.PrivateKeyID, payload)
+		if _, _, _, err := DecodeWIF(bad); err == nil {
+			t.Errorf("DecodeWIF accepted a %d-byte payload %x", len(payload), payload)
+		}
+	}
+
+	if _, _, _, err := DecodeWIF(Base58CheckEncode(0x80, keyOne.Serialize())); err == nil {
+		t.Error("DecodeWIF accepted Bitcoin's version byte")
+	}
+}
+
+// corruptLast changes the last character of a base58 string, which breaks
+// its checksum.
+func corruptLast(s string) string {
+	last := s[len(s)-1]
+	replacement := byte('2')
+	if last == replacement {
+		replacement = '3'
+	}
+	return s[:len(s)-1] + string(replacement)
+}
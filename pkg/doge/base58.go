@@ -0,0 +1,98 @@
+package doge
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+)
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+var (
+	ErrInvalidBase58 = errors.New("invalid base58 character")
+	ErrChecksum      = errors.New("base58check checksum mismatch")
+	ErrTooShort      = errors.New("base58check payload too short")
+)
+
+var base58Index = func() [256]int {
+	var index [256]int
+	for i := range index {
+		index[i] = -1
+	}
+	for i, c := range base58Alphabet {
+		index[c] = i
+	}
+	return index
+}()
+
+// Base58Encode encodes data with the Bitcoin base58 alphabet. Leading zero
+// bytes are encoded as leading '1's.
+func Base58Encode(data []byte) string {
+	n := new(big.Int).SetBytes(data)
+	radix := big.NewInt(58)
+	mod := new(big.Int)
+
+	var out []byte
+	for n.Sign() > 0 {
+		n.DivMod(n, radix, mod)
+		out = append(out, base58Alphabet[mod.Int64()])
+	}
+	for _, b := range data {
+		if b != 0 {
+			break
+		}
+		out = append(out, base58Alphabet[0])
+	}
+
+	return string(reverse(out))
+}
+
+// Base58Decode decodes a base58 string.
+func Base58Decode(s string) ([]byte, error) {
+	n := new(big.Int)
+	radix := big.NewInt(58)
+	for i := 0; i < len(s); i++ {
+		digit := base58Index[s[i]]
+		if digit < 0 {
+			return nil, ErrInvalidBase58
+		}
+		n.Mul(n, radix)
+		n.Add(n, big.NewInt(int64(digit)))
+	}
+
+	zeros := 0
+	for zeros < len(s) && s[zeros] == base58Alphabet[0] {
+		zeros++
+	}
+
+	return append(make([]byte, zeros), n.Bytes()...), nil
+}
+
+// Base58CheckEncode encodes version and payload with a 4-byte double-SHA256
+// checksum, as used for addresses and WIF keys.
+func Base58CheckEncode(version byte, payload []byte) string {
+	data := make([]byte, 0, 1+len(payload)+4)
+	data = append(data, version)
+	data = append(data, payload...)
+	data = append(data, DoubleSha256(data)[:4]...)
+	return Base58Encode(data)
+}
+
+// Base58CheckDecode decodes a Base58Check string into its version byte and
+// payload, verifying the checksum.
+func Base58CheckDecode(s string) (byte, []byte, error) {
+	data, err := Base58Decode(s)
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(data) < 5 {
+		return 0, nil, ErrTooShort
+	}
+
+	body, checksum := data[:len(data)-4], data[len(data)-4:]
+	if !bytes.Equal(DoubleSha256(body)[:4], checksum) {
+		return 0, nil, ErrChecksum
+	}
+
+	return body[0], body[1:], nil
+}
@@ -0,0 +1,54 @@
+package doge
+
+import "fmt"
+
+// ChainParams holds the version bytes that distinguish Dogecoin networks.
+type ChainParams struct {
+	Name             string
+	PubKeyHashAddrID byte    // P2PKH address version
+	ScriptHashAddrID byte    // P2SH address version
+	PrivateKeyID     byte    // WIF version
+	HDPrivateKeyID   [4]byte // BIP32 extended private key version
+	HDPublicKeyID    [4]byte // BIP32 extended public key version
+}
+
+var MainNetParams = ChainParams{
+	Name:             "main",
+	PubKeyHashAddrID: 0x1e,                            // D...
+	ScriptHashAddrID: 0x16,                            // 9... or A...
+	PrivateKeyID:     0x9e,                            // Q... or 6...
+	HDPrivateKeyID:   [4]byte{0x02, 0xfa, 0xc3, 0x98}, // dgpv
+	HDPublicKeyID:    [4]byte{0x02, 0xfa, 0xca, 0xfd}, // dgub
+}
+
+var TestNetParams = ChainParams{
+	Name:             "test",
+	PubKeyHashAddrID: 0x71, // n...
+	ScriptHashAddrID: 0xc4, // 2...
+	PrivateKeyID:     0xf1,
+	HDPrivateKeyID:   [4]byte{0x04, 0x35, 0x83, 0x94}, // tprv
+	HDPublicKeyID:    [4]byte{0x04, 0x35, 0x87, 0xcf}, // tpub
+}
+
+var RegTestParams = ChainParams{
+	Name:             "regtest",
+	PubKeyHashAddrID: 0x6f,                            // m... or n...
+	ScriptHashAddrID: 0xc4,                            // 2...
+	PrivateKeyID:     0xef,                            // c...
+	HDPrivateKeyID:   [4]byte{0x04, 0x35, 0x83, 0x94}, // tprv
+	HDPublicKeyID:    [4]byte{0x04, 0x35, 0x87, 0xcf}, // tpub
+}
+
+// AllChainParams lists the known networks, most specific first.
+var AllChainParams = []*ChainParams{&MainNetParams, &TestNetParams, &RegTestParams}
+
+// ChainParamsByName returns the parameters of a chain as named by
+// getblockchaininfo: "main", "test" or "regtest".
+func ChainParamsByName(name string) (*ChainParams, error) {
+	for _, params := range AllChainParams {
+		if params.Name == name {
+			return params, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown chain %q", name)
+}
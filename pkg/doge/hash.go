@@ -0,0 +1,33 @@
+package doge
+
+import (
+	"crypto/sha256"
+
+	"golang.org/x/crypto/ripemd160" //nolint:staticcheck // RIPEMD-160 is part of the address format.
+)
+
+// DoubleSha256 returns SHA256(SHA256(data)), used for txids, block hashes,
+// signature hashes and Base58Check checksums.
+func DoubleSha256(data []byte) []byte {
+	first := sha256.Sum256(data)
+	second := sha256.Sum256(first[:])
+	return second[:]
+}
+
+// Hash160 returns RIPEMD160(SHA256(data)), used for P2PKH and P2SH hashes.
+func Hash160(data []byte) []byte {
+	sha := sha256.Sum256(data)
+	hasher := ripemd160.New()
+	hasher.Write(sha[:])
+	return hasher.Sum(nil)
+}
+
+// reverse returns a reversed copy of b, converting between the internal
+// byte order of hashes and the order in which the node displays them.
+func reverse(b []byte) []byte {
+	r := make([]byte, len(b))
+	for i := range b {
+		r[i] = b[len(b)-1-i]
+	}
+	return r
+}
@@ -0,0 +1,119 @@
+package doge
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+)
+
+// SignatureInspection describes one signature found in a scriptSig.
+type SignatureInspection struct {
+	Signature []byte      // DER signature without the sighash byte
+	HashType  SigHashType // the sighash byte appended to the signature
+	SigHash   []byte      // the digest the signature commits to
+	PubKey    []byte      // the key the signature verifies against, if any
+	StrictDER bool        // strict DER encoding (BIP66)
+	LowS      bool        // S is in the lower half of the curve order
+	Valid     bool        // the signature verifies against PubKey
+}
+
+// InputInspection describes the signatures of one transaction input.
+type InputInspection struct {
+	Index        int
+	PrevOut      *TxOut
+	PrevClass    ScriptClass // template of the spent output
+	RedeemScript []byte      // for P2SH inputs
+	ScriptCode   []byte      // the script that was signed
+	Signatures   []SignatureInspection
+}
+
+// InspectInput finds the signatures in the scriptSig of input index, which
+// spends prevOut, and checks each one against the public keys in the
+// scriptSig and script code. The signed script code is the previous output
+// script, or the redeem script for P2SH.
+func InspectInput(tx *Tx, index int, prevOut *TxOut) (*InputInspection, error) {
+	if index < 0 || index >= len(tx.TxIn) {
+		return nil, fmt.Errorf("input %d out of range", index)
+	}
+
+	pushes, err := ParseScript(tx.TxIn[index].ScriptSig)
+	if err != nil {
+		return nil, fmt.Errorf("scriptSig: %w", err)
+	}
+
+	inspection := &InputInspection{
+		Index:      index,
+		PrevOut:    prevOut,
+		PrevClass:  ClassifyScript(prevOut.ScriptPubKey).Class,
+		ScriptCode: prevOut.ScriptPubKey,
+	}
+
+	if inspection.PrevClass == ScriptHashTy {
+		if len(pushes) == 0 {
+			return nil, errors.New("P2SH input without redeem script")
+		}
+		inspection.RedeemScript = pushes[len(pushes)-1].Data
+		inspection.ScriptCode = inspection.RedeemScript
+		pushes = pushes[:len(pushes)-1]
+	}
+
+	candidates := pubKeys(pushes)
+	if codeOps, err := ParseScript(inspection.ScriptCode); err == nil {
+		candidates = append(candidates, pubKeys(codeOps)...)
+	}
+
+	for _, push := range pushes {
+		if len(push.Data) < 9 || !IsDefinedHashType(push.Data) {
+			continue
+		}
+
+		der := push.Data[:len(push.Data)-1]
+		sig, err := ecdsa.ParseDERSignature(der)
+		if err != nil && !IsValidSignatureEncoding(push.Data) {
+			continue
+		}
+
+		result := SignatureInspection{
+			Signature: der,
+			HashType:  SigHashType(push.Data[len(push.Data)-1]),
+			StrictDER: IsValidSignatureEncoding(push.Data),
+		}
+		result.SigHash, err = SignatureHash(tx, index, inspection.ScriptCode, result.HashType)
+		if err != nil {
+			return nil, err
+		}
+
+		if sig != nil {
+			s := sig.S()
+			result.LowS = !s.IsOverHalfOrder()
+
+			for _, candidate := range candidates {
+				key, err := secp256k1.ParsePubKey(candidate)
+				if err != nil {
+					continue
+				}
+				if sig.Verify(result.SigHash, key) {
+					result.PubKey = candidate
+					result.Valid = true
+					break
+				}
+			}
+		}
+
+		inspection.Signatures = append(inspection.Signatures, result)
+	}
+
+	return inspection, nil
+}
+
+func pubKeys(ops []ScriptOp) [][]byte {
+	var keys [][]byte
+	for _, op := range ops {
+		if IsValidPubKeyEncoding(op.Data) {
+			keys = append(keys, op.Data)
+		}
+	}
+	return keys
+}
@@ -0,0 +1,114 @@
+package doge
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestInspectInput(t *testing.T) {
+	key, other := newTestKey(t), newTestKey(t)
+	p2pkh := &TxOut{Value: KoinuPerDoge, ScriptPubKey: PayToPubKeyHashScript(Hash160(key.PubKey()))}
+
+	tx := spendTx(1, MaxTxInSequenceNum, 0)
+	sig := signTest(t, tx, p2pkh.ScriptPubKey, key)
+	single, err := SignInput(tx, 0, p2pkh.ScriptPubKey, key.PrivateKey, SigHashSingle|SigHashAnyOneCanPay)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		scriptSig []byte
+		pubKey    []byte
+		hashType  SigHashType
+		lowS      bool
+		valid     bool
+	}{
+		{"valid", NewScriptBuilder().AddData(sig).AddData(key.PubKey()).Script(), key.PubKey(), SigHashAll, true, true},
+		{"wrong key", NewScriptBuilder().AddData(sig).AddData(other.PubKey()).Script(), nil, SigHashAll, true, false},
+		{"high S", NewScriptBuilder().AddData(highS(t, sig)).AddData(key.PubKey()).Script(), key.PubKey(), SigHashAll, false, true},
+		{"single anyonecanpay", NewScriptBuilder().AddData(single).AddData(key.PubKey()).Script(), key.PubKey(), SigHashSingle | SigHashAnyOneCanPay, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx.TxIn[0].ScriptSig = tt.scriptSig
+			inspection, err := InspectInput(tx, 0, p2pkh)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if inspection.PrevClass != PubKeyHashTy || !bytes.Equal(inspection.ScriptCode, p2pkh.ScriptPubKey) {
+				t.Errorf("class %v with script code %x, want P2PKH signing its output script", inspection.PrevClass, inspection.ScriptCode)
+			}
+			if len(inspection.Signatures) != 1 {
+				t.Fatalf("found %d signatures, want 1", len(inspection.Signatures))
+			}
+
+			got := inspection.Signatures[0]
+			if got.HashType != tt.hashType {
+				t.Errorf("HashType = %#x, want %#x", got.HashType, tt.hashType)
+			}
+			if got.LowS != tt.lowS || got.Valid != tt.valid || !got.StrictDER {
+				t.Errorf("LowS %v, Valid %v, StrictDER %v, want %v, %v, true", got.LowS, got.Valid, got.StrictDER, tt.lowS, tt.valid)
+			}
+			if !bytes.Equal(got.PubKey, tt.pubKey) {
+				t.Errorf("PubKey = %x, want %x", got.PubKey, tt.pubKey)
+			}
+
+			want, err := SignatureHash(tx, 0, p2pkh.ScriptPubKey, tt.hashType)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got.SigHash, want) {
+				t.Errorf("SigHash = %x, want %x", got.SigHash, want)
+			}
+		})
+	}
+
+	t.Run("out of range", func(t *testing.T) {
+		if _, err := InspectInput(tx, 1, p2pkh); err == nil {
+			t.Error("inspected an input the transaction does not have")
+		}
+	})
+}
+
+func TestInspectInputP2SHMultiSig(t *testing.T) {
+	keys := []*KeyPair{newTestKey(t), newTestKey(t), newTestKey(t)}
+	redeemScript, err := MultiSigScript(2, [][]byte{keys[0].PubKey(), keys[1].PubKey(), keys[2].PubKey()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	prevOut := &TxOut{Value: KoinuPerDoge, ScriptPubKey: PayToScriptHashScript(Hash160(redeemScript))}
+
+	tx := spendTx(1, MaxTxInSequenceNum, 0)
+	sig0, sig2 := signTest(t, tx, redeemScript, keys[0]), signTest(t, tx, redeemScript, keys[2])
+	// The signature of an outsider verifies against none of the keys.
+	stranger := signTest(t, tx, redeemScript, newTestKey(t))
+	tx.TxIn[0].ScriptSig = NewScriptBuilder().AddOp(OP_0).AddData(sig0).AddData(sig2).AddData(stranger).AddData(redeemScript).Script()
+
+	inspection, err := InspectInput(tx, 0, prevOut)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inspection.PrevClass != ScriptHashTy {
+		t.Errorf("PrevClass = %v, want P2SH", inspection.PrevClass)
+	}
+	if !bytes.Equal(inspection.RedeemScript, redeemScript) || !bytes.Equal(inspection.ScriptCode, redeemScript) {
+		t.Errorf("redeem script %x, script code %x, want both %x", inspection.RedeemScript, inspection.ScriptCode, redeemScript)
+	}
+
+	want := [][]byte{keys[0].PubKey(), keys[2].PubKey(), nil}
+	if len(inspection.Signatures) != len(want) {
+		t.Fatalf("found %d signatures, want %d", len(inspection.Signatures), len(want))
+	}
+	for i, sig := range inspection.Signatures {
+		if !bytes.Equal(sig.PubKey, want[i]) || sig.Valid != (want[i] != nil) {
+			t.Errorf("signature %d verifies against %x (valid %v), want %x", i, sig.PubKey, sig.Valid, want[i])
+		}
+	}
+
+	tx.TxIn[0].ScriptSig = nil
+	if _, err := InspectInput(tx, 0, prevOut); err == nil {
+		t.Error("inspected a P2SH input without a redeem script")
+	}
+}
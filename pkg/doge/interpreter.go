@@ -0,0 +1,993 @@
+package doge
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"golang.org/x/crypto/ripemd160" //nolint:staticcheck // OP_RIPEMD160 is part of the script language.
+)
+
+const (
+	maxScriptSize         = 10000
+	maxScriptElementSize  = 520
+	maxOpsPerScript       = 201
+	maxPubKeysPerMultiSig = 20
+	maxStackSize          = 1000
+
+	// LockTimeThreshold separates nLockTime heights (below) from unix
+	// timestamps (at or above).
+	LockTimeThreshold = 500_000_000
+
+	// BIP68 relative lock-time fields of TxIn.Sequence.
+	SequenceLockTimeDisableFlag = 1 << 31
+	SequenceLockTimeTypeFlag    = 1 << 22
+	SequenceLockTimeMask        = 0x0000ffff
+)
+
+// TraceStep records one opcode evaluated by VerifyScript.
+type TraceStep struct {
+	Script   string // "scriptSig", "scriptPubKey" or "redeemScript"
+	Offset   int    // byte offset of the opcode within Script
+	Op       ScriptOp
+	Executed bool     // false inside a branch that is not taken
+	Stack    [][]byte // the stack after the opcode, top last
+	AltStack [][]byte
+	Err      error // set on the opcode that failed
+}
+
+// Trace is the full execution of a scriptSig, scriptPubKey and, for P2SH,
+// the redeem script.
+type Trace struct {
+	Steps []TraceStep
+	Err   error
+}
+
+// String renders the trace one opcode per line, followed by the verdict.
+func (t *Trace) String() string {
+	var sb strings.Builder
+	for _, step := range t.Steps {
+		marker := " "
+		if !step.Executed {
+			marker = "-"
+		}
+		fmt.Fprintf(&sb, "%s %-12s %5d  %-24s [%s]", marker, step.Script, step.Offset, Disassemble(step.Op.Bytes()), formatStack(step.Stack))
+		if len(step.AltStack) > 0 {
+			fmt.Fprintf(&sb, " alt [%s]", formatStack(step.AltStack))
+		}
+		if step.Err != nil {
+			fmt.Fprintf(&sb, " error: %v", step.Err)
+		}
+		sb.WriteString("\n")
+	}
+
+	if t.Err != nil {
+		fmt.Fprintf(&sb, "result: %v\n", t.Err)
+	} else {
+		sb.WriteString("result: ok\n")
+	}
+	return sb.String()
+}
+
+func formatStack(stack [][]byte) string {
+	items := make([]string, len(stack))
+	for i, item := range stack {
+		items[i] = hex.EncodeToString(item)
+		if items[i] == "" {
+			items[i] = `""`
+		}
+	}
+	return strings.Join(items, " ")
+}
+
+// VerifyTx runs VerifyScript for every input of tx. prevOuts holds the
+// output spent by each input, in input order.
+func VerifyTx(tx *Tx, prevOuts []*TxOut, flags ScriptFlags) error {
+	if len(prevOuts) != len(tx.TxIn) {
+		return fmt.Errorf("%d previous outputs for %d inputs", len(prevOuts), len(tx.TxIn))
+	}
+
+	for i, prevOut := range prevOuts {
+		_, err := VerifyScript(tx.TxIn[i].ScriptSig, prevOut.ScriptPubKey, tx, i, flags)
+		if err != nil {
+			return fmt.Errorf("input %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// VerifyScript evaluates the scriptSig of input index of tx against the
+// scriptPubKey it spends, following Dogecoin Core's VerifyScript. The
+// returned trace is always set; its Err equals the returned error, which is
+// a ScriptError for script failures.
+func VerifyScript(scriptSig []byte, scriptPubKey []byte, tx *Tx, index int, flags ScriptFlags) (*Trace, error) {
+	trace := &Trace{}
+	if index < 0 || index >= len(tx.TxIn) {
+		trace.Err = fmt.Errorf("input %d out of range", index)
+		return trace, trace.Err
+	}
+	if _, err := tx.Serialize(); err != nil {
+		trace.Err = err
+		return trace, trace.Err
+	}
+
+	e := &engine{tx: tx, index: index, flags: flags, trace: trace}
+	trace.Err = e.verify(scriptSig, scriptPubKey)
+	return trace, trace.Err
+}
+
+type engine struct {
+	tx    *Tx
+	index int
+	flags ScriptFlags
+	trace *Trace
+}
+
+func (e *engine) verify(scriptSig []byte, scriptPubKey []byte) error {
+	if e.flags&ScriptVerifySigPushOnly != 0 && !IsPushOnly(scriptSig) {
+		return ErrScriptSigPushOnly
+	}
+
+	stack, err := e.eval(nil, scriptSig, "scriptSig")
+	if err != nil {
+		return err
+	}
+	// The redeem script runs on the stack as left by the scriptSig.
+	scriptSigStack := slices.Clone(stack)
+
+	stack, err = e.eval(stack, scriptPubKey, "scriptPubKey")
+	if err != nil {
+		return err
+	}
+	if len(stack) == 0 || !castToBool(stack[len(stack)-1]) {
+		return ErrScriptEvalFalse
+	}
+
+	if e.flags&ScriptVerifyP2SH != 0 && isPayToScriptHash(scriptPubKey) {
+		if !IsPushOnly(scriptSig) {
+			return ErrScriptSigPushOnly
+		}
+
+		stack = scriptSigStack
+		redeemScript := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		stack, err = e.eval(stack, redeemScript, "redeemScript")
+		if err != nil {
+			return err
+		}
+		if len(stack) == 0 || !castToBool(stack[len(stack)-1]) {
+			return ErrScriptEvalFalse
+		}
+	}
+
+	if e.flags&ScriptVerifyCleanStack != 0 && len(stack) != 1 {
+		return ErrScriptCleanStack
+	}
+
+	return nil
+}
+
+// machine is the state of a single EvalScript call.
+type machine struct {
+	e         *engine
+	script    []byte
+	stack     [][]byte
+	alt       [][]byte
+	cond      []bool
+	opCount   int
+	codeStart int // position after the last OP_CODESEPARATOR
+}
+
+func (e *engine) eval(stack [][]byte, script []byte, name string) ([][]byte, error) {
+	if len(script) > maxScriptSize {
+		return stack, ErrScriptSize
+	}
+
+	m := &machine{e: e, script: script, stack: stack}
+	for pc := 0; pc < len(script); {
+		executing := !slices.Contains(m.cond, false)
+
+		op, data, next, err := nextOp(script, pc)
+		if err != nil {
+			return m.stack, ErrScriptBadOpcode
+		}
+
+		err = m.execute(op, data, next, executing)
+		if err == nil && len(m.stack)+len(m.alt) > maxStackSize {
+			err = ErrScriptStackSize
+		}
+
+		e.trace.Steps = append(e.trace.Steps, TraceStep{
+			Script:   name,
+			Offset:   pc,
+			Op:       ScriptOp{Op: op, Data: data},
+			Executed: executing,
+			Stack:    slices.Clone(m.stack),
+			AltStack: slices.Clone(m.alt),
+			Err:      err,
+		})
+		if err != nil {
+			return m.stack, err
+		}
+
+		pc = next
+	}
+
+	if len(m.cond) > 0 {
+		return m.stack, ErrScriptUnbalancedConditional
+	}
+
+	return m.stack, nil
+}
+
+func isDisabledOpcode(op byte) bool {
+	switch op {
+	case OP_CAT, OP_SUBSTR, OP_LEFT, OP_RIGHT, OP_INVERT, OP_AND, OP_OR, OP_XOR,
+		OP_2MUL, OP_2DIV, OP_MUL, OP_DIV, OP_MOD, OP_LSHIFT, OP_RSHIFT:
+		return true
+	}
+	return false
+}
+
+// isMinimalPush reports whether data is pushed with the smallest possible
+// opcode (MINIMALDATA).
+func isMinimalPush(op byte, data []byte) bool {
+	switch {
+	case len(data) == 0:
+		return op == OP_0
+	case len(data) == 1 && data[0] >= 1 && data[0] <= 16:
+		return false
+	case len(data) == 1 && data[0] == 0x81:
+		return false
+	case len(data) <= 75:
+		return int(op) == len(data)
+	case len(data) <= 255:
+		return op == OP_PUSHDATA1
+	case len(data) <= 65535:
+		return op == OP_PUSHDATA2
+	}
+	return true
+}
+
+func castToBool(v []byte) bool {
+	for i, b := range v {
+		if b != 0 {
+			// Negative zero is false.
+			return i != len(v)-1 || b != 0x80
+		}
+	}
+	return false
+}
+
+func boolBytes(b bool) []byte {
+	if b {
+		return []byte{1}
+	}
+	return []byte{}
+}
+
+func (m *machine) top(i int) []byte {
+	return m.stack[len(m.stack)-i]
+}
+
+func (m *machine) pop() []byte {
+	v := m.stack[len(m.stack)-1]
+	m.stack = m.stack[:len(m.stack)-1]
+	return v
+}
+
+func (m *machine) push(v []byte) {
+	m.stack = append(m.stack, v)
+}
+
+func (m *machine) need(n int) error {
+	if len(m.stack) < n {
+		return ErrScriptInvalidStackOperation
+	}
+	return nil
+}
+
+// num decodes a stack element as a script number. Core reports malformed
+// numbers as an unknown error.
+func (m *machine) num(v []byte, maxSize int) (int64, error) {
+	n, err := ParseScriptNum(v, m.e.flags&ScriptVerifyMinimalData != 0, maxSize)
+	if err != nil {
+		return 0, ErrScriptUnknown
+	}
+	return int64(n), nil
+}
+
+func (m *machine) execute(op byte, data []byte, next int, executing bool) error {
+	flags := m.e.flags
+
+	if len(data) > maxScriptElementSize {
+		return ErrScriptPushSize
+	}
+	if op > OP_16 {
+		m.opCount++
+		if m.opCount > maxOpsPerScript {
+			return ErrScriptOpCount
+		}
+	}
+	// Disabled opcodes fail even in a branch that is not taken.
+	if isDisabledOpcode(op) {
+		return ErrScriptDisabledOpcode
+	}
+
+	if executing && op <= OP_PUSHDATA4 {
+		if flags&ScriptVerifyMinimalData != 0 && !isMinimalPush(op, data) {
+			return ErrScriptMinimalData
+		}
+		m.push(data)
+		return nil
+	}
+
+	// Conditionals are tracked even when not executing.
+	if !executing && (op < OP_IF || op > OP_ENDIF) {
+		return nil
+	}
+
+	switch op {
+	case OP_1NEGATE, OP_1, OP_2, OP_3, OP_4, OP_5, OP_6, OP_7, OP_8,
+		OP_9, OP_10, OP_11, OP_12, OP_13, OP_14, OP_15, OP_16:
+		m.push(ScriptNum(int64(op) - int64(OP_1-1)).Bytes())
+
+	case OP_NOP:
+
+	case OP_CHECKLOCKTIMEVERIFY:
+		if flags&ScriptVerifyCheckLockTimeVerify == 0 {
+			return m.upgradableNop()
+		}
+		if err := m.need(1); err != nil {
+			return err
+		}
+		// Lock times may use 5 bytes, beyond the 4-byte arithmetic limit.
+		lockTime, err := m.num(m.top(1), 5)
+		if err != nil {
+			return err
+		}
+		if lockTime < 0 {
+			return ErrScriptNegativeLockTime
+		}
+		if !m.e.checkLockTime(lockTime) {
+			return ErrScriptUnsatisfiedLockTime
+		}
+
+	case OP_CHECKSEQUENCEVERIFY:
+		if flags&ScriptVerifyCheckSequenceVerify == 0 {
+			return m.upgradableNop()
+		}
+		if err := m.need(1); err != nil {
+			return err
+		}
+		sequence, err := m.num(m.top(1), 5)
+		if err != nil {
+			return err
+		}
+		if sequence < 0 {
+			return ErrScriptNegativeLockTime
+		}
+		if sequence&SequenceLockTimeDisableFlag == 0 && !m.e.checkSequence(sequence) {
+			return ErrScriptUnsatisfiedLockTime
+		}
+
+	case OP_NOP1, OP_NOP4, OP_NOP5, OP_NOP6, OP_NOP7, OP_NOP8, OP_NOP9, OP_NOP10:
+		return m.upgradableNop()
+
+	case OP_IF, OP_NOTIF:
+		value := false
+		if executing {
+			if len(m.stack) < 1 {
+				return ErrScriptUnbalancedConditional
+			}
+			value = castToBool(m.pop())
+			if op == OP_NOTIF {
+				value = !value
+			}
+		}
+		m.cond = append(m.cond, value)
+
+	case OP_ELSE:
+		if len(m.cond) == 0 {
+			return ErrScriptUnbalancedConditional
+		}
+		m.cond[len(m.cond)-1] = !m.cond[len(m.cond)-1]
+
+	case OP_ENDIF:
+		if len(m.cond) == 0 {
+			return ErrScriptUnbalancedConditional
+		}
+		m.cond = m.cond[:len(m.cond)-1]
+
+	case OP_VERIFY:
+		if err := m.need(1); err != nil {
+			return err
+		}
+		if !castToBool(m.top(1)) {
+			return ErrScriptVerify
+		}
+		m.pop()
+
+	case OP_RETURN:
+		return ErrScriptOpReturn
+
+	case OP_TOALTSTACK:
+		if err := m.need(1); err != nil {
+			return err
+		}
+		m.alt = append(m.alt, m.pop())
+
+	case OP_FROMALTSTACK:
+		if len(m.alt) < 1 {
+			return ErrScriptInvalidAltStackOperation
+		}
+		m.push(m.alt[len(m.alt)-1])
+		m.alt = m.alt[:len(m.alt)-1]
+
+	case OP_2DROP:
+		if err := m.need(2); err != nil {
+			return err
+		}
+		m.stack = m.stack[:len(m.stack)-2]
+
+	case OP_2DUP:
+		if err := m.need(2); err != nil {
+			return err
+		}
+		a, b := m.top(2), m.top(1)
+		m.push(a)
+		m.push(b)
+
+	case OP_3DUP:
+		if err := m.need(3); err != nil {
+			return err
+		}
+		a, b, c := m.top(3), m.top(2), m.top(1)
+		m.push(a)
+		m.push(b)
+		m.push(c)
+
+	case OP_2OVER:
+		if err := m.need(4); err != nil {
+			return err
+		}
+		a, b := m.top(4), m.top(3)
+		m.push(a)
+		m.push(b)
+
+	case OP_2ROT:
+		if err := m.need(6); err != nil {
+			return err
+		}
+		a, b := m.top(6), m.top(5)
+		i := len(m.stack) - 6
+		m.stack = append(m.stack[:i], m.stack[i+2:]...)
+		m.push(a)
+		m.push(b)
+
+	case OP_2SWAP:
+		if err := m.need(4); err != nil {
+			return err
+		}
+		n := len(m.stack)
+		m.stack[n-4], m.stack[n-2] = m.stack[n-2], m.stack[n-4]
+		m.stack[n-3], m.stack[n-1] = m.stack[n-1], m.stack[n-3]
+
+	case OP_IFDUP:
+		if err := m.need(1); err != nil {
+			return err
+		}
+		if castToBool(m.top(1)) {
+			m.push(m.top(1))
+		}
+
+	case OP_DEPTH:
+		m.push(ScriptNum(len(m.stack)).Bytes())
+
+	case OP_DROP:
+		if err := m.need(1); err != nil {
+			return err
+		}
+		m.pop()
+
+	case OP_DUP:
+		if err := m.need(1); err != nil {
+			return err
+		}
+		m.push(m.top(1))
+
+	case OP_NIP:
+		if err := m.need(2); err != nil {
+			return err
+		}
+		top := m.pop()
+		m.stack[len(m.stack)-1] = top
+
+	case OP_OVER:
+		if err := m.need(2); err != nil {
+			return err
+		}
+		m.push(m.top(2))
+
+	case OP_PICK, OP_ROLL:
+		if err := m.need(2); err != nil {
+			return err
+		}
+		n, err := m.num(m.top(1), 4)
+		if err != nil {
+			return err
+		}
+		m.pop()
+		if n < 0 || n >= int64(len(m.stack)) {
+			return ErrScriptInvalidStackOperation
+		}
+		i := len(m.stack) - 1 - int(n)
+		v := m.stack[i]
+		if op == OP_ROLL {
+			m.stack = append(m.stack[:i], m.stack[i+1:]...)
+		}
+		m.push(v)
+
+	case OP_ROT:
+		if err := m.need(3); err != nil {
+			return err
+		}
+		n := len(m.stack)
+		m.stack[n-3], m.stack[n-2], m.stack[n-1] = m.stack[n-2], m.stack[n-1], m.stack[n-3]
+
+	case OP_SWAP:
+		if err := m.need(2); err != nil {
+			return err
+		}
+		n := len(m.stack)
+		m.stack[n-2], m.stack[n-1] = m.stack[n-1], m.stack[n-2]
+
+	case OP_TUCK:
+		if err := m.need(2); err != nil {
+			return err
+		}
+		n := len(m.stack)
+		top := m.stack[n-1]
+		m.stack = append(m.stack[:n-2], top, m.stack[n-2], top)
+
+	case OP_SIZE:
+		if err := m.need(1); err != nil {
+			return err
+		}
+		m.push(ScriptNum(len(m.top(1))).Bytes())
+
+	case OP_EQUAL, OP_EQUALVERIFY:
+		if err := m.need(2); err != nil {
+			return err
+		}
+		equal := bytes.Equal(m.pop(), m.pop())
+		m.push(boolBytes(equal))
+		if op == OP_EQUALVERIFY {
+			if !equal {
+				return ErrScriptEqualVerify
+			}
+			m.pop()
+		}
+
+	case OP_1ADD, OP_1SUB, OP_NEGATE, OP_ABS, OP_NOT, OP_0NOTEQUAL:
+		if err := m.need(1); err != nil {
+			return err
+		}
+		n, err := m.num(m.top(1), 4)
+		if err != nil {
+			return err
+		}
+		switch op {
+		case OP_1ADD:
+			n++
+		case OP_1SUB:
+			n--
+		case OP_NEGATE:
+			n = -n
+		case OP_ABS:
+			if n < 0 {
+				n = -n
+			}
+		case OP_NOT:
+			n = boolNum(n == 0)
+		case OP_0NOTEQUAL:
+			n = boolNum(n != 0)
+		}
+		m.pop()
+		m.push(ScriptNum(n).Bytes())
+
+	case OP_ADD, OP_SUB, OP_BOOLAND, OP_BOOLOR, OP_NUMEQUAL, OP_NUMEQUALVERIFY,
+		OP_NUMNOTEQUAL, OP_LESSTHAN, OP_GREATERTHAN, OP_LESSTHANOREQUAL,
+		OP_GREATERTHANOREQUAL, OP_MIN, OP_MAX:
+		if err := m.need(2); err != nil {
+			return err
+		}
+		a, err := m.num(m.top(2), 4)
+		if err != nil {
+			return err
+		}
+		b, err := m.num(m.top(1), 4)
+		if err != nil {
+			return err
+		}
+
+		var n int64
+		switch op {
+		case OP_ADD:
+			n = a + b
+		case OP_SUB:
+			n = a - b
+		case OP_BOOLAND:
+			n = boolNum(a != 0 && b != 0)
+		case OP_BOOLOR:
+			n = boolNum(a != 0 || b != 0)
+		case OP_NUMEQUAL, OP_NUMEQUALVERIFY:
+			n = boolNum(a == b)
+		case OP_NUMNOTEQUAL:
+			n = boolNum(a != b)
+		case OP_LESSTHAN:
+			n = boolNum(a < b)
+		case OP_GREATERTHAN:
+			n = boolNum(a > b)
+		case OP_LESSTHANOREQUAL:
+			n = boolNum(a <= b)
+		case OP_GREATERTHANOREQUAL:
+			n = boolNum(a >= b)
+		case OP_MIN:
+			n = min(a, b)
+		case OP_MAX:
+			n = max(a, b)
+		}
+		m.stack = m.stack[:len(m.stack)-2]
+		m.push(ScriptNum(n).Bytes())
+
+		if op == OP_NUMEQUALVERIFY {
+			if n == 0 {
+				return ErrScriptNumEqualVerify
+			}
+			m.pop()
+		}
+
+	case OP_WITHIN:
+		if err := m.need(3); err != nil {
+			return err
+		}
+		x, err := m.num(m.top(3), 4)
+		if err != nil {
+			return err
+		}
+		lo, err := m.num(m.top(2), 4)
+		if err != nil {
+			return err
+		}
+		hi, err := m.num(m.top(1), 4)
+		if err != nil {
+			return err
+		}
+		m.stack = m.stack[:len(m.stack)-3]
+		m.push(boolBytes(lo <= x && x < hi))
+
+	case OP_RIPEMD160, OP_SHA1, OP_SHA256, OP_HASH160, OP_HASH256:
+		if err := m.need(1); err != nil {
+			return err
+		}
+		v := m.pop()
+		var hash []byte
+		switch op {
+		case OP_RIPEMD160:
+			hasher := ripemd160.New()
+			hasher.Write(v)
+			hash = hasher.Sum(nil)
+		case OP_SHA1:
+			sum := sha1.Sum(v)
+			hash = sum[:]
+		case OP_SHA256:
+			sum := sha256.Sum256(v)
+			hash = sum[:]
+		case OP_HASH160:
+			hash = Hash160(v)
+		case OP_HASH256:
+			hash = DoubleSha256(v)
+		}
+		m.push(hash)
+
+	case OP_CODESEPARATOR:
+		m.codeStart = next
+
+	case OP_CHECKSIG, OP_CHECKSIGVERIFY:
+		if err := m.need(2); err != nil {
+			return err
+		}
+		sig, pubKey := m.top(2), m.top(1)
+
+		// A signature cannot sign itself, so it is removed from the code.
+		scriptCode := findAndDelete(m.script[m.codeStart:], pushScript(sig))
+
+		if err := m.e.checkSignatureEncoding(sig); err != nil {
+			return err
+		}
+		if err := m.e.checkPubKeyEncoding(pubKey); err != nil {
+			return err
+		}
+
+		ok := m.e.checkSig(sig, pubKey, scriptCode)
+		if !ok && flags&ScriptVerifyNullFail != 0 && len(sig) > 0 {
+			return ErrScriptSigNullFail
+		}
+
+		m.stack = m.stack[:len(m.stack)-2]
+		m.push(boolBytes(ok))
+		if op == OP_CHECKSIGVERIFY {
+			if !ok {
+				return ErrScriptCheckSigVerify
+			}
+			m.pop()
+		}
+
+	case OP_CHECKMULTISIG, OP_CHECKMULTISIGVERIFY:
+		return m.checkMultiSig(op)
+
+	default:
+		return ErrScriptBadOpcode
+	}
+
+	return nil
+}
+
+func boolNum(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func (m *machine) upgradableNop() error {
+	if m.e.flags&ScriptVerifyDiscourageUpgradableNops != 0 {
+		return ErrScriptDiscourageUpgradableNops
+	}
+	return nil
+}
+
+// checkMultiSig follows Core's OP_CHECKMULTISIG, including the extra element
+// it pops and the order in which keys and signatures are matched.
+func (m *machine) checkMultiSig(op byte) error {
+	flags := m.e.flags
+
+	i := 1
+	if err := m.need(i); err != nil {
+		return err
+	}
+	keyCount, err := m.num(m.top(i), 4)
+	if err != nil {
+		return err
+	}
+	if keyCount < 0 || keyCount > maxPubKeysPerMultiSig {
+		return ErrScriptPubKeyCount
+	}
+	m.opCount += int(keyCount)
+	if m.opCount > maxOpsPerScript {
+		return ErrScriptOpCount
+	}
+
+	i++
+	ikey := i
+	// Number of key elements left to pop, for NULLFAIL.
+	ikey2 := int(keyCount) + 2
+	i += int(keyCount)
+	if err := m.need(i); err != nil {
+		return err
+	}
+
+	sigCount, err := m.num(m.top(i), 4)
+	if err != nil {
+		return err
+	}
+	if sigCount < 0 || sigCount > keyCount {
+		return ErrScriptSigCount
+	}
+
+	i++
+	isig := i
+	i += int(sigCount)
+	if err := m.need(i); err != nil {
+		return err
+	}
+
+	scriptCode := m.script[m.codeStart:]
+	for k := 0; k < int(sigCount); k++ {
+		scriptCode = findAndDelete(scriptCode, pushScript(m.top(isig+k)))
+	}
+
+	success := true
+	for success && sigCount > 0 {
+		sig, pubKey := m.top(isig), m.top(ikey)
+
+		if err := m.e.checkSignatureEncoding(sig); err != nil {
+			return err
+		}
+		if err := m.e.checkPubKeyEncoding(pubKey); err != nil {
+			return err
+		}
+
+		if m.e.checkSig(sig, pubKey, scriptCode) {
+			isig++
+			sigCount--
+		}
+		ikey++
+		keyCount--
+
+		// More signatures left than keys means some can never match.
+		if sigCount > keyCount {
+			success = false
+		}
+	}
+
+	for ; i > 1; i-- {
+		if !success && flags&ScriptVerifyNullFail != 0 && ikey2 == 0 && len(m.top(1)) > 0 {
+			return ErrScriptSigNullFail
+		}
+		if ikey2 > 0 {
+			ikey2--
+		}
+		m.pop()
+	}
+
+	// The extra element popped because of the original off-by-one.
+	if err := m.need(1); err != nil {
+		return err
+	}
+	if flags&ScriptVerifyNullDummy != 0 && len(m.top(1)) > 0 {
+		return ErrScriptSigNullDummy
+	}
+	m.pop()
+
+	m.push(boolBytes(success))
+	if op == OP_CHECKMULTISIGVERIFY {
+		if !success {
+			return ErrScriptCheckMultiSigVerify
+		}
+		m.pop()
+	}
+
+	return nil
+}
+
+func (e *engine) checkSignatureEncoding(sig []byte) error {
+	// An empty signature is a valid way to fail CHECKSIG.
+	if len(sig) == 0 {
+		return nil
+	}
+	if e.flags&(ScriptVerifyDERSig|ScriptVerifyLowS|ScriptVerifyStrictEnc) != 0 && !IsValidSignatureEncoding(sig) {
+		return ErrScriptSigDER
+	}
+	if e.flags&ScriptVerifyLowS != 0 && !isLowS(sig) {
+		return ErrScriptSigHighS
+	}
+	if e.flags&ScriptVerifyStrictEnc != 0 && !IsDefinedHashType(sig) {
+		return ErrScriptSigHashType
+	}
+	return nil
+}
+
+func isLowS(sig []byte) bool {
+	parsed, ok := parseDERSignatureLax(sig[:len(sig)-1])
+	if !ok {
+		return false
+	}
+	s := parsed.S()
+	return !s.IsOverHalfOrder()
+}
+
+func (e *engine) checkPubKeyEncoding(pubKey []byte) error {
+	if e.flags&ScriptVerifyStrictEnc != 0 && !IsValidPubKeyEncoding(pubKey) {
+		return ErrScriptPubKeyType
+	}
+	return nil
+}
+
+func (e *engine) checkSig(sig []byte, pubKey []byte, scriptCode []byte) bool {
+	if len(sig) == 0 {
+		return false
+	}
+
+	key, err := secp256k1.ParsePubKey(pubKey)
+	if err != nil {
+		return false
+	}
+
+	parsed, ok := parseDERSignatureLax(sig[:len(sig)-1])
+	if !ok {
+		return false
+	}
+
+	// VerifyScript checked that the transaction serialises.
+	hash, err := SignatureHash(e.tx, e.index, scriptCode, SigHashType(sig[len(sig)-1]))
+	if err != nil {
+		return false
+	}
+	return parsed.Verify(hash, key)
+}
+
+func (e *engine) checkLockTime(lockTime int64) bool {
+	txLockTime := int64(e.tx.LockTime)
+
+	// Heights and timestamps cannot be compared with each other.
+	if (txLockTime < LockTimeThreshold) != (lockTime < LockTimeThreshold) {
+		return false
+	}
+	if lockTime > txLockTime {
+		return false
+	}
+
+	// A final input disables nLockTime, which would bypass the check.
+	return e.tx.TxIn[e.index].Sequence != MaxTxInSequenceNum
+}
+
+func (e *engine) checkSequence(sequence int64) bool {
+	txSequence := int64(e.tx.TxIn[e.index].Sequence)
+
+	// Relative lock times are only enforced from version 2 (BIP68).
+	if e.tx.Version < 2 {
+		return false
+	}
+	if txSequence&SequenceLockTimeDisableFlag != 0 {
+		return false
+	}
+
+	const mask = SequenceLockTimeTypeFlag | SequenceLockTimeMask
+	txSequence &= mask
+	sequence &= mask
+
+	// Blocks and time units cannot be compared with each other.
+	if (txSequence < SequenceLockTimeTypeFlag) != (sequence < SequenceLockTimeTypeFlag) {
+		return false
+	}
+	return sequence <= txSequence
+}
+
+// pushScript returns the script that pushes data, as CScript() << data does:
+// never using the small-integer opcodes.
+func pushScript(data []byte) []byte {
+	return append(pushPrefix(len(data)), data...)
+}
+
+// findAndDelete removes every occurrence of target from script that starts
+// on an opcode boundary, as CScript::FindAndDelete does.
+func findAndDelete(script []byte, target []byte) []byte {
+	if len(target) == 0 {
+		return script
+	}
+
+	var out []byte
+	found := false
+	copied, pc := 0, 0
+	for {
+		out = append(out, script[copied:pc]...)
+		for len(script)-pc >= len(target) && bytes.Equal(script[pc:pc+len(target)], target) {
+			pc += len(target)
+			found = true
+		}
+		copied = pc
+
+		if pc >= len(script) {
+			break
+		}
+		_, _, next, err := nextOp(script, pc)
+		if err != nil {
+			break
+		}
+		pc = next
+	}
+
+	if !found {
+		return script
+	}
+	return append(out, script[copied:]...)
+}
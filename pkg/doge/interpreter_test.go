@@ -0,0 +1,505 @@
+package doge
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+)
+
+// scriptTestFlags maps the flag names of Core's script_tests.json to
+// ScriptFlags. Vectors using flags Dogecoin does not have, such as WITNESS,
+// are skipped.
+var scriptTestFlags = map[string]ScriptFlags{
+	"NONE":                       ScriptVerifyNone,
+	"P2SH":                       ScriptVerifyP2SH,
+	"STRICTENC":                  ScriptVerifyStrictEnc,
+	"DERSIG":                     ScriptVerifyDERSig,
+	"LOW_S":                      ScriptVerifyLowS,
+	"NULLDUMMY":                  ScriptVerifyNullDummy,
+	"SIGPUSHONLY":                ScriptVerifySigPushOnly,
+	"MINIMALDATA":                ScriptVerifyMinimalData,
+	"DISCOURAGE_UPGRADABLE_NOPS": ScriptVerifyDiscourageUpgradableNops,
+	"CLEANSTACK":                 ScriptVerifyCleanStack,
+	"CHECKLOCKTIMEVERIFY":        ScriptVerifyCheckLockTimeVerify,
+	"CHECKSEQUENCEVERIFY":        ScriptVerifyCheckSequenceVerify,
+	"NULLFAIL":                   ScriptVerifyNullFail,
+}
+
+// scriptTestErrors maps the error names of script_tests.json to
+// ScriptError, with OK as 0.
+var scriptTestErrors = map[string]ScriptError{
+	"OK":                         0,
+	"UNKNOWN_ERROR":              ErrScriptUnknown,
+	"EVAL_FALSE":                 ErrScriptEvalFalse,
+	"OP_RETURN":                  ErrScriptOpReturn,
+	"SCRIPT_SIZE":                ErrScriptSize,
+	"PUSH_SIZE":                  ErrScriptPushSize,
+	"OP_COUNT":                   ErrScriptOpCount,
+	"STACK_SIZE":                 ErrScriptStackSize,
+	"SIG_COUNT":                  ErrScriptSigCount,
+	"PUBKEY_COUNT":               ErrScriptPubKeyCount,
+	"VERIFY":                     ErrScriptVerify,
+	"EQUALVERIFY":                ErrScriptEqualVerify,
+	"CHECKMULTISIGVERIFY":        ErrScriptCheckMultiSigVerify,
+	"CHECKSIGVERIFY":             ErrScriptCheckSigVerify,
+	"NUMEQUALVERIFY":             ErrScriptNumEqualVerify,
+	"BAD_OPCODE":                 ErrScriptBadOpcode,
+	"DISABLED_OPCODE":            ErrScriptDisabledOpcode,
+	"INVALID_STACK_OPERATION":    ErrScriptInvalidStackOperation,
+	"INVALID_ALTSTACK_OPERATION": ErrScriptInvalidAltStackOperation,
+	"UNBALANCED_CONDITIONAL":     ErrScriptUnbalancedConditional,
+	"NEGATIVE_LOCKTIME":          ErrScriptNegativeLockTime,
+	"UNSATISFIED_LOCKTIME":       ErrScriptUnsatisfiedLockTime,
+	"SIG_HASHTYPE":               ErrScriptSigHashType,
+	"SIG_DER":                    ErrScriptSigDER,
+	"MINIMALDATA":                ErrScriptMinimalData,
+	"SIG_PUSHONLY":               ErrScriptSigPushOnly,
+	"SIG_HIGH_S":                 ErrScriptSigHighS,
+	"SIG_NULLDUMMY":              ErrScriptSigNullDummy,
+	"PUBKEYTYPE":                 ErrScriptPubKeyType,
+	"CLEANSTACK":                 ErrScriptCleanStack,
+	"NULLFAIL":                   ErrScriptSigNullFail,
+	"DISCOURAGE_UPGRADABLE_NOPS": ErrScriptDiscourageUpgradableNops,
+}
+
+// parseTestFlags parses a comma-separated flag list. ok is false if a flag
+// is not known.
+func parseTestFlags(s string) (ScriptFlags, bool) {
+	var flags ScriptFlags
+	if s == "" {
+		return flags, true
+	}
+	for _, name := range strings.Split(s, ",") {
+		flag, ok := scriptTestFlags[name]
+		if !ok {
+			return 0, false
+		}
+		flags |= flag
+	}
+	return flags, true
+}
+
+// scriptTestOps holds the opcode names the test scripts use, with and
+// without the OP_ prefix, as in Core's ParseScript.
+var scriptTestOps = func() map[string]byte {
+	ops := make(map[string]byte)
+	for op, name := range opNames {
+		if op < OP_NOP && op != OP_RESERVED {
+			continue
+		}
+		ops[name] = op
+		ops[strings.TrimPrefix(name, "OP_")] = op
+	}
+	ops["NOP2"], ops["OP_NOP2"] = OP_CHECKLOCKTIMEVERIFY, OP_CHECKLOCKTIMEVERIFY
+	ops["NOP3"], ops["OP_NOP3"] = OP_CHECKSEQUENCEVERIFY, OP_CHECKSEQUENCEVERIFY
+	return ops
+}()
+
+// parseTestScript assembles the script notation of script_tests.json:
+// decimal numbers are pushed as script numbers, 0x... is inserted as raw
+// bytes, 'text' is pushed as data and anything else is an opcode name.
+func parseTestScript(s string) ([]byte, error) {
+	b := NewScriptBuilder()
+	for _, token := range strings.Fields(s) {
+		switch {
+		case isDecimal(token):
+			n, err := strconv.ParseInt(token, 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			b.AddInt64(n)
+		case strings.HasPrefix(token, "0x") && len(token) > 2:
+			data, err := hex.DecodeString(token[2:])
+			if err != nil {
+				return nil, err
+			}
+			b.AddOps(data...)
+		case len(token) >= 2 && token[0] == '\'' && token[len(token)-1] == '\'':
+			text := []byte(token[1 : len(token)-1])
+			if len(text) == 0 {
+				b.AddOp(OP_0)
+			} else {
+				// Pushed as is, even when a small-integer opcode would do.
+				b.AddOps(pushPrefix(len(text))...).AddOps(text...)
+			}
+		default:
+			op, ok := scriptTestOps[token]
+			if !ok {
+				return nil, fmt.Errorf("unknown opcode %q", token)
+			}
+			b.AddOp(op)
+		}
+	}
+	return b.Script(), nil
+}
+
+func isDecimal(s string) bool {
+	s = strings.TrimPrefix(s, "-")
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// scriptTestTx returns the spending transaction of a script_tests.json
+// vector: it spends the only output of a crediting transaction paying 0 to
+// scriptPubKey, with version 1, locktime 0 and final sequences.
+func scriptTestTx(t *testing.T, scriptSig, scriptPubKey []byte) *Tx {
+	t.Helper()
+
+	credit := &Tx{Version: 1}
+	credit.TxIn = append(credit.TxIn, &TxIn{
+		PrevTxID:  strings.Repeat("0", 64),
+		PrevIndex: 0xffffffff,
+		ScriptSig: []byte{OP_0, OP_0},
+		Sequence:  MaxTxInSequenceNum,
+	})
+	credit.TxOut = append(credit.TxOut, &TxOut{ScriptPubKey: scriptPubKey})
+
+	creditID, err := credit.TxID()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	spend := &Tx{Version: 1}
+	spend.TxIn = append(spend.TxIn, &TxIn{
+		PrevTxID:  creditID,
+		ScriptSig: scriptSig,
+		Sequence:  MaxTxInSequenceNum,
+	})
+	spend.TxOut = append(spend.TxOut, &TxOut{})
+
+	return spend
+}
+
+// TestScriptVectors runs the legacy (non-witness) vectors of Core's
+// script_tests.json through VerifyScript: [scriptSig, scriptPubKey, flags,
+// expected_scripterror, comments...].
+func TestScriptVectors(t *testing.T) {
+	data, err := os.ReadFile("testdata/script_tests.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var vectors [][]any
+	if err := json.Unmarshal(data, &vectors); err != nil {
+		t.Fatal(err)
+	}
+
+	run, skipped := 0, 0
+	for i, v := range vectors {
+		if len(v) < 4 {
+			continue // comment
+		}
+		if _, witness := v[0].([]any); witness {
+			skipped++
+			continue
+		}
+
+		sigAsm, pubKeyAsm, flagNames, errName := v[0].(string), v[1].(string), v[2].(string), v[3].(string)
+		flags, ok := parseTestFlags(flagNames)
+		want, knownErr := scriptTestErrors[errName]
+		if !ok || !knownErr {
+			skipped++
+			continue
+		}
+		run++
+
+		name := fmt.Sprintf("vector %d [%q %q %s]", i, sigAsm, pubKeyAsm, flagNames)
+		scriptSig, err := parseTestScript(sigAsm)
+		if err != nil {
+			t.Errorf("%s: scriptSig: %v", name, err)
+			continue
+		}
+		scriptPubKey, err := parseTestScript(pubKeyAsm)
+		if err != nil {
+			t.Errorf("%s: scriptPubKey: %v", name, err)
+			continue
+		}
+
+		tx := scriptTestTx(t, scriptSig, scriptPubKey)
+		_, err = VerifyScript(scriptSig, scriptPubKey, tx, 0, flags)
+
+		var got ScriptError
+		if err != nil && !errors.As(err, &got) {
+			t.Errorf("%s: error %v is not a ScriptError", name, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("%s: got %s, want %s", name, scriptErrorName(got), errName)
+		}
+	}
+
+	if run == 0 {
+		t.Fatal("no vectors in script_tests.json")
+	}
+	t.Logf("%d vectors run, %d witness or unsupported vectors skipped", run, skipped)
+}
+
+func scriptErrorName(e ScriptError) string {
+	for name, err := range scriptTestErrors {
+		if err == e {
+			return name
+		}
+	}
+	return e.Error()
+}
+
+// spendTx returns a transaction spending one output with the given version,
+// sequence and locktime, paying to an empty script.
+func spendTx(version int32, sequence uint32, lockTime uint32) *Tx {
+	tx := &Tx{Version: version, LockTime: lockTime}
+	tx.TxIn = append(tx.TxIn, &TxIn{
+		PrevTxID: "4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b",
+		Sequence: sequence,
+	})
+	tx.TxOut = append(tx.TxOut, &TxOut{Value: KoinuPerDoge, ScriptPubKey: []byte{OP_TRUE}})
+	return tx
+}
+
+func newTestKey(t *testing.T) *KeyPair {
+	t.Helper()
+	key, err := GenerateKeyPair(&RegTestParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func signTest(t *testing.T, tx *Tx, scriptCode []byte, key *KeyPair) []byte {
+	t.Helper()
+	sig, err := SignInput(tx, 0, scriptCode, key.PrivateKey, SigHashAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sig
+}
+
+// highS rewrites a DER signature with its S value negated, which verifies
+// the same but breaks the LOW_S rule.
+func highS(t *testing.T, sig []byte) []byte {
+	t.Helper()
+	parsed, err := ecdsa.ParseDERSignature(sig[:len(sig)-1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, s := parsed.R(), parsed.S()
+	s.Negate()
+
+	derInt := func(v [32]byte) []byte {
+		b := bytes.TrimLeft(v[:], "\x00")
+		if len(b) == 0 || b[0]&0x80 != 0 {
+			b = append([]byte{0}, b...)
+		}
+		return append([]byte{0x02, byte(len(b))}, b...)
+	}
+	body := append(derInt(r.Bytes()), derInt(s.Bytes())...)
+	der := append([]byte{0x30, byte(len(body))}, body...)
+	return append(der, sig[len(sig)-1])
+}
+
+func wantScriptError(t *testing.T, err error, want ScriptError) {
+	t.Helper()
+	var got ScriptError
+	if err != nil && !errors.As(err, &got) {
+		t.Fatalf("error %v is not a ScriptError", err)
+	}
+	if got != want {
+		t.Fatalf("got %s, want %s", scriptErrorName(got), scriptErrorName(want))
+	}
+}
+
+func TestVerifyP2PKH(t *testing.T) {
+	key, other := newTestKey(t), newTestKey(t)
+	prevScript := PayToPubKeyHashScript(Hash160(key.PubKey()))
+
+	tx := spendTx(1, MaxTxInSequenceNum, 0)
+	sig := signTest(t, tx, prevScript, key)
+
+	tests := []struct {
+		name      string
+		scriptSig []byte
+		flags     ScriptFlags
+		want      ScriptError
+	}{
+		{"valid", NewScriptBuilder().AddData(sig).AddData(key.PubKey()).Script(), StandardVerifyFlags, 0},
+		{"wrong key", NewScriptBuilder().AddData(sig).AddData(other.PubKey()).Script(), StandardVerifyFlags, ErrScriptEqualVerify},
+		{"wrong signature", NewScriptBuilder().AddData(signTest(t, tx, prevScript, other)).AddData(key.PubKey()).Script(), MandatoryVerifyFlags, ErrScriptEvalFalse},
+		{"missing signature", NewScriptBuilder().AddData(key.PubKey()).Script(), StandardVerifyFlags, ErrScriptInvalidStackOperation},
+		{"non-push scriptSig", NewScriptBuilder().AddData(sig).AddData(key.PubKey()).AddOp(OP_NOP).Script(), ScriptVerifySigPushOnly, ErrScriptSigPushOnly},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := VerifyScript(tt.scriptSig, prevScript, tx, 0, tt.flags)
+			wantScriptError(t, err, tt.want)
+		})
+	}
+}
+
+func TestVerifyP2SHMultiSig(t *testing.T) {
+	keys := []*KeyPair{newTestKey(t), newTestKey(t), newTestKey(t)}
+	redeemScript, err := MultiSigScript(2, [][]byte{keys[0].PubKey(), keys[1].PubKey(), keys[2].PubKey()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	prevScript := PayToScriptHashScript(Hash160(redeemScript))
+
+	tx := spendTx(1, MaxTxInSequenceNum, 0)
+	sig0, sig1, sig2 := signTest(t, tx, redeemScript, keys[0]), signTest(t, tx, redeemScript, keys[1]), signTest(t, tx, redeemScript, keys[2])
+
+	scriptSig := func(dummy byte, sigs ...[]byte) []byte {
+		b := NewScriptBuilder().AddOp(dummy)
+		for _, sig := range sigs {
+			b.AddData(sig)
+		}
+		return b.AddData(redeemScript).Script()
+	}
+
+	tests := []struct {
+		name      string
+		scriptSig []byte
+		flags     ScriptFlags
+		want      ScriptError
+	}{
+		{"first two keys", scriptSig(OP_0, sig0, sig1), StandardVerifyFlags, 0},
+		{"first and last key", scriptSig(OP_0, sig0, sig2), StandardVerifyFlags, 0},
+		{"signatures out of order", scriptSig(OP_0, sig1, sig0), MandatoryVerifyFlags, ErrScriptEvalFalse},
+		{"one signature", scriptSig(OP_0, sig0), MandatoryVerifyFlags, ErrScriptInvalidStackOperation},
+		{"non-zero dummy is policy only", scriptSig(OP_1, sig0, sig1), MandatoryVerifyFlags, 0},
+		{"non-zero dummy", scriptSig(OP_1, sig0, sig1), StandardVerifyFlags, ErrScriptSigNullDummy},
+		{"without P2SH the hash matches", scriptSig(OP_0), ScriptVerifyNone, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := VerifyScript(tt.scriptSig, prevScript, tx, 0, tt.flags)
+			wantScriptError(t, err, tt.want)
+		})
+	}
+}
+
+func TestVerifyLockTimes(t *testing.T) {
+	key := newTestKey(t)
+
+	cltv := LockTimeScript(500, key.PubKey())
+	csv := RelativeLockScript(RelativeLockBlocks(10), key.PubKey())
+
+	tests := []struct {
+		name   string
+		script []byte
+		tx     *Tx
+		want   ScriptError
+	}{
+		{"cltv matured", cltv, spendTx(1, 0, 500), 0},
+		{"cltv later locktime", cltv, spendTx(1, 0, 600), 0},
+		{"cltv too early", cltv, spendTx(1, 0, 499), ErrScriptUnsatisfiedLockTime},
+		{"cltv final sequence", cltv, spendTx(1, MaxTxInSequenceNum, 500), ErrScriptUnsatisfiedLockTime},
+		{"cltv time against height", cltv, spendTx(1, 0, LockTimeThreshold+500), ErrScriptUnsatisfiedLockTime},
+
+		{"csv matured", csv, spendTx(2, RelativeLockBlocks(10), 0), 0},
+		{"csv too early", csv, spendTx(2, RelativeLockBlocks(9), 0), ErrScriptUnsatisfiedLockTime},
+		{"csv version 1", csv, spendTx(1, RelativeLockBlocks(10), 0), ErrScriptUnsatisfiedLockTime},
+		{"csv disabled sequence", csv, spendTx(2, SequenceLockTimeDisableFlag|10, 0), ErrScriptUnsatisfiedLockTime},
+		{"csv time against blocks", csv, spendTx(2, RelativeLockTime(time.Hour), 0), ErrScriptUnsatisfiedLockTime},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prevScript := PayToScriptHashScript(Hash160(tt.script))
+			sig := signTest(t, tt.tx, tt.script, key)
+			scriptSig := NewScriptBuilder().AddData(sig).AddData(tt.script).Script()
+
+			_, err := VerifyScript(scriptSig, prevScript, tt.tx, 0, StandardVerifyFlags)
+			wantScriptError(t, err, tt.want)
+		})
+	}
+
+	t.Run("negative locktime", func(t *testing.T) {
+		script := NewScriptBuilder().AddInt64(-1).AddOps(OP_CHECKLOCKTIMEVERIFY).Script()
+		_, err := VerifyScript(nil, script, spendTx(1, 0, 0), 0, StandardVerifyFlags)
+		wantScriptError(t, err, ErrScriptNegativeLockTime)
+	})
+}
+
+// TestVerifyMandatory checks which failures are consensus rules, reported by
+// the node as mandatory-script-verify-flag-failed, and which are only
+// policy: those pass MandatoryVerifyFlags and fail StandardVerifyFlags.
+func TestVerifyMandatory(t *testing.T) {
+	key, other := newTestKey(t), newTestKey(t)
+	p2pkh := PayToPubKeyHashScript(Hash160(key.PubKey()))
+	checkSigNot := NewScriptBuilder().AddData(key.PubKey()).AddOps(OP_CHECKSIG, OP_NOT).Script()
+
+	tx := spendTx(1, MaxTxInSequenceNum, 0)
+	sig := signTest(t, tx, p2pkh, key)
+	wrongSig := signTest(t, tx, checkSigNot, other)
+
+	tests := []struct {
+		name         string
+		scriptSig    []byte
+		scriptPubKey []byte
+		mandatory    ScriptError
+		standard     ScriptError
+	}{
+		{
+			name:         "valid",
+			scriptSig:    NewScriptBuilder().AddData(sig).AddData(key.PubKey()).Script(),
+			scriptPubKey: p2pkh,
+		},
+		{
+			name:         "high S",
+			scriptSig:    NewScriptBuilder().AddData(highS(t, sig)).AddData(key.PubKey()).Script(),
+			scriptPubKey: p2pkh,
+			standard:     ErrScriptSigHighS,
+		},
+		{
+			name:         "failed signature not null",
+			scriptSig:    NewScriptBuilder().AddData(wrongSig).Script(),
+			scriptPubKey: checkSigNot,
+			standard:     ErrScriptSigNullFail,
+		},
+		{
+			name:         "failed signature null",
+			scriptSig:    NewScriptBuilder().AddOp(OP_0).Script(),
+			scriptPubKey: checkSigNot,
+		},
+		{
+			name:         "wrong signature",
+			scriptSig:    NewScriptBuilder().AddData(wrongSig).AddData(key.PubKey()).Script(),
+			scriptPubKey: p2pkh,
+			mandatory:    ErrScriptEvalFalse,
+			standard:     ErrScriptSigNullFail,
+		},
+		{
+			name:         "equalverify",
+			scriptSig:    NewScriptBuilder().AddData(sig).AddData(other.PubKey()).Script(),
+			scriptPubKey: p2pkh,
+			mandatory:    ErrScriptEqualVerify,
+			standard:     ErrScriptEqualVerify,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := VerifyScript(tt.scriptSig, tt.scriptPubKey, tx, 0, MandatoryVerifyFlags)
+			wantScriptError(t, err, tt.mandatory)
+
+			_, err = VerifyScript(tt.scriptSig, tt.scriptPubKey, tx, 0, StandardVerifyFlags)
+			wantScriptError(t, err, tt.standard)
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package doge
+
+import (
+	"fmt"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+)
+
+// KeyPair is a secp256k1 private key together with the form of its public
+// key and the network its WIF and address are encoded for.
+type KeyPair struct {
+	PrivateKey *secp256k1.PrivateKey
+	Compressed bool
+	Params     *ChainParams
+}
+
+// GenerateKeyPair creates a random key using compressed public keys, as the
+// node's wallet does.
+func GenerateKeyPair(params *ChainParams) (*KeyPair, error) {
+	key, err := secp256k1.GeneratePrivateKey()
+	if err != nil {
+		return nil, fmt.Errorf("generate key: %w", err)
+	}
+	return &KeyPair{PrivateKey: key, Compressed: true, Params: params}, nil
+}
+
+// KeyPairFromWIF imports a key in Wallet Import Format, such as the result
+// of RpcTransport.DumpPrivKey.
+func KeyPairFromWIF(wif string) (*KeyPair, error) {
+	key, compressed, params, err := DecodeWIF(wif)
+	if err != nil {
+		return nil, err
+	}
+	return &KeyPair{PrivateKey: key, Compressed: compressed, Params: params}, nil
+}
+
+// WIF exports the key in Wallet Import Format, for importprivkey.
+func (k *KeyPair) WIF() string {
+	return EncodeWIF(k.PrivateKey, k.Compressed, k.Params)
+}
+
+// PubKey returns the serialized public key.
+func (k *KeyPair) PubKey() []byte {
+	return SerializePubKey(k.PrivateKey, k.Compressed)
+}
+
+// Address returns the P2PKH address of the key on its own network.
+func (k *KeyPair) Address() *Address {
+	return k.AddressFor(k.Params)
+}
+
+// AddressFor returns the P2PKH address of the key on another network.
+func (k *KeyPair) AddressFor(params *ChainParams) *Address {
+	return NewAddressPubKey(k.PubKey(), params)
+}
+
+// Sign signs a 32-byte digest and returns the DER signature, without a
+// sighash byte. Signatures are deterministic (RFC 6979) and low-S.
+func (k *KeyPair) Sign(digest []byte) []byte {
+	return ecdsa.Sign(k.PrivateKey, digest).Serialize()
+}
+
+// Verify reports whether sig is a valid DER signature of digest by this key.
+func (k *KeyPair) Verify(digest []byte, sig []byte) bool {
+	return VerifySignature(k.PubKey(), digest, sig)
+}
+
+// VerifySignature reports whether sig is a valid DER signature of digest by
+// the serialized public key pubKey.
+func VerifySignature(pubKey []byte, digest []byte, sig []byte) bool {
+	key, err := secp256k1.ParsePubKey(pubKey)
+	if err != nil {
+		return false
+	}
+
+	parsed, err := ecdsa.ParseDERSignature(sig)
+	if err != nil {
+		return false
+	}
+
+	return parsed.Verify(digest, key)
+}
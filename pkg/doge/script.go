@@ -0,0 +1,396 @@
+package doge
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrScriptTruncated = errors.New("script truncated")
+
+// ScriptBuilder assembles a script from opcodes and minimally encoded
+// data pushes.
+type ScriptBuilder struct {
+	script []byte
+}
+
+func NewScriptBuilder() *ScriptBuilder {
+	return &ScriptBuilder{}
+}
+
+func (b *ScriptBuilder) AddOp(op byte) *ScriptBuilder {
+	b.script = append(b.script, op)
+	return b
+}
+
+func (b *ScriptBuilder) AddOps(ops ...byte) *ScriptBuilder {
+	b.script = append(b.script, ops...)
+	return b
+}
+
+// AddData pushes data using the smallest possible push opcode, as required
+// by the MINIMALDATA standardness rule.
+func (b *ScriptBuilder) AddData(data []byte) *ScriptBuilder {
+	switch {
+	case len(data) == 0:
+		b.script = append(b.script, OP_0)
+	case len(data) == 1 && data[0] >= 1 && data[0] <= 16:
+		b.script = append(b.script, OP_1+data[0]-1)
+	case len(data) == 1 && data[0] == 0x81:
+		b.script = append(b.script, OP_1NEGATE)
+	default:
+		b.script = append(b.script, pushPrefix(len(data))...)
+		b.script = append(b.script, data...)
+	}
+	return b
+}
+
+// AddInt64 pushes n as a script number.
+func (b *ScriptBuilder) AddInt64(n int64) *ScriptBuilder {
+	switch {
+	case n == 0:
+		b.script = append(b.script, OP_0)
+	case n == -1:
+		b.script = append(b.script, OP_1NEGATE)
+	case n >= 1 && n <= 16:
+		b.script = append(b.script, OP_1+byte(n)-1)
+	default:
+		b.AddData(ScriptNum(n).Bytes())
+	}
+	return b
+}
+
+func (b *ScriptBuilder) Script() []byte {
+	return append([]byte{}, b.script...)
+}
+
+func pushPrefix(n int) []byte {
+	switch {
+	case n < int(OP_PUSHDATA1):
+		return []byte{byte(n)}
+	case n <= 0xff:
+		return []byte{OP_PUSHDATA1, byte(n)}
+	case n <= 0xffff:
+		prefix := []byte{OP_PUSHDATA2, 0, 0}
+		binary.LittleEndian.PutUint16(prefix[1:], uint16(n))
+		return prefix
+	default:
+		prefix := []byte{OP_PUSHDATA4, 0, 0, 0, 0}
+		binary.LittleEndian.PutUint32(prefix[1:], uint32(n))
+		return prefix
+	}
+}
+
+// ScriptNum is an integer as encoded on the script stack: little-endian,
+// minimal length, with the sign in the top bit of the last byte.
+type ScriptNum int64
+
+func (n ScriptNum) Bytes() []byte {
+	if n == 0 {
+		return nil
+	}
+
+	negative := n < 0
+	abs := uint64(n)
+	if negative {
+		abs = uint64(-n)
+	}
+
+	var out []byte
+	for abs > 0 {
+		out = append(out, byte(abs&0xff))
+		abs >>= 8
+	}
+
+	if out[len(out)-1]&0x80 != 0 {
+		if negative {
+			out = append(out, 0x80)
+		} else {
+			out = append(out, 0x00)
+		}
+	} else if negative {
+		out[len(out)-1] |= 0x80
+	}
+
+	return out
+}
+
+// nextOp decodes the opcode at pos, returning it together with any pushed
+// data and the position of the following opcode.
+func nextOp(script []byte, pos int) (byte, []byte, int, error) {
+	op := script[pos]
+	pos++
+
+	var size int
+	switch {
+	case op < OP_PUSHDATA1:
+		size = int(op)
+	case op == OP_PUSHDATA1:
+		if pos+1 > len(script) {
+			return op, nil, pos, ErrScriptTruncated
+		}
+		size = int(script[pos])
+		pos++
+	case op == OP_PUSHDATA2:
+		if pos+2 > len(script) {
+			return op, nil, pos, ErrScriptTruncated
+		}
+		size = int(binary.LittleEndian.Uint16(script[pos:]))
+		pos += 2
+	case op == OP_PUSHDATA4:
+		if pos+4 > len(script) {
+			return op, nil, pos, ErrScriptTruncated
+		}
+		size = int(binary.LittleEndian.Uint32(script[pos:]))
+		pos += 4
+	default:
+		return op, nil, pos, nil
+	}
+
+	if size < 0 || pos+size > len(script) {
+		return op, nil, pos, ErrScriptTruncated
+	}
+
+	return op, script[pos : pos+size], pos + size, nil
+}
+
+// removeCodeSeparators strips OP_CODESEPARATOR from a script code before it
+// is hashed for a signature, leaving push data untouched.
+func removeCodeSeparators(script []byte) []byte {
+	out := make([]byte, 0, len(script))
+	for pos := 0; pos < len(script); {
+		op, _, next, err := nextOp(script, pos)
+		if err != nil {
+			return append(out, script[pos:]...)
+		}
+		if op != OP_CODESEPARATOR {
+			out = append(out, script[pos:next]...)
+		}
+		pos = next
+	}
+	return out
+}
+
+// PayToPubKeyHashScript returns OP_DUP OP_HASH160 <hash> OP_EQUALVERIFY OP_CHECKSIG.
+func PayToPubKeyHashScript(pubKeyHash []byte) []byte {
+	return NewScriptBuilder().
+		AddOps(OP_DUP, OP_HASH160).
+		AddData(pubKeyHash).
+		AddOps(OP_EQUALVERIFY, OP_CHECKSIG).
+		Script()
+}
+
+// PayToScriptHashScript returns OP_HASH160 <hash> OP_EQUAL.
+func PayToScriptHashScript(scriptHash []byte) []byte {
+	return NewScriptBuilder().
+		AddOp(OP_HASH160).
+		AddData(scriptHash).
+		AddOp(OP_EQUAL).
+		Script()
+}
+
+// MultiSigScript returns the bare m-of-n CHECKMULTISIG script for pubKeys,
+// in the given order.
+func MultiSigScript(m int, pubKeys [][]byte) ([]byte, error) {
+	if m < 1 || m > len(pubKeys) || len(pubKeys) > 16 {
+		return nil, fmt.Errorf("invalid multisig %d-of-%d", m, len(pubKeys))
+	}
+
+	b := NewScriptBuilder().AddInt64(int64(m))
+	for _, pubKey := range pubKeys {
+		b.AddData(pubKey)
+	}
+	return b.AddInt64(int64(len(pubKeys))).AddOp(OP_CHECKMULTISIG).Script(), nil
+}
+
+// NullDataScript returns OP_RETURN <data>, an unspendable data-carrier output.
+func NullDataScript(data []byte) []byte {
+	b := NewScriptBuilder().AddOp(OP_RETURN)
+	if len(data) > 0 {
+		b.AddData(data)
+	}
+	return b.Script()
+}
+
+var (
+	ErrScriptNumOverflow   = errors.New("script number overflow")
+	ErrScriptNumNonMinimal = errors.New("non-minimally encoded script number")
+)
+
+// ParseScriptNum decodes a script number from the stack. Core limits
+// arithmetic operands to 4 bytes (5 for lock times) and, under MINIMALDATA,
+// rejects encodings with superfluous bytes.
+func ParseScriptNum(data []byte, requireMinimal bool, maxSize int) (ScriptNum, error) {
+	if len(data) > maxSize {
+		return 0, ErrScriptNumOverflow
+	}
+
+	if requireMinimal && len(data) > 0 {
+		// The last byte may only be 0x00 or 0x80 if the byte before it
+		// needs its top bit for the value.
+		if data[len(data)-1]&0x7f == 0 {
+			if len(data) == 1 || data[len(data)-2]&0x80 == 0 {
+				return 0, ErrScriptNumNonMinimal
+			}
+		}
+	}
+
+	if len(data) == 0 {
+		return 0, nil
+	}
+
+	var result int64
+	for i, b := range data {
+		result |= int64(b) << (8 * i)
+	}
+
+	if data[len(data)-1]&0x80 != 0 {
+		return ScriptNum(-(result & ^(int64(0x80) << (8 * (len(data) - 1))))), nil
+	}
+
+	return ScriptNum(result), nil
+}
+
+// ScriptOp is a single parsed opcode together with the data it pushes.
+type ScriptOp struct {
+	Op   byte
+	Data []byte
+}
+
+// IsPush reports whether the opcode only pushes a value, as required of
+// every opcode in a standard scriptSig.
+func (o ScriptOp) IsPush() bool {
+	return o.Op <= OP_16
+}
+
+// Bytes re-encodes the opcode, keeping the original push opcode so that
+// parsing and assembling a script round-trips exactly.
+func (o ScriptOp) Bytes() []byte {
+	var out []byte
+	switch {
+	case o.Op > 0 && o.Op < OP_PUSHDATA1:
+		out = append(out, o.Op)
+	case o.Op == OP_PUSHDATA1:
+		out = append(out, o.Op, byte(len(o.Data)))
+	case o.Op == OP_PUSHDATA2:
+		out = append(out, o.Op, byte(len(o.Data)), byte(len(o.Data)>>8))
+	case o.Op == OP_PUSHDATA4:
+		n := len(o.Data)
+		out = append(out, o.Op, byte(n), byte(n>>8), byte(n>>16), byte(n>>24))
+	default:
+		return []byte{o.Op}
+	}
+	return append(out, o.Data...)
+}
+
+// ParseScript splits a script into opcodes and pushed data.
+func ParseScript(script []byte) ([]ScriptOp, error) {
+	var ops []ScriptOp
+	for pos := 0; pos < len(script); {
+		op, data, next, err := nextOp(script, pos)
+		if err != nil {
+			return ops, fmt.Errorf("opcode at offset %d: %w", pos, err)
+		}
+		ops = append(ops, ScriptOp{Op: op, Data: data})
+		pos = next
+	}
+	return ops, nil
+}
+
+func ParseScriptHex(s string) ([]ScriptOp, error) {
+	script, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return ParseScript(script)
+}
+
+// AssembleScript serialises parsed opcodes back into a script.
+func AssembleScript(ops []ScriptOp) []byte {
+	var script []byte
+	for _, op := range ops {
+		script = append(script, op.Bytes()...)
+	}
+	return script
+}
+
+// IsPushOnly reports whether script consists solely of push opcodes.
+func IsPushOnly(script []byte) bool {
+	ops, err := ParseScript(script)
+	if err != nil {
+		return false
+	}
+	for _, op := range ops {
+		if !op.IsPush() {
+			return false
+		}
+	}
+	return true
+}
+
+// Disassemble renders script in the asm format of the node's decodescript
+// and scriptPubKey.asm fields.
+func Disassemble(script []byte) string {
+	return disassemble(script, false)
+}
+
+// DisassembleScriptSig renders a scriptSig like the node's scriptSig.asm,
+// which marks the sighash type of pushed signatures, e.g. "3044...01" is
+// shown as "3044...[ALL]".
+func DisassembleScriptSig(script []byte) string {
+	return disassemble(script, true)
+}
+
+var sigHashNames = map[SigHashType]string{
+	SigHashAll:                          "ALL",
+	SigHashAll | SigHashAnyOneCanPay:    "ALL|ANYONECANPAY",
+	SigHashNone:                         "NONE",
+	SigHashNone | SigHashAnyOneCanPay:   "NONE|ANYONECANPAY",
+	SigHashSingle:                       "SINGLE",
+	SigHashSingle | SigHashAnyOneCanPay: "SINGLE|ANYONECANPAY",
+}
+
+func (h SigHashType) String() string {
+	if name, ok := sigHashNames[h]; ok {
+		return name
+	}
+	return fmt.Sprintf("SigHashType(0x%02x)", uint32(h))
+}
+
+func disassemble(script []byte, decodeSigHash bool) string {
+	var parts []string
+	unspendable := len(script) > 0 && script[0] == OP_RETURN
+
+	for pos := 0; pos < len(script); {
+		op, data, next, err := nextOp(script, pos)
+		if err != nil {
+			parts = append(parts, "[error]")
+			break
+		}
+		pos = next
+
+		if op > OP_PUSHDATA4 {
+			parts = append(parts, OpName(op))
+			continue
+		}
+
+		if len(data) <= 4 {
+			n, _ := ParseScriptNum(data, false, 4)
+			parts = append(parts, fmt.Sprintf("%d", n))
+			continue
+		}
+
+		if decodeSigHash && !unspendable && IsValidSignatureEncoding(data) {
+			if name, ok := sigHashNames[SigHashType(data[len(data)-1])]; ok {
+				parts = append(parts, hex.EncodeToString(data[:len(data)-1])+"["+name+"]")
+				continue
+			}
+		}
+
+		parts = append(parts, hex.EncodeToString(data))
+	}
+
+	return strings.Join(parts, " ")
+}
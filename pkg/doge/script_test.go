@@ -0,0 +1,140 @@
+package doge
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+)
+
+var (
+	testPubKey, _  = hex.DecodeString("0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798")
+	testPubKey2, _ = hex.DecodeString("02c6047f9441ed7d6d3045406e95c07cd85c778e4b8cef3ca7abac09b95c709ee5")
+	testHash, _    = hex.DecodeString("89abcdefabbaabbaabbaabbaabbaabbaabbaabba")
+)
+
+func mustMultiSig(t *testing.T, m int, pubKeys ...[]byte) []byte {
+	t.Helper()
+	script, err := MultiSigScript(m, pubKeys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return script
+}
+
+func TestDisassemble(t *testing.T) {
+	pub, pub2, hash := hex.EncodeToString(testPubKey), hex.EncodeToString(testPubKey2), hex.EncodeToString(testHash)
+
+	tests := []struct {
+		name   string
+		script []byte
+		want   string
+	}{
+		{"empty", nil, ""},
+		{"p2pkh", PayToPubKeyHashScript(testHash), "OP_DUP OP_HASH160 " + hash + " OP_EQUALVERIFY OP_CHECKSIG"},
+		{"p2sh", PayToScriptHashScript(testHash), "OP_HASH160 " + hash + " OP_EQUAL"},
+		{"p2pk", NewScriptBuilder().AddData(testPubKey).AddOp(OP_CHECKSIG).Script(), pub + " OP_CHECKSIG"},
+		{"multisig", mustMultiSig(t, 1, testPubKey, testPubKey2), "1 " + pub + " " + pub2 + " 2 OP_CHECKMULTISIG"},
+		{"nulldata", NullDataScript([]byte("hello")), "OP_RETURN 68656c6c6f"},
+		{"cltv", NewScriptBuilder().AddInt64(100).AddOps(OP_CHECKLOCKTIMEVERIFY, OP_DROP).Script(), "100 OP_CHECKLOCKTIMEVERIFY OP_DROP"},
+		{"small ints", []byte{OP_0, OP_1NEGATE, OP_1, OP_16}, "0 -1 1 16"},
+		{"short pushes are numbers", []byte{0x01, 0x0b, 0x01, 0x81, 0x02, 0xe8, 0x03}, "11 -1 1000"},
+		{"pushdata1", []byte{OP_PUSHDATA1, 0x01, 0x07}, "7"},
+		{"pushdata2", []byte{OP_PUSHDATA2, 0x01, 0x00, 0x08}, "8"},
+		{"pushdata4", []byte{OP_PUSHDATA4, 0x01, 0x00, 0x00, 0x00, 0x09}, "9"},
+
+		{"truncated push", []byte{0x02, 0x01}, "[error]"},
+		{"truncated pushdata1 length", []byte{OP_PUSHDATA1}, "[error]"},
+		{"truncated pushdata2 length", []byte{OP_DUP, OP_PUSHDATA2, 0x01}, "OP_DUP [error]"},
+		{"truncated pushdata4 data", []byte{OP_PUSHDATA4, 0xff, 0xff, 0xff, 0xff, 0x00}, "[error]"},
+		{"error stops disassembly", []byte{OP_1, 0x05, 0x01, 0x02}, "1 [error]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Disassemble(tt.script); got != tt.want {
+				t.Errorf("Disassemble(%x) = %q, want %q", tt.script, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDisassembleScriptSig(t *testing.T) {
+	key, err := GenerateKeyPair(&RegTestParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der := key.Sign(DoubleSha256([]byte("much sign")))
+
+	for _, hashType := range []SigHashType{SigHashAll, SigHashNone | SigHashAnyOneCanPay, SigHashSingle} {
+		sig := append(bytes.Clone(der), byte(hashType))
+		script := NewScriptBuilder().AddData(sig).AddData(testPubKey).Script()
+
+		want := hex.EncodeToString(der) + "[" + hashType.String() + "] " + hex.EncodeToString(testPubKey)
+		if got := DisassembleScriptSig(script); got != want {
+			t.Errorf("DisassembleScriptSig = %q, want %q", got, want)
+		}
+
+		// Without decoding, the sighash byte stays part of the hex.
+		want = hex.EncodeToString(sig) + " " + hex.EncodeToString(testPubKey)
+		if got := Disassemble(script); got != want {
+			t.Errorf("Disassemble = %q, want %q", got, want)
+		}
+	}
+
+	// An undefined sighash type is not decoded.
+	sig := append(bytes.Clone(der), 0x04)
+	script := NewScriptBuilder().AddData(sig).Script()
+	if got := DisassembleScriptSig(script); strings.Contains(got, "[") {
+		t.Errorf("DisassembleScriptSig = %q, want plain hex", got)
+	}
+}
+
+func TestParseScriptRoundTrip(t *testing.T) {
+	scripts := [][]byte{
+		PayToPubKeyHashScript(testHash),
+		mustMultiSig(t, 2, testPubKey, testPubKey2),
+		// Non-minimal pushes keep their opcode.
+		{OP_PUSHDATA1, 0x01, 0x07},
+		{OP_PUSHDATA2, 0x02, 0x00, 0xaa, 0xbb},
+		{OP_PUSHDATA4, 0x00, 0x00, 0x00, 0x00},
+		append([]byte{OP_PUSHDATA1, 0x50}, bytes.Repeat([]byte{0xcc}, 0x50)...),
+	}
+
+	for _, script := range scripts {
+		ops, err := ParseScript(script)
+		if err != nil {
+			t.Errorf("ParseScript(%x): %v", script, err)
+			continue
+		}
+		if got := AssembleScript(ops); !bytes.Equal(got, script) {
+			t.Errorf("AssembleScript(ParseScript(%x)) = %x", script, got)
+		}
+	}
+}
+
+func TestParseScriptMalformed(t *testing.T) {
+	scripts := [][]byte{
+		{0x01},
+		{0x4b, 0x00},
+		{OP_PUSHDATA1},
+		{OP_PUSHDATA1, 0x02, 0x00},
+		{OP_PUSHDATA2, 0x00},
+		{OP_PUSHDATA2, 0x03, 0x00, 0x00},
+		{OP_PUSHDATA4, 0x00, 0x00, 0x00},
+		{OP_PUSHDATA4, 0xff, 0xff, 0xff, 0x7f},
+	}
+
+	for _, script := range scripts {
+		if _, err := ParseScript(script); !errors.Is(err, ErrScriptTruncated) {
+			t.Errorf("ParseScript(%x) = %v, want ErrScriptTruncated", script, err)
+		}
+		if IsPushOnly(script) {
+			t.Errorf("IsPushOnly(%x) = true for a malformed script", script)
+		}
+		if class := ClassifyScript(script).Class; class != NonStandardTy {
+			t.Errorf("ClassifyScript(%x) = %v, want nonstandard", script, class)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package doge
+
+// ScriptFlags selects the rules VerifyScript enforces. The values match the
+// SCRIPT_VERIFY_* flags of Dogecoin Core 1.14.
+type ScriptFlags uint32
+
+const (
+	ScriptVerifyNone                     ScriptFlags = 0
+	ScriptVerifyP2SH                     ScriptFlags = 1 << 0
+	ScriptVerifyStrictEnc                ScriptFlags = 1 << 1
+	ScriptVerifyDERSig                   ScriptFlags = 1 << 2
+	ScriptVerifyLowS                     ScriptFlags = 1 << 3
+	ScriptVerifyNullDummy                ScriptFlags = 1 << 4
+	ScriptVerifySigPushOnly              ScriptFlags = 1 << 5
+	ScriptVerifyMinimalData              ScriptFlags = 1 << 6
+	ScriptVerifyDiscourageUpgradableNops ScriptFlags = 1 << 7
+	ScriptVerifyCleanStack               ScriptFlags = 1 << 8
+	ScriptVerifyCheckLockTimeVerify      ScriptFlags = 1 << 9
+	ScriptVerifyCheckSequenceVerify      ScriptFlags = 1 << 10
+	ScriptVerifyNullFail                 ScriptFlags = 1 << 14
+)
+
+// MandatoryVerifyFlags are the rules a transaction must pass to be valid at
+// all; a failure is reported by the node as
+// "mandatory-script-verify-flag-failed".
+const MandatoryVerifyFlags = ScriptVerifyP2SH
+
+// StandardVerifyFlags are the rules the node applies to transactions
+// entering its mempool. Failing only these is reported as
+// "non-mandatory-script-verify-flag".
+const StandardVerifyFlags = MandatoryVerifyFlags |
+	ScriptVerifyDERSig |
+	ScriptVerifyStrictEnc |
+	ScriptVerifyMinimalData |
+	ScriptVerifyNullDummy |
+	ScriptVerifyDiscourageUpgradableNops |
+	ScriptVerifyCleanStack |
+	ScriptVerifyNullFail |
+	ScriptVerifyCheckLockTimeVerify |
+	ScriptVerifyCheckSequenceVerify |
+	ScriptVerifyLowS
+
+// ScriptError is a script verification failure. The error strings are those
+// of the node, so they can be matched against sendrawtransaction rejections.
+type ScriptError int
+
+const (
+	ErrScriptUnknown ScriptError = iota + 1
+	ErrScriptEvalFalse
+	ErrScriptOpReturn
+
+	ErrScriptSize
+	ErrScriptPushSize
+	ErrScriptOpCount
+	ErrScriptStackSize
+	ErrScriptSigCount
+	ErrScriptPubKeyCount
+
+	ErrScriptVerify
+	ErrScriptEqualVerify
+	ErrScriptCheckMultiSigVerify
+	ErrScriptCheckSigVerify
+	ErrScriptNumEqualVerify
+
+	ErrScriptBadOpcode
+	ErrScriptDisabledOpcode
+	ErrScriptInvalidStackOperation
+	ErrScriptInvalidAltStackOperation
+	ErrScriptUnbalancedConditional
+
+	ErrScriptNegativeLockTime
+	ErrScriptUnsatisfiedLockTime
+
+	ErrScriptSigHashType
+	ErrScriptSigDER
+	ErrScriptMinimalData
+	ErrScriptSigPushOnly
+	ErrScriptSigHighS
+	ErrScriptSigNullDummy
+	ErrScriptPubKeyType
+	ErrScriptCleanStack
+	ErrScriptSigNullFail
+
+	ErrScriptDiscourageUpgradableNops
+)
+
+var scriptErrorStrings = map[ScriptError]string{
+	ErrScriptUnknown:                  "unknown error",
+	ErrScriptEvalFalse:                "Script evaluated without error but finished with a false/empty top stack element",
+	ErrScriptOpReturn:                 "OP_RETURN was encountered",
+	ErrScriptSize:                     "Script is too big",
+	ErrScriptPushSize:                 "Push value size limit exceeded",
+	ErrScriptOpCount:                  "Operation limit exceeded",
+	ErrScriptStackSize:                "Stack size limit exceeded",
+	ErrScriptSigCount:                 "Signature count negative or greater than pubkey count",
+	ErrScriptPubKeyCount:              "Pubkey count negative or limit exceeded",
+	ErrScriptVerify:                   "Script failed an OP_VERIFY operation",
+	ErrScriptEqualVerify:              "Script failed an OP_EQUALVERIFY operation",
+	ErrScriptCheckMultiSigVerify:      "Script failed an OP_CHECKMULTISIGVERIFY operation",
+	ErrScriptCheckSigVerify:           "Script failed an OP_CHECKSIGVERIFY operation",
+	ErrScriptNumEqualVerify:           "Script failed an OP_NUMEQUALVERIFY operation",
+	ErrScriptBadOpcode:                "Opcode missing or not understood",
+	ErrScriptDisabledOpcode:           "Attempted to use a disabled opcode",
+	ErrScriptInvalidStackOperation:    "Operation not valid with the current stack size",
+	ErrScriptInvalidAltStackOperation: "Operation not valid with the current altstack size",
+	ErrScriptUnbalancedConditional:    "Invalid OP_IF construction",
+	ErrScriptNegativeLockTime:         "Negative locktime",
+	ErrScriptUnsatisfiedLockTime:      "Locktime requirement not satisfied",
+	ErrScriptSigHashType:              "Signature hash type missing or not understood",
+	ErrScriptSigDER:                   "Non-canonical DER signature",
+	ErrScriptMinimalData:              "Data push larger than necessary",
+	ErrScriptSigPushOnly:              "Only non-push operators allowed in signatures",
+	ErrScriptSigHighS:                 "Non-canonical signature: S value is unnecessarily high",
+	ErrScriptSigNullDummy:             "Dummy CHECKMULTISIG argument must be zero",
+	ErrScriptPubKeyType:               "Public key is neither compressed or uncompressed",
+	ErrScriptCleanStack:               "Extra items left on stack after execution",
+	ErrScriptSigNullFail:              "Signature must be zero for failed CHECK(MULTI)SIG operation",
+	ErrScriptDiscourageUpgradableNops: "NOPx reserved for soft-fork upgrades",
+}
+
+func (e ScriptError) Error() string {
+	if s, ok := scriptErrorStrings[e]; ok {
+		return s
+	}
+	return scriptErrorStrings[ErrScriptUnknown]
+}
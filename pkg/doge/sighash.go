@@ -0,0 +1,89 @@
+package doge
+
+import (
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+)
+
+type SigHashType uint32
+
+const (
+	SigHashAll          SigHashType = 0x01
+	SigHashNone         SigHashType = 0x02
+	SigHashSingle       SigHashType = 0x03
+	SigHashAnyOneCanPay SigHashType = 0x80
+
+	sigHashMask = 0x1f
+)
+
+// SignatureHash computes the legacy (pre-segwit) signature hash that
+// Dogecoin signs for input index, with scriptCode being the script of the
+// output being spent (or the redeem script for P2SH). The result is the
+// digest in the byte order that is passed to ECDSA. It fails if tx cannot be
+// serialised.
+func SignatureHash(tx *Tx, index int, scriptCode []byte, hashType SigHashType) ([]byte, error) {
+	// Core hashes out-of-range inputs, and SIGHASH_SINGLE without a matching
+	// output, as the number one rather than failing.
+	one := make([]byte, 32)
+	one[0] = 1
+
+	if index >= len(tx.TxIn) {
+		return one, nil
+	}
+
+	txCopy := tx.Copy()
+	for i, in := range txCopy.TxIn {
+		if i == index {
+			in.ScriptSig = removeCodeSeparators(scriptCode)
+		} else {
+			in.ScriptSig = nil
+		}
+	}
+
+	switch hashType & sigHashMask {
+	case SigHashNone:
+		txCopy.TxOut = nil
+		for i, in := range txCopy.TxIn {
+			if i != index {
+				in.Sequence = 0
+			}
+		}
+	case SigHashSingle:
+		if index >= len(txCopy.TxOut) {
+			return one, nil
+		}
+		txCopy.TxOut = txCopy.TxOut[:index+1]
+		for i := 0; i < index; i++ {
+			txCopy.TxOut[i] = &TxOut{Value: -1}
+		}
+		for i, in := range txCopy.TxIn {
+			if i != index {
+				in.Sequence = 0
+			}
+		}
+	}
+
+	if hashType&SigHashAnyOneCanPay != 0 {
+		txCopy.TxIn = []*TxIn{txCopy.TxIn[index]}
+	}
+
+	data, err := txCopy.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	data = append(data, byte(hashType), byte(hashType>>8), byte(hashType>>16), byte(hashType>>24))
+
+	return DoubleSha256(data), nil
+}
+
+// SignInput signs input index of tx and returns the DER signature with the
+// sighash byte appended, ready to be pushed in a scriptSig. Signatures are
+// deterministic (RFC 6979) and always low-S.
+func SignInput(tx *Tx, index int, scriptCode []byte, key *secp256k1.PrivateKey, hashType SigHashType) ([]byte, error) {
+	hash, err := SignatureHash(tx, index, scriptCode, hashType)
+	if err != nil {
+		return nil, err
+	}
+	sig := ecdsa.Sign(key, hash)
+	return append(sig.Serialize(), byte(hashType)), nil
+}
@@ -0,0 +1,107 @@
+package doge
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+// TestSignatureHashVectors checks SignatureHash against Core's
+// sighash.json: [raw_transaction, script, input_index, hashType,
+// signature_hash], with the hash in the byte order the node displays.
+func TestSignatureHashVectors(t *testing.T) {
+	data, err := os.ReadFile("testdata/sighash.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var vectors [][]any
+	if err := json.Unmarshal(data, &vectors); err != nil {
+		t.Fatal(err)
+	}
+
+	count := 0
+	for i, v := range vectors {
+		if len(v) != 5 {
+			continue // comment
+		}
+		count++
+
+		rawTx, script, want := v[0].(string), v[1].(string), v[4].(string)
+		index := int(v[2].(float64))
+		hashType := SigHashType(uint32(int32(v[3].(float64))))
+
+		tx, err := DecodeTxHex(rawTx)
+		if err != nil {
+			t.Errorf("vector %d: decode tx: %v", i, err)
+			continue
+		}
+		scriptCode, err := hex.DecodeString(script)
+		if err != nil {
+			t.Errorf("vector %d: decode script: %v", i, err)
+			continue
+		}
+
+		hash, err := SignatureHash(tx, index, scriptCode, hashType)
+		if err != nil {
+			t.Errorf("vector %d: %v", i, err)
+			continue
+		}
+		if got := hex.EncodeToString(reverse(hash)); got != want {
+			t.Errorf("vector %d: hash = %s, want %s", i, got, want)
+		}
+	}
+
+	if count == 0 {
+		t.Fatal("no vectors in sighash.json")
+	}
+}
+
+func TestSignInputVerify(t *testing.T) {
+	key, err := GenerateKeyPair(&RegTestParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prevScript := PayToPubKeyHashScript(Hash160(key.PubKey()))
+
+	for _, hashType := range []SigHashType{
+		SigHashAll,
+		SigHashNone,
+		SigHashSingle,
+		SigHashAll | SigHashAnyOneCanPay,
+	} {
+		t.Run(hashType.String(), func(t *testing.T) {
+			tx := NewTx()
+			tx.TxIn = append(tx.TxIn, &TxIn{
+				PrevTxID: "4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b",
+				Sequence: MaxTxInSequenceNum,
+			})
+			tx.TxOut = append(tx.TxOut, &TxOut{Value: KoinuPerDoge, ScriptPubKey: prevScript})
+
+			sig, err := SignInput(tx, 0, prevScript, key.PrivateKey, hashType)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !IsValidSignatureEncoding(sig) {
+				t.Errorf("signature %x is not strict DER", sig)
+			}
+			tx.TxIn[0].ScriptSig = NewScriptBuilder().AddData(sig).AddData(key.PubKey()).Script()
+
+			if _, err := VerifyScript(tx.TxIn[0].ScriptSig, prevScript, tx, 0, StandardVerifyFlags); err != nil {
+				t.Fatalf("VerifyScript: %v", err)
+			}
+
+			// Changing what the signature commits to must invalidate it.
+			tx.TxOut[0].Value--
+			_, err = VerifyScript(tx.TxIn[0].ScriptSig, prevScript, tx, 0, StandardVerifyFlags)
+			if hashType&sigHashMask == SigHashNone {
+				if err != nil {
+					t.Fatalf("SIGHASH_NONE signature rejected after changing an output: %v", err)
+				}
+			} else if err == nil {
+				t.Fatal("signature still valid after changing an output")
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package doge
+
+import (
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+)
+
+// IsValidSignatureEncoding reports whether sig (including its trailing
+// sighash byte) is a strict DER signature as required by BIP66.
+func IsValidSignatureEncoding(sig []byte) bool {
+	// Format: 0x30 [total-length] 0x02 [R-length] [R] 0x02 [S-length] [S] [sighash]
+	if len(sig) < 9 || len(sig) > 73 {
+		return false
+	}
+	if sig[0] != 0x30 || int(sig[1]) != len(sig)-3 {
+		return false
+	}
+
+	lenR := int(sig[3])
+	if 5+lenR >= len(sig) {
+		return false
+	}
+	lenS := int(sig[5+lenR])
+	if lenR+lenS+7 != len(sig) {
+		return false
+	}
+
+	if sig[2] != 0x02 || lenR == 0 || sig[4]&0x80 != 0 {
+		return false
+	}
+	if lenR > 1 && sig[4] == 0x00 && sig[5]&0x80 == 0 {
+		return false
+	}
+
+	if sig[lenR+4] != 0x02 || lenS == 0 || sig[lenR+6]&0x80 != 0 {
+		return false
+	}
+	if lenS > 1 && sig[lenR+6] == 0x00 && sig[lenR+7]&0x80 == 0 {
+		return false
+	}
+
+	return true
+}
+
+// IsDefinedHashType reports whether the sighash byte of sig is one of the
+// six defined types (STRICTENC).
+func IsDefinedHashType(sig []byte) bool {
+	if len(sig) == 0 {
+		return false
+	}
+	_, ok := sigHashNames[SigHashType(sig[len(sig)-1])]
+	return ok
+}
+
+// IsValidPubKeyEncoding reports whether pubKey is a compressed or
+// uncompressed SEC public key (STRICTENC).
+func IsValidPubKeyEncoding(pubKey []byte) bool {
+	switch {
+	case len(pubKey) == 33 && (pubKey[0] == 0x02 || pubKey[0] == 0x03):
+		return true
+	case len(pubKey) == 65 && pubKey[0] == 0x04:
+		return true
+	}
+	return false
+}
+
+// parseDERSignatureLax parses sig (without the sighash byte) the way the
+// node does when DERSIG is not enforced: lengths, padding and trailing
+// garbage are tolerated. ok is false only if the overall structure cannot be
+// read. Values out of range yield a zero signature, which never verifies.
+func parseDERSignatureLax(sig []byte) (*ecdsa.Signature, bool) {
+	pos := 0
+
+	readLength := func() (int, bool) {
+		if pos == len(sig) {
+			return 0, false
+		}
+		lenByte := int(sig[pos])
+		pos++
+		if lenByte&0x80 == 0 {
+			return lenByte, true
+		}
+		lenByte -= 0x80
+		if pos+lenByte > len(sig) {
+			return 0, false
+		}
+		for lenByte > 0 && sig[pos] == 0 {
+			pos++
+			lenByte--
+		}
+		if lenByte >= 8 {
+			return 0, false
+		}
+		length := 0
+		for ; lenByte > 0; lenByte-- {
+			length = length<<8 + int(sig[pos])
+			pos++
+		}
+		return length, true
+	}
+
+	readInteger := func() ([]byte, bool) {
+		if pos == len(sig) || sig[pos] != 0x02 {
+			return nil, false
+		}
+		pos++
+		length, ok := readLength()
+		if !ok || length > len(sig)-pos {
+			return nil, false
+		}
+		value := sig[pos : pos+length]
+		pos += length
+		for len(value) > 0 && value[0] == 0 {
+			value = value[1:]
+		}
+		return value, true
+	}
+
+	if pos == len(sig) || sig[pos] != 0x30 {
+		return nil, false
+	}
+	pos++
+	// The sequence length is skipped, not checked.
+	if pos == len(sig) {
+		return nil, false
+	}
+	lenByte := int(sig[pos])
+	pos++
+	if lenByte&0x80 != 0 {
+		lenByte -= 0x80
+		if pos+lenByte > len(sig) {
+			return nil, false
+		}
+		pos += lenByte
+	}
+
+	rBytes, ok := readInteger()
+	if !ok {
+		return nil, false
+	}
+	sBytes, ok := readInteger()
+	if !ok {
+		return nil, false
+	}
+
+	var r, s secp256k1.ModNScalar
+	if len(rBytes) > 32 || len(sBytes) > 32 || r.SetByteSlice(rBytes) || s.SetByteSlice(sBytes) {
+		r.Zero()
+		s.Zero()
+	}
+	return ecdsa.NewSignature(&r, &s), true
+}
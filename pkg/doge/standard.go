@@ -0,0 +1,148 @@
+package doge
+
+import "bytes"
+
+// ScriptClass is the standard template a scriptPubKey matches.
+type ScriptClass int
+
+const (
+	NonStandardTy ScriptClass = iota
+	PubKeyTy
+	PubKeyHashTy
+	ScriptHashTy
+	MultiSigTy
+	NullDataTy
+)
+
+// String returns the name the node uses in scriptPubKey.type.
+func (c ScriptClass) String() string {
+	switch c {
+	case PubKeyTy:
+		return "pubkey"
+	case PubKeyHashTy:
+		return "pubkeyhash"
+	case ScriptHashTy:
+		return "scripthash"
+	case MultiSigTy:
+		return "multisig"
+	case NullDataTy:
+		return "nulldata"
+	}
+	return "nonstandard"
+}
+
+// ScriptInfo describes a classified scriptPubKey.
+type ScriptInfo struct {
+	Class        ScriptClass
+	RequiredSigs int      // signatures needed to spend, 0 for nulldata and nonstandard
+	PubKeys      [][]byte // for pubkey and multisig
+	Hash         []byte   // the pubkey hash or script hash for pubkeyhash and scripthash
+	Data         [][]byte // the pushes following OP_RETURN for nulldata
+}
+
+// ClassifyScript matches script against the standard templates recognised
+// by Dogecoin Core's Solver.
+func ClassifyScript(script []byte) ScriptInfo {
+	if isPayToScriptHash(script) {
+		return ScriptInfo{Class: ScriptHashTy, RequiredSigs: 1, Hash: script[2:22]}
+	}
+
+	if len(script) > 0 && script[0] == OP_RETURN && IsPushOnly(script[1:]) {
+		ops, _ := ParseScript(script[1:])
+		info := ScriptInfo{Class: NullDataTy}
+		for _, op := range ops {
+			info.Data = append(info.Data, pushValue(op))
+		}
+		return info
+	}
+
+	ops, err := ParseScript(script)
+	if err != nil {
+		return ScriptInfo{Class: NonStandardTy}
+	}
+
+	switch {
+	case len(ops) == 2 && isPubKey(ops[0]) && ops[1].Op == OP_CHECKSIG:
+		return ScriptInfo{Class: PubKeyTy, RequiredSigs: 1, PubKeys: [][]byte{ops[0].Data}}
+
+	case len(ops) == 5 && ops[0].Op == OP_DUP && ops[1].Op == OP_HASH160 &&
+		ops[2].Op <= OP_PUSHDATA4 && len(ops[2].Data) == 20 &&
+		ops[3].Op == OP_EQUALVERIFY && ops[4].Op == OP_CHECKSIG:
+		return ScriptInfo{Class: PubKeyHashTy, RequiredSigs: 1, Hash: ops[2].Data}
+
+	case len(ops) >= 4 && ops[len(ops)-1].Op == OP_CHECKMULTISIG:
+		return classifyMultiSig(ops)
+	}
+
+	return ScriptInfo{Class: NonStandardTy}
+}
+
+// isPayToScriptHash matches P2SH on the exact bytes, as in
+// CScript::IsPayToScriptHash.
+func isPayToScriptHash(script []byte) bool {
+	return len(script) == 23 && script[0] == OP_HASH160 && script[1] == 0x14 && script[22] == OP_EQUAL
+}
+
+func classifyMultiSig(ops []ScriptOp) ScriptInfo {
+	m, okM := smallInt(ops[0].Op)
+	n, okN := smallInt(ops[len(ops)-2].Op)
+	keys := ops[1 : len(ops)-2]
+
+	if !okM || !okN || m < 1 || n < m || len(keys) != n {
+		return ScriptInfo{Class: NonStandardTy}
+	}
+
+	info := ScriptInfo{Class: MultiSigTy, RequiredSigs: m}
+	for _, key := range keys {
+		if !isPubKey(key) {
+			return ScriptInfo{Class: NonStandardTy}
+		}
+		info.PubKeys = append(info.PubKeys, key.Data)
+	}
+	return info
+}
+
+// Addresses returns the addresses the script pays to on the given network,
+// as listed in the node's scriptPubKey.addresses.
+func (info ScriptInfo) Addresses(params *ChainParams) []string {
+	switch info.Class {
+	case PubKeyHashTy:
+		return []string{Base58CheckEncode(params.PubKeyHashAddrID, info.Hash)}
+	case ScriptHashTy:
+		return []string{Base58CheckEncode(params.ScriptHashAddrID, info.Hash)}
+	case PubKeyTy, MultiSigTy:
+		var addresses []string
+		for _, key := range info.PubKeys {
+			addresses = append(addresses, Base58CheckEncode(params.PubKeyHashAddrID, Hash160(key)))
+		}
+		return addresses
+	}
+	return nil
+}
+
+// isPubKey matches the 33-65 byte pushes Solver accepts as public keys.
+func isPubKey(op ScriptOp) bool {
+	return op.Op <= OP_PUSHDATA4 && len(op.Data) >= 33 && len(op.Data) <= 65
+}
+
+func smallInt(op byte) (int, bool) {
+	switch {
+	case op == OP_0:
+		return 0, true
+	case op >= OP_1 && op <= OP_16:
+		return int(op-OP_1) + 1, true
+	}
+	return 0, false
+}
+
+// pushValue returns the value an opcode pushes, including the small
+// integer opcodes.
+func pushValue(op ScriptOp) []byte {
+	switch {
+	case op.Op == OP_1NEGATE:
+		return []byte{0x81}
+	case op.Op >= OP_1 && op.Op <= OP_16:
+		return []byte{op.Op - OP_1 + 1}
+	}
+	return bytes.Clone(op.Data)
+}
@@ -0,0 +1,139 @@
+package doge
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestClassifyScript(t *testing.T) {
+	p2pk := NewScriptBuilder().AddData(testPubKey).AddOp(OP_CHECKSIG).Script()
+
+	tests := []struct {
+		name    string
+		script  []byte
+		class   ScriptClass
+		sigs    int
+		pubKeys [][]byte
+		hash    []byte
+		data    [][]byte
+	}{
+		{name: "p2pkh", script: PayToPubKeyHashScript(testHash), class: PubKeyHashTy, sigs: 1, hash: testHash},
+		{name: "p2sh", script: PayToScriptHashScript(testHash), class: ScriptHashTy, sigs: 1, hash: testHash},
+		{name: "p2pk", script: p2pk, class: PubKeyTy, sigs: 1, pubKeys: [][]byte{testPubKey}},
+		{
+			name:    "multisig",
+			script:  mustMultiSig(t, 2, testPubKey, testPubKey2),
+			class:   MultiSigTy,
+			sigs:    2,
+			pubKeys: [][]byte{testPubKey, testPubKey2},
+		},
+		{name: "nulldata", script: NullDataScript([]byte("hello")), class: NullDataTy, data: [][]byte{[]byte("hello")}},
+		{name: "bare op_return", script: []byte{OP_RETURN}, class: NullDataTy},
+		{name: "nulldata small int", script: []byte{OP_RETURN, OP_1NEGATE, OP_16}, class: NullDataTy, data: [][]byte{{0x81}, {16}}},
+
+		{name: "empty", script: nil, class: NonStandardTy},
+		{name: "op_true", script: []byte{OP_TRUE}, class: NonStandardTy},
+		{name: "p2pkh short hash", script: PayToPubKeyHashScript(testHash[:19]), class: NonStandardTy},
+		{name: "p2pkh trailing op", script: append(PayToPubKeyHashScript(testHash), OP_NOP), class: NonStandardTy},
+		{
+			name:   "p2sh with pushdata1",
+			script: append(append([]byte{OP_HASH160, OP_PUSHDATA1, 20}, testHash...), OP_EQUAL),
+			class:  NonStandardTy,
+		},
+		{name: "p2pk short key", script: NewScriptBuilder().AddData(testPubKey[:32]).AddOp(OP_CHECKSIG).Script(), class: NonStandardTy},
+		{
+			name:   "multisig m above n",
+			script: NewScriptBuilder().AddOp(OP_2).AddData(testPubKey).AddOps(OP_1, OP_CHECKMULTISIG).Script(),
+			class:  NonStandardTy,
+		},
+		{
+			name:   "multisig zero of one",
+			script: NewScriptBuilder().AddOp(OP_0).AddData(testPubKey).AddOps(OP_1, OP_CHECKMULTISIG).Script(),
+			class:  NonStandardTy,
+		},
+		{
+			name:   "multisig key count mismatch",
+			script: NewScriptBuilder().AddOp(OP_1).AddData(testPubKey).AddOps(OP_2, OP_CHECKMULTISIG).Script(),
+			class:  NonStandardTy,
+		},
+		{
+			name:   "multisig non-key push",
+			script: NewScriptBuilder().AddOp(OP_1).AddData(testHash).AddOps(OP_1, OP_CHECKMULTISIG).Script(),
+			class:  NonStandardTy,
+		},
+		{name: "nulldata with non-push", script: []byte{OP_RETURN, OP_DUP}, class: NonStandardTy},
+		{name: "nulldata truncated", script: []byte{OP_RETURN, 0x05, 0x01}, class: NonStandardTy},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := ClassifyScript(tt.script)
+			if info.Class != tt.class {
+				t.Fatalf("class = %v, want %v", info.Class, tt.class)
+			}
+			if info.RequiredSigs != tt.sigs {
+				t.Errorf("required sigs = %d, want %d", info.RequiredSigs, tt.sigs)
+			}
+			if !bytes.Equal(info.Hash, tt.hash) {
+				t.Errorf("hash = %x, want %x", info.Hash, tt.hash)
+			}
+			if !equalPushes(info.PubKeys, tt.pubKeys) {
+				t.Errorf("pubkeys = %x, want %x", info.PubKeys, tt.pubKeys)
+			}
+			if !equalPushes(info.Data, tt.data) {
+				t.Errorf("data = %x, want %x", info.Data, tt.data)
+			}
+		})
+	}
+}
+
+func TestScriptClassString(t *testing.T) {
+	names := map[ScriptClass]string{
+		NonStandardTy: "nonstandard",
+		PubKeyTy:      "pubkey",
+		PubKeyHashTy:  "pubkeyhash",
+		ScriptHashTy:  "scripthash",
+		MultiSigTy:    "multisig",
+		NullDataTy:    "nulldata",
+	}
+	for class, want := range names {
+		if got := class.String(); got != want {
+			t.Errorf("%d.String() = %q, want %q", int(class), got, want)
+		}
+	}
+}
+
+func TestScriptInfoAddresses(t *testing.T) {
+	for _, params := range AllChainParams {
+		info := ClassifyScript(mustMultiSig(t, 1, testPubKey, testPubKey2))
+		addresses := info.Addresses(params)
+		want := []string{
+			Base58CheckEncode(params.PubKeyHashAddrID, Hash160(testPubKey)),
+			Base58CheckEncode(params.PubKeyHashAddrID, Hash160(testPubKey2)),
+		}
+		if len(addresses) != 2 || addresses[0] != want[0] || addresses[1] != want[1] {
+			t.Errorf("%s: multisig addresses = %v, want %v", params.Name, addresses, want)
+		}
+
+		p2sh := ClassifyScript(PayToScriptHashScript(testHash)).Addresses(params)
+		if len(p2sh) != 1 || p2sh[0] != Base58CheckEncode(params.ScriptHashAddrID, testHash) {
+			t.Errorf("%s: p2sh addresses = %v", params.Name, p2sh)
+		}
+
+		if got := ClassifyScript(NullDataScript([]byte("x"))).Addresses(params); got != nil {
+			t.Errorf("%s: nulldata addresses = %v, want none", params.Name, got)
+		}
+	}
+}
+
+func equalPushes(a, b [][]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !bytes.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,41 @@
+package doge
+
+import "time"
+
+// LockTimeScript returns the redeem script
+// <lockTime> OP_CHECKLOCKTIMEVERIFY OP_DROP <pubKey> OP_CHECKSIG, spendable
+// by pubKey from a transaction whose nLockTime is at least lockTime: a block
+// height below LockTimeThreshold, a unix time from it. The spending input's
+// sequence must be below MaxTxInSequenceNum for nLockTime to apply.
+func LockTimeScript(lockTime uint32, pubKey []byte) []byte {
+	return NewScriptBuilder().
+		AddInt64(int64(lockTime)).AddOps(OP_CHECKLOCKTIMEVERIFY, OP_DROP).
+		AddData(pubKey).AddOp(OP_CHECKSIG).
+		Script()
+}
+
+// RelativeLockScript returns the redeem script
+// <sequence> OP_CHECKSEQUENCEVERIFY OP_DROP <pubKey> OP_CHECKSIG, spendable
+// by pubKey once the output has aged by the BIP68 relative lock sequence,
+// see RelativeLockBlocks and RelativeLockTime. The spending transaction must
+// have version 2 and the input a sequence of at least the same lock.
+func RelativeLockScript(sequence uint32, pubKey []byte) []byte {
+	return NewScriptBuilder().
+		AddInt64(int64(sequence)).AddOps(OP_CHECKSEQUENCEVERIFY, OP_DROP).
+		AddData(pubKey).AddOp(OP_CHECKSIG).
+		Script()
+}
+
+// RelativeLockBlocks returns the BIP68 sequence locking an input until its
+// output has n confirmations beyond the one it was mined in.
+func RelativeLockBlocks(n uint16) uint32 {
+	return uint32(n)
+}
+
+// RelativeLockTime returns the BIP68 sequence locking an input for at least
+// d after the median time past of the block before the one its output was
+// mined in. BIP68 counts in units of 512 seconds; d is rounded up.
+func RelativeLockTime(d time.Duration) uint32 {
+	units := (int64(d/time.Second) + 511) / 512
+	return SequenceLockTimeTypeFlag | uint32(min(units, SequenceLockTimeMask))
+}
@@ -0,0 +1,291 @@
+package doge
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+const (
+	KoinuPerDoge = 100_000_000
+
+	// MaxTxInSequenceNum disables nLockTime and relative locktime for an input.
+	MaxTxInSequenceNum uint32 = 0xffffffff
+)
+
+// ToKoinu converts a DOGE amount as reported by the node into koinu.
+func ToKoinu(amount float64) int64 {
+	return int64(math.Round(amount * KoinuPerDoge))
+}
+
+// ToDoge converts koinu into a DOGE amount as accepted by the node.
+func ToDoge(koinu int64) float64 {
+	return float64(koinu) / KoinuPerDoge
+}
+
+type TxIn struct {
+	PrevTxID  string // txid of the output being spent, as displayed by the node
+	PrevIndex uint32
+	ScriptSig []byte
+	Sequence  uint32
+}
+
+type TxOut struct {
+	Value        int64 // in koinu
+	ScriptPubKey []byte
+}
+
+// Tx is a Dogecoin transaction in its (pre-segwit) wire format.
+type Tx struct {
+	Version  int32
+	TxIn     []*TxIn
+	TxOut    []*TxOut
+	LockTime uint32
+}
+
+func NewTx() *Tx {
+	return &Tx{Version: 1}
+}
+
+func (tx *Tx) Copy() *Tx {
+	c := &Tx{
+		Version:  tx.Version,
+		TxIn:     make([]*TxIn, len(tx.TxIn)),
+		TxOut:    make([]*TxOut, len(tx.TxOut)),
+		LockTime: tx.LockTime,
+	}
+	for i, in := range tx.TxIn {
+		cin := *in
+		cin.ScriptSig = append([]byte{}, in.ScriptSig...)
+		c.TxIn[i] = &cin
+	}
+	for i, out := range tx.TxOut {
+		cout := *out
+		cout.ScriptPubKey = append([]byte{}, out.ScriptPubKey...)
+		c.TxOut[i] = &cout
+	}
+	return c
+}
+
+// Serialize returns the transaction in its wire format. It fails if an
+// input's PrevTxID is not a 32-byte hex txid.
+func (tx *Tx) Serialize() ([]byte, error) {
+	var buf bytes.Buffer
+
+	writeUint32(&buf, uint32(tx.Version))
+	writeVarInt(&buf, uint64(len(tx.TxIn)))
+	for i, in := range tx.TxIn {
+		prev, err := hex.DecodeString(in.PrevTxID)
+		if err != nil || len(prev) != 32 {
+			return nil, fmt.Errorf("tx: input %d: invalid previous txid %q", i, in.PrevTxID)
+		}
+		buf.Write(reverse(prev))
+		writeUint32(&buf, in.PrevIndex)
+		writeVarBytes(&buf, in.ScriptSig)
+		writeUint32(&buf, in.Sequence)
+	}
+	writeVarInt(&buf, uint64(len(tx.TxOut)))
+	for _, out := range tx.TxOut {
+		writeUint64(&buf, uint64(out.Value))
+		writeVarBytes(&buf, out.ScriptPubKey)
+	}
+	writeUint32(&buf, tx.LockTime)
+
+	return buf.Bytes(), nil
+}
+
+// Hex returns the serialised transaction as accepted by sendrawtransaction.
+func (tx *Tx) Hex() (string, error) {
+	data, err := tx.Serialize()
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(data), nil
+}
+
+// TxID returns the transaction id in the byte order displayed by the node.
+func (tx *Tx) TxID() (string, error) {
+	data, err := tx.Serialize()
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(reverse(DoubleSha256(data))), nil
+}
+
+// DecodeTxHex parses a hex-encoded transaction, e.g. from getrawtransaction.
+func DecodeTxHex(s string) (*Tx, error) {
+	data, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return DeserializeTx(data)
+}
+
+func DeserializeTx(data []byte) (*Tx, error) {
+	r := bytes.NewReader(data)
+	tx := &Tx{}
+
+	version, err := readUint32(r)
+	if err != nil {
+		return nil, err
+	}
+	tx.Version = int32(version)
+
+	inCount, err := readVarInt(r)
+	if err != nil {
+		return nil, err
+	}
+	if inCount > uint64(len(data)) {
+		return nil, fmt.Errorf("tx: implausible input count %d", inCount)
+	}
+	for i := uint64(0); i < inCount; i++ {
+		prev := make([]byte, 32)
+		if _, err := io.ReadFull(r, prev); err != nil {
+			return nil, err
+		}
+		index, err := readUint32(r)
+		if err != nil {
+			return nil, err
+		}
+		scriptSig, err := readVarBytes(r)
+		if err != nil {
+			return nil, err
+		}
+		sequence, err := readUint32(r)
+		if err != nil {
+			return nil, err
+		}
+		tx.TxIn = append(tx.TxIn, &TxIn{
+			PrevTxID:  hex.EncodeToString(reverse(prev)),
+			PrevIndex: index,
+			ScriptSig: scriptSig,
+			Sequence:  sequence,
+		})
+	}
+
+	outCount, err := readVarInt(r)
+	if err != nil {
+		return nil, err
+	}
+	if outCount > uint64(len(data)) {
+		return nil, fmt.Errorf("tx: implausible output count %d", outCount)
+	}
+	for i := uint64(0); i < outCount; i++ {
+		value, err := readUint64(r)
+		if err != nil {
+			return nil, err
+		}
+		script, err := readVarBytes(r)
+		if err != nil {
+			return nil, err
+		}
+		tx.TxOut = append(tx.TxOut, &TxOut{
+			Value:        int64(value),
+			ScriptPubKey: script,
+		})
+	}
+
+	tx.LockTime, err = readUint32(r)
+	if err != nil {
+		return nil, err
+	}
+	if r.Len() != 0 {
+		return nil, errors.New("tx: trailing data after locktime")
+	}
+
+	return tx, nil
+}
+
+func writeUint32(w *bytes.Buffer, v uint32) {
+	var b [4]byte
+	binary.LittleEndian.PutUint32(b[:], v)
+	w.Write(b[:])
+}
+
+func writeUint64(w *bytes.Buffer, v uint64) {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], v)
+	w.Write(b[:])
+}
+
+func writeVarInt(w *bytes.Buffer, v uint64) {
+	switch {
+	case v < 0xfd:
+		w.WriteByte(byte(v))
+	case v <= 0xffff:
+		w.WriteByte(0xfd)
+		var b [2]byte
+		binary.LittleEndian.PutUint16(b[:], uint16(v))
+		w.Write(b[:])
+	case v <= 0xffffffff:
+		w.WriteByte(0xfe)
+		writeUint32(w, uint32(v))
+	default:
+		w.WriteByte(0xff)
+		writeUint64(w, v)
+	}
+}
+
+func writeVarBytes(w *bytes.Buffer, data []byte) {
+	writeVarInt(w, uint64(len(data)))
+	w.Write(data)
+}
+
+func readUint32(r io.Reader) (uint32, error) {
+	var b [4]byte
+	if _, err := io.ReadFull(r, b[:]); err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint32(b[:]), nil
+}
+
+func readUint64(r io.Reader) (uint64, error) {
+	var b [8]byte
+	if _, err := io.ReadFull(r, b[:]); err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint64(b[:]), nil
+}
+
+func readVarInt(r io.Reader) (uint64, error) {
+	var b [1]byte
+	if _, err := io.ReadFull(r, b[:]); err != nil {
+		return 0, err
+	}
+
+	switch b[0] {
+	case 0xfd:
+		var v [2]byte
+		if _, err := io.ReadFull(r, v[:]); err != nil {
+			return 0, err
+		}
+		return uint64(binary.LittleEndian.Uint16(v[:])), nil
+	case 0xfe:
+		v, err := readUint32(r)
+		return uint64(v), err
+	case 0xff:
+		return readUint64(r)
+	}
+
+	return uint64(b[0]), nil
+}
+
+func readVarBytes(r *bytes.Reader) ([]byte, error) {
+	n, err := readVarInt(r)
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(r.Len()) {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	data := make([]byte, n)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
@@ -0,0 +1,60 @@
+package doge
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSerializeRoundTrip(t *testing.T) {
+	tx := NewTx()
+	tx.TxIn = append(tx.TxIn, &TxIn{
+		PrevTxID:  "4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b",
+		PrevIndex: 1,
+		ScriptSig: []byte{OP_1},
+		Sequence:  MaxTxInSequenceNum - 1,
+	})
+	tx.TxOut = append(tx.TxOut, &TxOut{Value: 5 * KoinuPerDoge, ScriptPubKey: []byte{OP_TRUE}})
+	tx.LockTime = 100
+
+	data, err := tx.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := DeserializeTx(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := decoded.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, again) {
+		t.Errorf("round trip changed the transaction:\n%x\n%x", data, again)
+	}
+	if decoded.TxIn[0].PrevTxID != tx.TxIn[0].PrevTxID {
+		t.Errorf("PrevTxID = %s, want %s", decoded.TxIn[0].PrevTxID, tx.TxIn[0].PrevTxID)
+	}
+}
+
+func TestSerializeInvalidPrevTxID(t *testing.T) {
+	for _, prev := range []string{
+		"",
+		"not hex",
+		"4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda3",
+		"4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b00",
+	} {
+		tx := NewTx()
+		tx.TxIn = append(tx.TxIn, &TxIn{PrevTxID: prev})
+
+		if _, err := tx.Serialize(); err == nil {
+			t.Errorf("Serialize with PrevTxID %q succeeded", prev)
+		}
+		if _, err := tx.TxID(); err == nil {
+			t.Errorf("TxID with PrevTxID %q succeeded", prev)
+		}
+		if _, err := SignatureHash(tx, 0, nil, SigHashAll); err == nil {
+			t.Errorf("SignatureHash with PrevTxID %q succeeded", prev)
+		}
+	}
+}
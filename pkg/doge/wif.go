@@ -0,0 +1,69 @@
+package doge
+
+import (
+	"fmt"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// DecodeWIF decodes a private key in Wallet Import Format, as returned by
+// dumpprivkey, reporting whether its public key is used in compressed form
+// and which network it belongs to.
+func DecodeWIF(wif string) (*secp256k1.PrivateKey, bool, *ChainParams, error) {
+	version, payload, err := Base58CheckDecode(wif)
+	if err != nil {
+		return nil, false, nil, err
+	}
+
+	var params *ChainParams
+	for _, p := range AllChainParams {
+		if p.PrivateKeyID == version {
+			params = p
+			break
+		}
+	}
+	if params == nil {
+		return nil, false, nil, fmt.Errorf("wif: unknown version byte 0x%02x", version)
+	}
+
+	compressed := false
+	switch {
+	case len(payload) == 33 && payload[32] == 0x01:
+		compressed = true
+		payload = payload[:32]
+	case len(payload) != 32:
+		return nil, false, nil, fmt.Errorf("wif: invalid key length %d", len(payload))
+	}
+
+	return secp256k1.PrivKeyFromBytes(payload), compressed, params, nil
+}
+
+// EncodeWIF encodes key in Wallet Import Format for params, as accepted by
+// importprivkey. compressed selects whether the key's address is derived
+// from the compressed public key.
+func EncodeWIF(key *secp256k1.PrivateKey, compressed bool, params *ChainParams) string {
+	payload := key.Serialize()
+	if compressed {
+		payload = append(payload, 0x01)
+	}
+	return Base58CheckEncode(params.PrivateKeyID, payload)
+}
+
+// IsValidWIF reports whether wif decodes to a key for params (or any known
+// network when params is nil).
+func IsValidWIF(wif string, params *ChainParams) bool {
+	_, _, keyParams, err := DecodeWIF(wif)
+	if err != nil {
+		return false
+	}
+	return params == nil || keyParams == params
+}
+
+// SerializePubKey returns the public key of key in the form selected by
+// compressed.
+func SerializePubKey(key *secp256k1.PrivateKey, compressed bool) []byte {
+	if compressed {
+		return key.PubKey().SerializeCompressed()
+	}
+	return key.PubKey().SerializeUncompressed()
+}
@@ -0,0 +1,69 @@
+package dogetest
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/dogecoinfoundation/dogetest/pkg/doge"
+	"github.com/dogecoinfoundation/dogetest/pkg/rpc"
+)
+
+// CheckAddress decodes address for regtest with doge.DecodeAddress and with
+// the node's validateaddress, and returns an error describing the first
+// field on which they disagree.
+func (d *DogeTest) CheckAddress(address string) error {
+	decoded, decodeErr := doge.DecodeAddress(address, &doge.RegTestParams)
+
+	validated, err := d.Rpc.ValidateAddress(address)
+	if err != nil {
+		return err
+	}
+
+	if (decodeErr == nil) != validated.IsValid {
+		return fmt.Errorf("validity mismatch: go %v (%v), node %v", decodeErr == nil, decodeErr, validated.IsValid)
+	}
+	if !validated.IsValid {
+		return nil
+	}
+
+	if decoded.String() != validated.Address {
+		return fmt.Errorf("address mismatch: go %q, node %q", decoded.String(), validated.Address)
+	}
+
+	script := hex.EncodeToString(decoded.Script())
+	if script != validated.ScriptPubKey {
+		return fmt.Errorf("scriptPubKey mismatch: go %s, node %s", script, validated.ScriptPubKey)
+	}
+
+	isScript := decoded.Type == doge.ScriptHashAddr
+	if isScript != validated.IsScript {
+		return fmt.Errorf("isscript mismatch: go %v, node %v", isScript, validated.IsScript)
+	}
+
+	return nil
+}
+
+// WatchKey adds the address of a key generated in Go to the node's wallet as
+// watch-only, so that ListUnspent and GetWallet report its outputs while the
+// node itself cannot spend them. Call it before funding the address.
+func (d *DogeTest) WatchKey(key *doge.KeyPair) error {
+	rescan := false
+	_, err := d.Rpc.ImportAddress(rpc.ImportAddressParams{
+		Script: key.Address().String(),
+		Rescan: &rescan,
+	})
+	return err
+}
+
+// ImportKey adds a key generated in Go to the node's wallet under label, so
+// the node can spend from its address too. An encrypted wallet must be
+// unlocked first, see UnlockWallet.
+func (d *DogeTest) ImportKey(key *doge.KeyPair, label string) error {
+	rescan := false
+	_, err := d.Rpc.ImportPrivKey(rpc.ImportPrivKeyParams{
+		DogecoinPrivKey: key.WIF(),
+		Label:           &label,
+		Rescan:          &rescan,
+	})
+	return err
+}
@@ -0,0 +1,46 @@
+package dogetest
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/dogecoinfoundation/dogetest/pkg/doge"
+)
+
+// TestImportNullResults checks WatchKey and ImportKey against importaddress
+// and importprivkey, which return null, and the arguments they send.
+func TestImportNullResults(t *testing.T) {
+	params := map[string]string{}
+	d, _ := newFakeNode(t, func(method string, args []json.RawMessage) (any, string) {
+		switch method {
+		case "importaddress", "importprivkey":
+			data, _ := json.Marshal(args)
+			params[method] = string(data)
+			return nil, ""
+		}
+		return nil, errUnknownMethod
+	})
+
+	key, err := doge.GenerateKeyPair(&doge.RegTestParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := d.WatchKey(key); err != nil {
+		t.Fatalf("WatchKey: %v", err)
+	}
+	if err := d.ImportKey(key, "much label"); err != nil {
+		t.Fatalf("ImportKey: %v", err)
+	}
+
+	// The label left unset by WatchKey is sent as Core's default, since
+	// rescan follows it.
+	want := `["` + key.Address().String() + `","",false]`
+	if params["importaddress"] != want {
+		t.Errorf("importaddress params = %s, want %s", params["importaddress"], want)
+	}
+	want = `["` + key.WIF() + `","much label",false]`
+	if params["importprivkey"] != want {
+		t.Errorf("importprivkey params = %s, want %s", params["importprivkey"], want)
+	}
+}
@@ -0,0 +1,58 @@
+package dogetest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dogecoinfoundation/dogetest/pkg/rpc"
+)
+
+// ErrNotRegtest is returned by helpers that mine blocks or change the
+// node's clock when the node is not on regtest.
+var ErrNotRegtest = errors.New("node is not on regtest")
+
+// attachBackend connects to a node that is already running, see
+// DogeTestConfig.Attach. It never restarts, snapshots or stops the node.
+type attachBackend struct {
+	config *rpc.Config
+}
+
+func (a *attachBackend) start(ctx context.Context) (*rpc.Config, error) {
+	return a.config, nil
+}
+
+func (a *attachBackend) restart(ctx context.Context) (*rpc.Config, error) {
+	return nil, errors.New("an attached node cannot be restarted")
+}
+
+func (a *attachBackend) snapshot(ctx context.Context, name string) (*rpc.Config, error) {
+	return nil, errors.New("an attached node cannot be snapshotted")
+}
+
+func (a *attachBackend) restore(ctx context.Context, name string) (*rpc.Config, error) {
+	return nil, errors.New("an attached node cannot be restored")
+}
+
+func (a *attachBackend) stop() error {
+	return nil
+}
+
+// requireRegtest guards helpers that would change a shared chain, so an
+// attached node on mainnet or testnet is never mined on.
+func (d *DogeTest) requireRegtest() error {
+	if d.chain != "regtest" {
+		return fmt.Errorf("%w: chain is %q", ErrNotRegtest, d.chain)
+	}
+
+	return nil
+}
+
+// Generate mines n blocks to the node's wallet and returns their hashes.
+func (d *DogeTest) Generate(n int) ([]string, error) {
+	if err := d.requireRegtest(); err != nil {
+		return nil, err
+	}
+
+	return d.Rpc.Generate(n)
+}
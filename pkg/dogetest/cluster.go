@@ -0,0 +1,252 @@
+package dogetest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dogecoinfoundation/dogetest/pkg/rpc"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/network"
+)
+
+// regtestP2PPort is the port dogecoind accepts peers on in regtest.
+const regtestP2PPort = 18444
+
+// peerTimeout bounds how long Cluster.Start waits for the topology's
+// connections to be established.
+const peerTimeout = 30 * time.Second
+
+// Topology selects which nodes of a Cluster are connected to each other.
+type Topology int
+
+const (
+	// Line connects node i to node i+1.
+	Line Topology = iota
+	// Ring is a Line with the last node also connected to the first.
+	Ring
+	// FullMesh connects every pair of nodes.
+	FullMesh
+)
+
+func (t Topology) String() string {
+	switch t {
+	case Line:
+		return "line"
+	case Ring:
+		return "ring"
+	case FullMesh:
+		return "full mesh"
+	}
+	return fmt.Sprintf("Topology(%d)", int(t))
+}
+
+// edges returns the connections of the topology on n nodes.
+func (t Topology) edges(n int) [][2]int {
+	var edges [][2]int
+	switch t {
+	case FullMesh:
+		for i := range n {
+			for j := i + 1; j < n; j++ {
+				edges = append(edges, [2]int{i, j})
+			}
+		}
+	default:
+		for i := 0; i+1 < n; i++ {
+			edges = append(edges, [2]int{i, i + 1})
+		}
+		if t == Ring && n > 2 {
+			edges = append(edges, [2]int{n - 1, 0})
+		}
+	}
+	return edges
+}
+
+type ClusterConfig struct {
+	// Nodes is the number of nodes to start.
+	Nodes    int
+	Topology Topology
+	// Node configures every node, which always runs in a container, so
+	// Binary and Attach cannot be set. Without a NetworkName, a network is
+	// created for the cluster and removed by Stop.
+	Node DogeTestConfig
+}
+
+// Cluster runs several regtest nodes on one Docker network, connected as
+// peers, so blocks and transactions propagate between them.
+type Cluster struct {
+	Nodes   []*DogeTest
+	config  ClusterConfig
+	network *testcontainers.DockerNetwork
+
+	partition *partition
+}
+
+func NewCluster(config ClusterConfig) (*Cluster, error) {
+	if config.Nodes < 1 {
+		return nil, errors.New("a cluster needs at least one node")
+	}
+	if config.Node.Binary != "" {
+		return nil, errors.New("clusters need the container backend, not Binary")
+	}
+	if config.Node.Attach != nil {
+		return nil, errors.New("clusters need the container backend, not Attach")
+	}
+
+	return &Cluster{
+		config: config,
+	}, nil
+}
+
+// Start starts every node and connects them according to the topology,
+// waiting until the connections are up.
+func (c *Cluster) Start() error {
+	ctx := context.Background()
+
+	nodeConfig := c.config.Node
+	if nodeConfig.NetworkName == "" {
+		net, err := network.New(ctx, network.WithDriver("bridge"))
+		if err != nil {
+			return err
+		}
+		c.network = net
+		nodeConfig.NetworkName = net.Name
+	}
+
+	for i := range c.config.Nodes {
+		node, err := NewDogeTest(nodeConfig)
+		if err != nil {
+			return err
+		}
+		c.Nodes = append(c.Nodes, node)
+
+		err = node.Start()
+		if err != nil {
+			return fmt.Errorf("start node %d: %w", i, err)
+		}
+	}
+
+	for _, edge := range c.config.Topology.edges(len(c.Nodes)) {
+		err := c.Connect(edge[0], edge[1])
+		if err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, peerTimeout)
+	defer cancel()
+
+	return c.waitForTopology(ctx)
+}
+
+// Rpc returns the RPC transport of node i.
+func (c *Cluster) Rpc(i int) *rpc.RpcTransport {
+	return c.Nodes[i].Rpc
+}
+
+// Connect makes node i open a connection to node j.
+func (c *Cluster) Connect(i int, j int) error {
+	_, err := c.Rpc(i).AddNode(rpc.AddNodeParams{
+		Node:    c.Nodes[j].p2pAddress(),
+		Command: "onetry",
+	})
+	if err != nil {
+		return fmt.Errorf("connect node %d to node %d: %w", i, j, err)
+	}
+
+	return nil
+}
+
+// waitForTopology waits until every node has at least as many peers as
+// the topology gives it.
+func (c *Cluster) waitForTopology(ctx context.Context) error {
+	degree := make([]int, len(c.Nodes))
+	for _, edge := range c.config.Topology.edges(len(c.Nodes)) {
+		degree[edge[0]]++
+		degree[edge[1]]++
+	}
+
+	return waitFor(ctx, fmt.Sprintf("%s topology", c.config.Topology), func() (bool, string, error) {
+		counts := make([]string, len(c.Nodes))
+		done := true
+		for i := range c.Nodes {
+			peers, err := c.Rpc(i).GetPeerInfo()
+			if err != nil {
+				return false, "", err
+			}
+			counts[i] = fmt.Sprintf("%d/%d", len(peers), degree[i])
+			done = done && len(peers) >= degree[i]
+		}
+
+		return done, "peers " + strings.Join(counts, ", "), nil
+	})
+}
+
+// SyncBlocks blocks until every node has the same best block, or ctx is
+// done.
+func (c *Cluster) SyncBlocks(ctx context.Context) error {
+	return waitFor(ctx, "nodes to agree on the best block", func() (bool, string, error) {
+		tips := make([]string, len(c.Nodes))
+		for i := range c.Nodes {
+			hash, err := c.Rpc(i).GetBestBlockHash()
+			if err != nil {
+				return false, "", err
+			}
+			tips[i] = hash
+		}
+
+		return allEqual(tips), "tips " + strings.Join(tips, ", "), nil
+	})
+}
+
+// SyncMempools blocks until every node has the same transactions in its
+// mempool, or ctx is done.
+func (c *Cluster) SyncMempools(ctx context.Context) error {
+	return waitFor(ctx, "nodes to agree on the mempool", func() (bool, string, error) {
+		mempools := make([]string, len(c.Nodes))
+		sizes := make([]string, len(c.Nodes))
+		for i := range c.Nodes {
+			mempool, err := c.Rpc(i).GetRawMempool()
+			if err != nil {
+				return false, "", err
+			}
+			slices.Sort(mempool)
+			mempools[i] = strings.Join(mempool, ",")
+			sizes[i] = fmt.Sprint(len(mempool))
+		}
+
+		return allEqual(mempools), "mempool sizes " + strings.Join(sizes, ", "), nil
+	})
+}
+
+// Stop stops every node and removes the network created by Start.
+func (c *Cluster) Stop() error {
+	for _, node := range c.Nodes {
+		node.Stop()
+	}
+
+	if c.network != nil {
+		err := c.network.Remove(context.Background())
+		c.network = nil
+		return err
+	}
+
+	return nil
+}
+
+// p2pAddress is the address other containers on the network connect to.
+func (d *DogeTest) p2pAddress() string {
+	return fmt.Sprintf("%s:%d", d.name, regtestP2PPort)
+}
+
+func allEqual(values []string) bool {
+	for _, v := range values {
+		if v != values[0] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,68 @@
+package dogetest_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dogecoinfoundation/dogetest/pkg/dogetest"
+	"github.com/dogecoinfoundation/dogetest/pkg/dogetest/testutil"
+	"github.com/dogecoinfoundation/dogetest/pkg/rpc"
+)
+
+// TestClusterSyncBlocks connects two nodes, mines on the first and waits
+// for the second to follow.
+func TestClusterSyncBlocks(t *testing.T) {
+	cluster := testutil.NewCluster(t, dogetest.ClusterConfig{Nodes: 2, Topology: dogetest.Line})
+
+	for i := range cluster.Nodes {
+		peers, err := cluster.Rpc(i).GetPeerInfo()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(peers) == 0 {
+			t.Errorf("node %d has no peers", i)
+		}
+	}
+
+	hashes, err := cluster.Nodes[0].Generate(5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if err := cluster.SyncBlocks(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	best, err := cluster.Rpc(1).GetBestBlockHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := hashes[len(hashes)-1]; best != want {
+		t.Errorf("node 1 best block = %s, want %s", best, want)
+	}
+}
+
+func TestNewClusterConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  dogetest.ClusterConfig
+		wantErr bool
+	}{
+		{"containers", dogetest.ClusterConfig{Nodes: 3, Topology: dogetest.Ring}, false},
+		{"no nodes", dogetest.ClusterConfig{}, true},
+		{"binary", dogetest.ClusterConfig{Nodes: 2, Node: dogetest.DogeTestConfig{Binary: "dogecoind"}}, true},
+		{"attach", dogetest.ClusterConfig{Nodes: 2, Node: dogetest.DogeTestConfig{Attach: &rpc.Config{RpcUrl: "http://127.0.0.1:22555"}}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := dogetest.NewCluster(tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewCluster = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
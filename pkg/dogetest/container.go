@@ -0,0 +1,381 @@
+package dogetest
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/go-connections/nat"
+	"github.com/dogecoinfoundation/dogetest/pkg/rpc"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/network"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// DefaultRPCPort is the RPC port inside a container when
+// DogeTestConfig.Port is zero, the regtest default.
+const DefaultRPCPort = 18332
+
+// sessionLabel labels every container with the session of the process that
+// started it.
+const sessionLabel = "dogetest.session"
+
+var (
+	// session identifies this process's containers, in their names and
+	// sessionLabel, so parallel test binaries never share a name.
+	session = newSession()
+	// containerCount numbers the containers within the session.
+	containerCount atomic.Int64
+
+	// sessionNetworkMu guards sessionNetwork, the bridge network shared by
+	// the session's nodes that have no NetworkName, and sessionNetworkUsers,
+	// the number of nodes on it. The last node to stop removes it.
+	sessionNetworkMu    sync.Mutex
+	sessionNetwork      *testcontainers.DockerNetwork
+	sessionNetworkUsers int
+)
+
+// containerBackend runs the node in a Docker container, see
+// DogeTest.Container.
+type containerBackend struct {
+	d *DogeTest
+	// network is the session network the node joined, if it has no
+	// NetworkName, which stop leaves.
+	network *testcontainers.DockerNetwork
+}
+
+func (c *containerBackend) start(ctx context.Context) (*rpc.Config, error) {
+	return c.run(ctx, "")
+}
+
+// run starts the node's container from image, or from the configured image
+// when it is empty.
+func (c *containerBackend) run(ctx context.Context, image string) (*rpc.Config, error) {
+	portVal := strconv.Itoa(c.d.rpcPort())
+
+	logConsumers := []testcontainers.LogConsumer{}
+	if logLine := c.d.logLine(); logLine != nil {
+		logConsumers = append(logConsumers, funcLogConsumer(logLine))
+	}
+
+	networks := []string{}
+	if c.d.config.NetworkName != "" {
+		networks = append(networks, c.d.config.NetworkName)
+	} else if c.network != nil {
+		networks = append(networks, c.network.Name)
+	} else {
+		net, err := joinSessionNetwork(ctx)
+		if err != nil {
+			return nil, err
+		}
+		c.network = net
+		networks = append(networks, net.Name)
+	}
+
+	req := testcontainers.ContainerRequest{
+		Networks:     networks,
+		Name:         c.d.name,
+		Labels:       map[string]string{sessionLabel: session},
+		ExposedPorts: []string{portVal + "/tcp"},
+		Env: map[string]string{
+			"PORT": portVal,
+		},
+		Cmd: c.d.nodeArgs(),
+		Files: []testcontainers.ContainerFile{{
+			Reader:            bytes.NewReader(c.d.confFile(nil)),
+			ContainerFilePath: confPath,
+			FileMode:          0644,
+		}},
+		WaitingFor: wait.ForLog("init message: Done loading").WithStartupTimeout(10 * time.Second),
+		LogConsumerCfg: &testcontainers.LogConsumerConfig{
+			Opts:      []testcontainers.LogProductionOption{testcontainers.WithLogProductionTimeout(10 * time.Second)},
+			Consumers: logConsumers,
+		},
+	}
+
+	if image != "" {
+		req.Image = image
+	} else {
+		err := c.d.setImage(ctx, &req)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	dogecoinContainer, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		if dogecoinContainer.IsRunning() {
+			break
+		}
+
+		time.Sleep(1 * time.Second)
+	}
+
+	for {
+		handlerPort, err := dogecoinContainer.MappedPort(ctx, nat.Port(portVal+"/tcp"))
+		if err == nil {
+			c.d.logf("dogetest: RPC port %s mapped to %s", portVal, handlerPort.Port())
+			break
+		}
+
+		c.d.logf("dogetest: waiting for RPC port %s to be mapped: %v", portVal, err)
+
+		time.Sleep(1 * time.Second)
+	}
+
+	c.d.Container = dogecoinContainer
+
+	return c.rpcConfig(ctx)
+}
+
+// restart waits for the container to stop, as dogecoind exits after
+// encryptwallet, and starts it again.
+func (c *containerBackend) restart(ctx context.Context) (*rpc.Config, error) {
+	err := c.waitExited(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.d.Container.Start(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.rpcConfig(ctx)
+}
+
+// checkMounts fails for a container with volumes or other mounts, which
+// docker commit does not save.
+func checkMounts(mounts []container.MountPoint) error {
+	if len(mounts) == 0 {
+		return nil
+	}
+
+	var paths []string
+	for _, m := range mounts {
+		paths = append(paths, fmt.Sprintf("%s (%s)", m.Destination, m.Type))
+	}
+
+	return fmt.Errorf("the container mounts %s, which docker commit does not save: remove VOLUME from the Dockerfile", strings.Join(paths, ", "))
+}
+
+// waitExited waits until dogecoind has exited and the container stopped,
+// for up to processTimeout.
+func (c *containerBackend) waitExited(ctx context.Context) error {
+	deadline := time.Now().Add(processTimeout)
+	for {
+		state, err := c.d.Container.State(ctx)
+		if err != nil {
+			return err
+		}
+
+		if !state.Running {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("dogecoind did not exit within %v", processTimeout)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+}
+
+// snapshot stops dogecoind, commits the container, whose regtest data
+// directory is in its filesystem, as a snapshot image and starts it again.
+// Images whose Dockerfile declares a VOLUME are rejected, since docker
+// commit leaves out volumes and the snapshot would miss the chain.
+func (c *containerBackend) snapshot(ctx context.Context, name string) (*rpc.Config, error) {
+	cli, err := testcontainers.NewDockerClientWithOpts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cli.Close()
+
+	inspect, err := cli.ContainerInspect(ctx, c.d.Container.GetContainerID())
+	if err != nil {
+		return nil, err
+	}
+	if err := checkMounts(inspect.Mounts); err != nil {
+		return nil, err
+	}
+
+	err = c.shutdown(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = cli.ContainerCommit(ctx, c.d.Container.GetContainerID(), container.CommitOptions{
+		Reference: snapshotImage(name),
+		Comment:   "dogetest snapshot " + name,
+	})
+	if err != nil {
+		return nil, err
+	}
+	addSnapshotImage(snapshotImage(name))
+
+	err = c.d.Container.Start(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.rpcConfig(ctx)
+}
+
+// restore replaces the container with one started from a snapshot image.
+func (c *containerBackend) restore(ctx context.Context, name string) (*rpc.Config, error) {
+	exists, err := imageExists(ctx, snapshotImage(name))
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrNoSnapshot, name)
+	}
+
+	err = c.d.Container.Terminate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.d.Container = nil
+
+	return c.run(ctx, snapshotImage(name))
+}
+
+// shutdown stops dogecoind over RPC, so its data directory is flushed, and
+// waits for the container to stop.
+func (c *containerBackend) shutdown(ctx context.Context) error {
+	_, err := c.d.Rpc.Stop()
+	if err != nil {
+		return err
+	}
+
+	return c.waitExited(ctx)
+}
+
+func (c *containerBackend) stop() error {
+	var errs []error
+	if c.d.Container != nil {
+		errs = append(errs, c.d.Container.Terminate(context.Background()))
+	}
+	if c.network != nil {
+		errs = append(errs, leaveSessionNetwork(context.Background()))
+		c.network = nil
+	}
+
+	return errors.Join(errs...)
+}
+
+// rpcConfig points at the host and mapped port of the container's RPC port,
+// which may change when the container restarts.
+func (c *containerBackend) rpcConfig(ctx context.Context) (*rpc.Config, error) {
+	portVal := strconv.Itoa(c.d.rpcPort())
+
+	ip, err := c.d.Container.Host(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if c.d.config.Host != "" {
+		ip = c.d.config.Host
+	}
+
+	mappedPort, err := c.d.Container.MappedPort(ctx, nat.Port(portVal+"/tcp"))
+	if err != nil {
+		return nil, err
+	}
+
+	c.d.logf("dogetest: dogecoind at %s:%s (container %s)", ip, mappedPort.Port(), c.d.name)
+
+	return localRpcConfig(ip + ":" + mappedPort.Port()), nil
+}
+
+// rpcPort is the RPC port inside the container.
+func (d *DogeTest) rpcPort() int {
+	if d.config.Port == 0 {
+		return DefaultRPCPort
+	}
+	return d.config.Port
+}
+
+// joinSessionNetwork returns the session network, creating it for the
+// first node.
+func joinSessionNetwork(ctx context.Context) (*testcontainers.DockerNetwork, error) {
+	sessionNetworkMu.Lock()
+	defer sessionNetworkMu.Unlock()
+
+	if sessionNetwork == nil {
+		net, err := network.New(ctx,
+			network.WithDriver("bridge"),
+			network.WithLabels(map[string]string{sessionLabel: session}),
+		)
+		if err != nil {
+			return nil, err
+		}
+		sessionNetwork = net
+	}
+	sessionNetworkUsers++
+
+	return sessionNetwork, nil
+}
+
+// leaveSessionNetwork removes the session network once no node uses it.
+func leaveSessionNetwork(ctx context.Context) error {
+	sessionNetworkMu.Lock()
+	defer sessionNetworkMu.Unlock()
+
+	sessionNetworkUsers--
+	if sessionNetworkUsers > 0 {
+		return nil
+	}
+
+	net := sessionNetwork
+	sessionNetwork = nil
+	return net.Remove(ctx)
+}
+
+// newContainerName returns a container name that is unique across
+// concurrent tests and test binaries. Other containers on the same network
+// reach the node by it.
+func newContainerName() string {
+	return fmt.Sprintf("dogecoin-%s-%d", session, containerCount.Add(1))
+}
+
+// newSession returns a random session id, or one derived from the pid and
+// the time if the system has no randomness to offer.
+func newSession() string {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%08x", uint32(os.Getpid())^uint32(time.Now().UnixNano()))
+	}
+	return hex.EncodeToString(b)
+}
+
+// localRpcConfig is the RPC config of a node started by this package, which
+// always uses the test/test credentials.
+func localRpcConfig(address string) *rpc.Config {
+	return &rpc.Config{
+		RpcUrl:  "http://" + address,
+		RpcUser: "test",
+		RpcPass: "test",
+	}
+}
@@ -0,0 +1,68 @@
+package dogetest
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+)
+
+func TestCheckMounts(t *testing.T) {
+	if err := checkMounts(nil); err != nil {
+		t.Errorf("no mounts: %v", err)
+	}
+
+	err := checkMounts([]container.MountPoint{
+		{Type: mount.TypeVolume, Destination: "/root/.dogecoin"},
+		{Type: mount.TypeTmpfs, Destination: "/tmp"},
+	})
+	if err == nil {
+		t.Fatal("got no error for a container with volumes")
+	}
+	for _, want := range []string{"/root/.dogecoin (volume)", "/tmp (tmpfs)", "VOLUME"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("err = %q, want %q in it", err, want)
+		}
+	}
+}
+
+// dockerName is what Docker accepts as a container name.
+var dockerName = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]+$`)
+
+func TestNewContainerName(t *testing.T) {
+	names := make([]string, 50)
+	var wg sync.WaitGroup
+	for i := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			names[i] = newContainerName()
+		}()
+	}
+	wg.Wait()
+
+	seen := map[string]bool{}
+	for _, name := range names {
+		if seen[name] {
+			t.Errorf("%s handed out twice", name)
+		}
+		seen[name] = true
+
+		if !strings.HasPrefix(name, "dogecoin-"+session+"-") || !dockerName.MatchString(name) {
+			t.Errorf("name %q is not a container name of session %s", name, session)
+		}
+	}
+}
+
+func TestNewSession(t *testing.T) {
+	a, b := newSession(), newSession()
+	if !regexp.MustCompile(`^[0-9a-f]{8}$`).MatchString(a) {
+		t.Errorf("session %q is not 8 hex digits", a)
+	}
+	if a == b {
+		t.Errorf("two sessions are both %s", a)
+	}
+}
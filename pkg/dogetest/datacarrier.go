@@ -0,0 +1,119 @@
+package dogetest
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dogecoinfoundation/dogetest/pkg/datacarrier"
+	"github.com/dogecoinfoundation/dogetest/pkg/doge"
+	"github.com/dogecoinfoundation/dogetest/pkg/rpc"
+	"github.com/dogecoinfoundation/dogetest/pkg/txbuilder"
+)
+
+// maxUnconfirmedChain is one below the node's default -limitancestorcount,
+// the longest chain of unconfirmed transactions Inscribe broadcasts before
+// mining a block.
+const maxUnconfirmedChain = 24
+
+// EmbedData broadcasts a transaction with an OP_RETURN output carrying
+// data, funded from the outputs of from, an address of SetupAddresses. It
+// returns the txid; the transaction is left unconfirmed.
+func (d *DogeTest) EmbedData(from Address, data []byte) (string, error) {
+	key, utxos, err := d.fundingKey(from)
+	if err != nil {
+		return "", err
+	}
+
+	tx, err := datacarrier.BuildNullDataTx(key, utxos, data, txbuilder.DefaultFeeRate)
+	if err != nil {
+		return "", err
+	}
+
+	return txbuilder.Broadcast(d.Rpc, tx)
+}
+
+// Inscribe writes body as a Doginal-style inscription (see
+// datacarrier.Inscription) paying the inscribed output to destination, or
+// back to from when empty. The chain is funded from the outputs of from and
+// broadcast in order, mining a block whenever the unconfirmed chain would
+// grow past the node's ancestor limit; the final transactions are left
+// unconfirmed.
+func (d *DogeTest) Inscribe(from Address, contentType string, body []byte, destination string) (*datacarrier.Inscription, error) {
+	key, utxos, err := d.fundingKey(from)
+	if err != nil {
+		return nil, err
+	}
+
+	ins, txs, err := datacarrier.BuildInscription(contentType, body, datacarrier.ChainConfig{
+		Key:         key,
+		UTXOs:       utxos,
+		Destination: destination,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i, tx := range txs {
+		if i > 0 && i%maxUnconfirmedChain == 0 {
+			if _, err := d.ConfirmBlocks(); err != nil {
+				return nil, err
+			}
+		}
+		if _, err := txbuilder.Broadcast(d.Rpc, tx); err != nil {
+			return nil, err
+		}
+	}
+
+	return ins, nil
+}
+
+// FindNullData returns the OP_RETURN outputs of the blocks at heights
+// from..to inclusive.
+func (d *DogeTest) FindNullData(ctx context.Context, from int64, to int64) ([]datacarrier.NullData, error) {
+	it := d.Blocks(ctx, from, to, rpc.BlockIteratorConfig{})
+	defer it.Close()
+
+	var found []datacarrier.NullData
+	for it.Next() {
+		data, err := datacarrier.FindNullData(it.Block())
+		if err != nil {
+			return nil, err
+		}
+		found = append(found, data...)
+	}
+
+	return found, it.Err()
+}
+
+// FindInscriptions returns the inscriptions completed in the blocks at
+// heights from..to inclusive. Chains that began before from are missed.
+func (d *DogeTest) FindInscriptions(ctx context.Context, from int64, to int64) ([]*datacarrier.Inscription, error) {
+	it := d.Blocks(ctx, from, to, rpc.BlockIteratorConfig{})
+	defer it.Close()
+
+	parser := datacarrier.NewParser()
+	var found []*datacarrier.Inscription
+	for it.Next() {
+		found = append(found, parser.AddBlock(it.Block())...)
+	}
+
+	return found, it.Err()
+}
+
+func (d *DogeTest) fundingKey(from Address) (*doge.KeyPair, []rpc.UTXO, error) {
+	key, err := doge.KeyPairFromWIF(from.PrivateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	key.Params = &doge.RegTestParams
+
+	wallet, err := d.GetWallet(from.Address)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(wallet.Unspents) == 0 {
+		return nil, nil, fmt.Errorf("no unspent outputs at %s", from.Address)
+	}
+
+	return key, wallet.Unspents, nil
+}
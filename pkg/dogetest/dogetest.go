@@ -5,18 +5,14 @@ import (
 	_ "embed"
 	"fmt"
 	"log"
-	"math/rand"
 	"os"
-	"path"
-	"path/filepath"
-	"strconv"
 	"time"
 
-	"github.com/docker/go-connections/nat"
+	"github.com/dogecoinfoundation/dogetest/pkg/doge"
+	"github.com/dogecoinfoundation/dogetest/pkg/follower"
+	"github.com/dogecoinfoundation/dogetest/pkg/hdwallet"
 	"github.com/dogecoinfoundation/dogetest/pkg/rpc"
 	"github.com/testcontainers/testcontainers-go"
-	"github.com/testcontainers/testcontainers-go/network"
-	"github.com/testcontainers/testcontainers-go/wait"
 )
 
 //go:embed Dockerfile.dogecoin
@@ -27,13 +23,103 @@ type DogeTest struct {
 	Rpc       *rpc.RpcTransport
 	config    DogeTestConfig
 	Container testcontainers.Container
+
+	backend backend
+	// running is set once Start succeeds and cleared by Stop.
+	running bool
+	// name is the node's unique container name.
+	name string
+	// chain is the node's network as reported by getblockchaininfo.
+	chain string
+}
+
+// backend runs the node a DogeTest talks to.
+type backend interface {
+	// start launches the node and returns how to reach its RPC server.
+	start(ctx context.Context) (*rpc.Config, error)
+	// restart waits for the node to exit, as it does after encryptwallet,
+	// and starts it again.
+	restart(ctx context.Context) (*rpc.Config, error)
+	// snapshot shuts the node down, saves its chain and wallet under name
+	// and starts it again; restore restarts it from such a snapshot.
+	snapshot(ctx context.Context, name string) (*rpc.Config, error)
+	restore(ctx context.Context, name string) (*rpc.Config, error)
+	stop() error
 }
 
 type DogeTestConfig struct {
-	Host          string
+	// Host is the Docker host the container's mapped RPC port is reached
+	// on, the one testcontainers reports when empty.
+	Host string
+	// NetworkName is the Docker network the container joins. Nodes without
+	// one share a bridge network created for this process, which the last
+	// of them to stop removes.
 	NetworkName   string
 	LogContainers bool
-	Port          int
+	// Log, when set, receives the node's output line by line instead of the
+	// standard logger, as if LogContainers were set.
+	Log func(line string)
+	// Port is the RPC port inside the container, DefaultRPCPort when zero.
+	// Docker maps it to a free host port, so nodes never collide on it.
+	Port int
+
+	// Version is the Dogecoin Core release installed by the embedded
+	// Dockerfile, DefaultVersion when empty.
+	Version string
+	// Image, when set, is a prebuilt image to run instead of building one.
+	// Its entrypoint must be dogecoind; the node's settings are passed as
+	// arguments.
+	Image string
+	// Dockerfile, when set, is the path of a Dockerfile to build instead of
+	// the embedded one, with its directory as the build context. It gets
+	// the DOGE_VERSION build arg and its entrypoint must be dogecoind.
+	//
+	// Built images are tagged dogetest/dogecoin:<hash of the Dockerfile,
+	// build args and context> and kept, so later runs start without
+	// rebuilding. The context hash honours .dockerignore.
+	Dockerfile string
+	// Release, when set, is the path of a local Dogecoin Core release
+	// tarball (dogecoin-<version>-x86_64-linux-gnu.tar.gz) or of a directory
+	// holding its binaries, which is copied into the build context so the
+	// image builds without downloading Core. Only the debian base image must
+	// be available to Docker.
+	Release string
+	// ReleaseSHA256 is the expected hex SHA-256 of a Release tarball.
+	// Without it, the tarball's file name must be listed in
+	// ReleaseChecksums. Binaries directories are not verified.
+	ReleaseSHA256 string
+
+	// Binary, when set, is the path of a dogecoind executable to run as a
+	// child process instead of starting a container, for machines without
+	// Docker. The node gets a temporary data directory, a generated
+	// dogecoin.conf and free ports; Port and the image options are ignored.
+	Binary string
+
+	// Conf holds extra dogecoin.conf lines, such as "txindex=1",
+	// "acceptnonstdtxn=1" or "zmqpubrawblock=tcp://0.0.0.0:28332". Args holds
+	// extra dogecoind arguments, such as "-debug=mempool". Both are applied
+	// when the node starts, so they need no image rebuild. Options the
+	// package sets itself, like the network, RPC settings and ports, are
+	// rejected with ErrReservedOption.
+	Conf []string
+	Args []string
+
+	// Attach, when set, connects to an already running node instead of
+	// starting one, for example a shared node loaded with rpc.LoadConfig.
+	// Stop leaves it running. Helpers that mine blocks or change the node's
+	// clock return ErrNotRegtest unless the node is on regtest.
+	Attach *rpc.Config
+
+	// WalletPassphrase, when set, encrypts the node wallet with this
+	// passphrase during Start. The wallet is left locked. With Attach, it is
+	// the passphrase of the node's already encrypted wallet.
+	WalletPassphrase string
+
+	// Mnemonic, when set, is a BIP39 mnemonic from which SetupAddresses
+	// derives the key of each label (see hdwallet.LabelPath), so a label
+	// gets the same address on every run. The keys are imported into the
+	// node's wallet.
+	Mnemonic string
 }
 
 type AddressSetup struct {
@@ -44,18 +130,34 @@ type AddressSetup struct {
 func (d *DogeTest) SetupAddresses(addressSetups []AddressSetup) (*AddressBook, error) {
 	addresses := make([]Address, len(addressSetups))
 
-	_, err := d.Rpc.Generate(100)
+	if d.config.WalletPassphrase != "" {
+		err := d.UnlockWallet(time.Minute)
+		if err != nil {
+			return nil, err
+		}
+		defer d.LockWallet()
+	}
+
+	_, err := d.Generate(100)
 	if err != nil {
 		return nil, err
 	}
 
-	for i, addressSetup := range addressSetups {
-		address, err := d.Rpc.GetNewAddress()
+	var master *hdwallet.ExtendedKey
+	if d.config.Mnemonic != "" {
+		master, err = hdwallet.NewMasterFromMnemonic(d.config.Mnemonic, &doge.RegTestParams)
 		if err != nil {
 			return nil, err
 		}
+	}
 
-		privKey, err := d.Rpc.DumpPrivKey(address)
+	for i, addressSetup := range addressSetups {
+		var address, privKey string
+		if master != nil {
+			address, privKey, err = d.importLabelKey(master, addressSetup.Label)
+		} else {
+			address, privKey, err = d.newWalletKey()
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -83,9 +185,43 @@ func (d *DogeTest) SetupAddresses(addressSetups []AddressSetup) (*AddressBook, e
 	}, nil
 }
 
+func (d *DogeTest) newWalletKey() (string, string, error) {
+	address, err := d.Rpc.GetNewAddress()
+	if err != nil {
+		return "", "", err
+	}
+
+	privKey, err := d.Rpc.DumpPrivKey(address)
+	if err != nil {
+		return "", "", err
+	}
+
+	return address, privKey, nil
+}
+
+func (d *DogeTest) importLabelKey(master *hdwallet.ExtendedKey, label string) (string, string, error) {
+	extKey, err := hdwallet.LabelKey(master, label)
+	if err != nil {
+		return "", "", fmt.Errorf("derive key for %q: %w", label, err)
+	}
+
+	key, err := extKey.KeyPair()
+	if err != nil {
+		return "", "", err
+	}
+
+	err = d.ImportKey(key, label)
+	if err != nil {
+		return "", "", err
+	}
+
+	return key.Address().String(), key.WIF(), nil
+}
+
 func NewDogeTest(config DogeTestConfig) (*DogeTest, error) {
 	return &DogeTest{
 		config: config,
+		name:   newContainerName(),
 	}, nil
 }
 
@@ -102,7 +238,7 @@ func (d *DogeTest) GetWallet(address string) (*Wallet, error) {
 }
 
 func (d *DogeTest) ConfirmBlocks() ([]string, error) {
-	blocks, err := d.Rpc.Generate(1)
+	blocks, err := d.Generate(1)
 	if err != nil {
 		return nil, err
 	}
@@ -110,122 +246,139 @@ func (d *DogeTest) ConfirmBlocks() ([]string, error) {
 	return blocks, nil
 }
 
-func (d *DogeTest) Start() error {
-	portVal := strconv.Itoa(d.config.Port)
+// Blocks iterates over the blocks at heights from..to inclusive; see
+// rpc.NewBlockIterator for the meaning of a negative to and the config.
+func (d *DogeTest) Blocks(ctx context.Context, from int64, to int64, config rpc.BlockIteratorConfig) *rpc.BlockIterator {
+	return rpc.NewBlockIterator(ctx, d.Rpc, from, to, config)
+}
 
-	logConsumers := []testcontainers.LogConsumer{}
-	if d.config.LogContainers {
-		logConsumer := &StdoutLogConsumer{
-			Name: "dogecoin",
-		}
+// Follower returns a chain follower on this node, see follower.NewFollower.
+func (d *DogeTest) Follower(config follower.Config) *follower.Follower {
+	return follower.NewFollower(d.Rpc, config)
+}
 
-		logConsumers = append(logConsumers, logConsumer)
-	}
+func (d *DogeTest) Start() error {
+	ctx := context.Background()
 
-	networks := []string{}
-	if d.config.NetworkName != "" {
-		networks = append(networks, d.config.NetworkName)
-	} else {
-		ctx := context.Background()
+	err := d.validateOptions()
+	if err != nil {
+		return err
+	}
 
-		net, err := network.New(ctx, network.WithDriver("bridge"))
-		if err != nil {
-			return err
-		}
-		networks = append(networks, net.Name)
+	switch {
+	case d.config.Attach != nil:
+		d.backend = &attachBackend{config: d.config.Attach}
+	case d.config.Binary != "":
+		d.backend = &processBackend{d: d}
+	default:
+		d.backend = &containerBackend{d: d}
 	}
 
-	dockerfilePath, err := WriteDockerfileToDisk()
+	rpcConfig, err := d.backend.start(ctx)
 	if err != nil {
 		return err
 	}
 
-	dockerFolderPath := filepath.Dir(dockerfilePath)
-	dockerFilename := filepath.Base(dockerfilePath)
-
-	log.Println("Dockerfile path:", dockerfilePath)
-	log.Println("Dockerfile folder path:", dockerFolderPath)
-	log.Println("Dockerfile filename:", dockerFilename)
-
-	req := testcontainers.ContainerRequest{
-		FromDockerfile: testcontainers.FromDockerfile{
-			Context:    dockerFolderPath,
-			Dockerfile: dockerFilename,
-			KeepImage:  false,
-			BuildArgs: map[string]*string{
-				"PORT": &portVal,
-			},
-		},
-		Networks:     networks,
-		Name:         "dogecoin-" + portVal,
-		ExposedPorts: []string{portVal + "/tcp"},
-		Env: map[string]string{
-			"PORT": portVal,
-		},
-		WaitingFor: wait.ForLog("init message: Done loading").WithStartupTimeout(10 * time.Second),
-		LogConsumerCfg: &testcontainers.LogConsumerConfig{
-			Opts:      []testcontainers.LogProductionOption{testcontainers.WithLogProductionTimeout(10 * time.Second)},
-			Consumers: logConsumers,
-		},
+	err = d.connectRpc(ctx, rpcConfig)
+	if err != nil {
+		return err
 	}
 
-	ctx := context.Background()
-
-	dogecoinContainer, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
-		ContainerRequest: req,
-		Started:          true,
-	})
+	info, err := d.Rpc.GetBlockchainInfo()
 	if err != nil {
 		return err
 	}
+	d.chain = info.Chain
 
-	for {
-		if dogecoinContainer.IsRunning() {
-			break
+	if d.config.WalletPassphrase != "" && d.config.Attach == nil {
+		err = d.encryptWallet(ctx)
+		if err != nil {
+			return err
 		}
-
-		time.Sleep(1 * time.Second)
 	}
 
+	d.running = true
+	return nil
+}
+
+// connectRpc points d.Rpc at the node's RPC server and waits until the node
+// answers calls.
+func (d *DogeTest) connectRpc(ctx context.Context, config *rpc.Config) error {
+	d.Rpc = rpc.NewRpcTransport(config)
+
+	deadline := time.Now().Add(30 * time.Second)
 	for {
-		handlerPort, err := dogecoinContainer.MappedPort(ctx, nat.Port(portVal+"/tcp"))
+		_, err := d.Rpc.GetBlockCount()
 		if err == nil {
-			fmt.Printf("Handler port mapped to %s\n", handlerPort.Port())
-			break
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("dogecoin rpc not ready: %v", err)
 		}
 
-		fmt.Printf("Waiting for handler port to be mapped... %s\n", err)
+		time.Sleep(500 * time.Millisecond)
+	}
+}
 
-		time.Sleep(1 * time.Second)
+// logLine returns where the node's output and the package's messages about
+// it go: DogeTestConfig.Log, the standard logger when LogContainers is set,
+// or nil to discard them.
+func (d *DogeTest) logLine() func(line string) {
+	if d.config.Log != nil {
+		return d.config.Log
+	}
+	if d.config.LogContainers {
+		return func(line string) { log.Println(line) }
 	}
+	return nil
+}
 
-	ip, _ := dogecoinContainer.Host(ctx)
-	mappedPort, _ := dogecoinContainer.MappedPort(ctx, nat.Port(portVal+"/tcp"))
+// logf formats a message about the node for logLine.
+func (d *DogeTest) logf(format string, args ...any) {
+	if logLine := d.logLine(); logLine != nil {
+		logLine(fmt.Sprintf(format, args...))
+	}
+}
 
-	fmt.Printf("Dogecoin is running at %s:%s\n", ip, mappedPort.Port())
+// encryptWallet encrypts the wallet and restarts the node, which Core shuts
+// down as part of encryptwallet.
+func (d *DogeTest) encryptWallet(ctx context.Context) error {
+	err := d.Rpc.EncryptWallet(d.config.WalletPassphrase)
+	if err != nil {
+		return err
+	}
 
-	d.Container = dogecoinContainer
+	rpcConfig, err := d.backend.restart(ctx)
+	if err != nil {
+		return err
+	}
 
-	d.Rpc = rpc.NewRpcTransport(&rpc.Config{
-		RpcUrl:  "http://" + d.config.Host + ":" + mappedPort.Port(),
-		RpcUser: "test",
-		RpcPass: "test",
-	})
+	return d.connectRpc(ctx, rpcConfig)
+}
 
-	return nil
+// UnlockWallet unlocks the encrypted wallet with the configured passphrase
+// for the given duration.
+func (d *DogeTest) UnlockWallet(timeout time.Duration) error {
+	return d.Rpc.WalletPassphrase(d.config.WalletPassphrase, int64(timeout.Seconds()))
+}
+
+// LockWallet locks the encrypted wallet again.
+func (d *DogeTest) LockWallet() error {
+	return d.Rpc.WalletLock()
 }
 
 func (d *DogeTest) Stop() error {
-	if d.Container != nil {
-		d.Container.Terminate(context.Background())
+	d.running = false
+	if d.backend != nil {
+		return d.backend.stop()
 	}
 
 	return nil
 }
 
 func WriteDockerfileToDisk() (string, error) {
-	tempDir := path.Join(os.TempDir(), "dogetest") + strconv.Itoa(rand.Intn(1000000))
-	err := os.MkdirAll(tempDir, 0755)
+	tempDir, err := os.MkdirTemp("", "dogetest-")
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,179 @@
+package dogetest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/dogecoinfoundation/dogetest/pkg/doge"
+	"github.com/dogecoinfoundation/dogetest/pkg/hdwallet"
+	"github.com/dogecoinfoundation/dogetest/pkg/rpc"
+)
+
+// fakeNode answers JSON-RPC requests with handle, which returns the result
+// of a method or the message of Core's error. Methods it does not know
+// should return errUnknownMethod.
+type fakeNode struct {
+	mu     sync.Mutex
+	calls  []string
+	handle func(method string, params []json.RawMessage) (any, string)
+}
+
+const errUnknownMethod = "Method not found"
+
+// newFakeNode returns a regtest DogeTest whose RPC server is node.
+func newFakeNode(t *testing.T, handle func(method string, params []json.RawMessage) (any, string)) (*DogeTest, *fakeNode) {
+	t.Helper()
+
+	node := &fakeNode{handle: handle}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+			Id     uint64            `json:"id"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+			return
+		}
+
+		node.mu.Lock()
+		node.calls = append(node.calls, req.Method)
+		result, message := node.handle(req.Method, req.Params)
+		node.mu.Unlock()
+
+		res := map[string]any{"result": result, "error": nil, "id": req.Id}
+		if message != "" {
+			res["result"] = nil
+			res["error"] = map[string]any{"code": -1, "message": message}
+		}
+		json.NewEncoder(w).Encode(res)
+	}))
+	t.Cleanup(server.Close)
+
+	d := &DogeTest{
+		Rpc:   rpc.NewRpcTransport(&rpc.Config{RpcUrl: server.URL}),
+		chain: "regtest",
+	}
+
+	return d, node
+}
+
+// count returns how often method was called.
+func (n *fakeNode) count(method string) int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	count := 0
+	for _, call := range n.calls {
+		if call == method {
+			count++
+		}
+	}
+	return count
+}
+
+func TestImportLabelKey(t *testing.T) {
+	var imported []string
+	d, _ := newFakeNode(t, func(method string, params []json.RawMessage) (any, string) {
+		if method != "importprivkey" {
+			return nil, errUnknownMethod
+		}
+		var wif, label string
+		json.Unmarshal(params[0], &wif)
+		json.Unmarshal(params[1], &label)
+		imported = append(imported, wif+" "+label)
+		return nil, ""
+	})
+
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	master, err := hdwallet.NewMasterFromMnemonic(mnemonic, &doge.RegTestParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	address, wif, err := d.importLabelKey(master, "much label")
+	if err != nil {
+		t.Fatalf("importLabelKey: %v", err)
+	}
+
+	extKey, err := hdwallet.LabelKey(master, "much label")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := extKey.Address().String(); address != want {
+		t.Errorf("address = %s, want %s", address, want)
+	}
+	if len(imported) != 1 || imported[0] != wif+" much label" {
+		t.Errorf("imported %q, want the key of %s under its label", imported, address)
+	}
+}
+
+func TestConnectNullResult(t *testing.T) {
+	var added []string
+	handle := func(method string, params []json.RawMessage) (any, string) {
+		if method != "addnode" {
+			return nil, errUnknownMethod
+		}
+		var node string
+		json.Unmarshal(params[0], &node)
+		added = append(added, node)
+		return nil, ""
+	}
+	a, _ := newFakeNode(t, handle)
+	b, _ := newFakeNode(t, handle)
+	b.name = "dogetest-b"
+
+	c := &Cluster{Nodes: []*DogeTest{a, b}}
+	if err := c.Connect(0, 1); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	if len(added) != 1 || added[0] != "dogetest-b:18444" {
+		t.Errorf("addnode %q, want dogetest-b:18444", added)
+	}
+}
+
+func TestPartitionNeedsContainers(t *testing.T) {
+	a, fake := newFakeNode(t, func(method string, params []json.RawMessage) (any, string) {
+		return nil, ""
+	})
+	b, _ := newFakeNode(t, func(method string, params []json.RawMessage) (any, string) {
+		return nil, ""
+	})
+
+	c := &Cluster{Nodes: []*DogeTest{a, b}}
+	err := c.Partition([]int{0}, []int{1})
+	if err == nil || !strings.Contains(err.Error(), "does not run in a container") {
+		t.Fatalf("err = %v, want an error for a node without a container", err)
+	}
+	if n := fake.count("setban"); n != 0 {
+		t.Errorf("setban called %d times before the check", n)
+	}
+
+	err = c.Partition([]int{2}, []int{0})
+	if err == nil || !strings.Contains(err.Error(), "no node 2") {
+		t.Fatalf("err = %v, want an error for a missing node", err)
+	}
+}
+
+func TestLogf(t *testing.T) {
+	var lines []string
+	d := &DogeTest{config: DogeTestConfig{
+		Log:           func(line string) { lines = append(lines, line) },
+		LogContainers: true,
+	}}
+	d.logf("dogetest: dogecoind at %s:%d", "127.0.0.1", 18332)
+	if len(lines) != 1 || lines[0] != "dogetest: dogecoind at 127.0.0.1:18332" {
+		t.Errorf("logged %q, want the message once through Log", lines)
+	}
+
+	// Without Log or LogContainers messages are dropped.
+	quiet := &DogeTest{}
+	if quiet.logLine() != nil {
+		t.Error("logLine is set without Log or LogContainers")
+	}
+	quiet.logf("such silence")
+}
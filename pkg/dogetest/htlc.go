@@ -0,0 +1,98 @@
+package dogetest
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/dogecoinfoundation/dogetest/pkg/doge"
+	"github.com/dogecoinfoundation/dogetest/pkg/htlc"
+	"github.com/dogecoinfoundation/dogetest/pkg/rpc"
+	"github.com/dogecoinfoundation/dogetest/pkg/txbuilder"
+)
+
+// FundHTLC pays amount DOGE from the outputs of from, an address of
+// SetupAddresses, to the P2SH address of the contract and returns the
+// contract output, left unconfirmed.
+func (d *DogeTest) FundHTLC(from Address, c *htlc.Contract, amount float64) (rpc.UTXO, error) {
+	key, utxos, err := d.fundingKey(from)
+	if err != nil {
+		return rpc.UTXO{}, err
+	}
+
+	script := doge.PayToScriptHashScript(doge.Hash160(c.Script()))
+
+	b := txbuilder.New()
+	for _, utxo := range utxos {
+		if err := b.AddP2PKHInputKey(utxo, key); err != nil {
+			return rpc.UTXO{}, err
+		}
+	}
+	b.AddScriptOutput(script, doge.ToKoinu(amount))
+	if err := b.SetChange(from.Address, txbuilder.DefaultFeeRate); err != nil {
+		return rpc.UTXO{}, err
+	}
+
+	tx, err := b.Build()
+	if err != nil {
+		return rpc.UTXO{}, err
+	}
+	txid, err := txbuilder.Broadcast(d.Rpc, tx)
+	if err != nil {
+		return rpc.UTXO{}, err
+	}
+
+	return rpc.UTXO{
+		TxID:         txid,
+		Vout:         0,
+		Amount:       amount,
+		ScriptPubKey: hex.EncodeToString(script),
+		RedeemScript: hex.EncodeToString(c.Script()),
+	}, nil
+}
+
+// ClaimHTLC spends the contract output utxo to address by revealing the
+// preimage, signed with the recipient key, and returns the txid.
+func (d *DogeTest) ClaimHTLC(c *htlc.Contract, utxo rpc.UTXO, key *doge.KeyPair, preimage []byte, address string) (string, error) {
+	tx, err := htlc.ClaimTx(c, utxo, key, preimage, address, txbuilder.DefaultFeeRate)
+	if err != nil {
+		return "", err
+	}
+
+	return txbuilder.Broadcast(d.Rpc, tx)
+}
+
+// RefundHTLC spends the contract output utxo back to address with the
+// refund key after asserting, like CheckTimeLock, that the node rejects the
+// refund as non-final first. It then matures the lock time by mining or
+// mock time and returns the txid of the accepted refund. The lock time must
+// not have passed yet.
+func (d *DogeTest) RefundHTLC(c *htlc.Contract, utxo rpc.UTXO, key *doge.KeyPair, address string) (string, error) {
+	tx, err := htlc.RefundTx(c, utxo, key, address, txbuilder.DefaultFeeRate)
+	if err != nil {
+		return "", err
+	}
+
+	return d.CheckTimeLock(tx, func() error {
+		return d.MatureLockTime(tx)
+	})
+}
+
+// FindPreimage scans the blocks from height from up to the tip for a
+// transaction claiming the contract, and returns the revealed preimage with
+// the claiming txid.
+func (d *DogeTest) FindPreimage(ctx context.Context, c *htlc.Contract, from int64) ([]byte, string, error) {
+	it := d.Blocks(ctx, from, -1, rpc.BlockIteratorConfig{})
+	defer it.Close()
+
+	for it.Next() {
+		if preimage, txid, ok := htlc.ExtractPreimage(it.Block(), c); ok {
+			return preimage, txid, nil
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, "", err
+	}
+
+	return nil, "", fmt.Errorf("no claim of contract %s found from height %d", c.Address(&doge.RegTestParams), from)
+}
@@ -0,0 +1,264 @@
+package dogetest
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/docker/docker/client"
+	"github.com/moby/patternmatcher"
+	"github.com/moby/patternmatcher/ignorefile"
+	"github.com/testcontainers/testcontainers-go"
+)
+
+// DefaultVersion is the Dogecoin Core release the embedded Dockerfile
+// installs unless DogeTestConfig.Version says otherwise.
+const DefaultVersion = "1.14.9"
+
+// imageRepo is the repository built images are tagged in, with the content
+// hash of their Dockerfile and build args as the tag.
+const imageRepo = "dogetest/dogecoin"
+
+// nodeArgs are the dogecoind arguments every container is started with, so
+// that prebuilt images and user Dockerfiles need no configuration of their
+// own, followed by DogeTestConfig.Args.
+func (d *DogeTest) nodeArgs() []string {
+	args := []string{
+		"-printtoconsole",
+		"-conf=" + confPath,
+		"-regtest",
+		"-server",
+		"-rpcuser=test",
+		"-rpcpassword=test",
+		"-rpcport=" + strconv.Itoa(d.rpcPort()),
+		"-rpcbind=0.0.0.0",
+		"-rpcallowip=0.0.0.0/0",
+	}
+
+	return append(args, d.config.Args...)
+}
+
+// imageBuild describes how an image is built, before any file is written.
+type imageBuild struct {
+	dockerfile []byte
+	buildArgs  map[string]*string
+	// context is the hash of a user's build context, empty for contexts
+	// written from embedded files.
+	context string
+	// prepare writes the build context and returns the Dockerfile's path.
+	prepare func() (string, error)
+}
+
+// buildMu serializes image lookups and builds, so nodes started in parallel
+// build a missing image once and then share it.
+var buildMu sync.Mutex
+
+// setImage points req at the image to run: the configured prebuilt image,
+// a previously built image with the same content hash, or one it builds,
+// tags and keeps for the next run.
+func (d *DogeTest) setImage(ctx context.Context, req *testcontainers.ContainerRequest) error {
+	if d.config.Image != "" {
+		req.Image = d.config.Image
+		return nil
+	}
+
+	build, err := d.imageBuild()
+	if err != nil {
+		return err
+	}
+
+	tag := contentHash(build.dockerfile, build.buildArgs, build.context)
+	req.Image = imageRepo + ":" + tag
+
+	buildMu.Lock()
+	defer buildMu.Unlock()
+
+	cached, err := imageExists(ctx, req.Image)
+	if err != nil {
+		return err
+	}
+	if cached {
+		return nil
+	}
+
+	dockerfilePath, err := build.prepare()
+	if err != nil {
+		return err
+	}
+
+	log.Println("Building image", req.Image, "from", dockerfilePath)
+
+	provider, err := testcontainers.NewDockerProvider()
+	if err != nil {
+		return err
+	}
+	defer provider.Close()
+
+	_, err = provider.BuildImage(ctx, &testcontainers.ContainerRequest{
+		FromDockerfile: testcontainers.FromDockerfile{
+			Context:    filepath.Dir(dockerfilePath),
+			Dockerfile: filepath.Base(dockerfilePath),
+			Repo:       imageRepo,
+			Tag:        tag,
+			KeepImage:  true,
+			BuildArgs:  build.buildArgs,
+		},
+	})
+
+	return err
+}
+
+// imageBuild selects the local release, the user's Dockerfile or the
+// embedded one, in that order.
+func (d *DogeTest) imageBuild() (*imageBuild, error) {
+	if d.config.Release != "" {
+		return d.releaseBuild()
+	}
+
+	version := d.config.Version
+	if version == "" {
+		version = DefaultVersion
+	}
+	buildArgs := map[string]*string{
+		"DOGE_VERSION": &version,
+	}
+
+	if d.config.Dockerfile != "" {
+		dockerfile, err := os.ReadFile(d.config.Dockerfile)
+		if err != nil {
+			return nil, err
+		}
+		context, err := hashContext(filepath.Dir(d.config.Dockerfile), filepath.Base(d.config.Dockerfile))
+		if err != nil {
+			return nil, fmt.Errorf("hash build context: %w", err)
+		}
+		return &imageBuild{
+			dockerfile: dockerfile,
+			buildArgs:  buildArgs,
+			context:    context,
+			prepare: func() (string, error) {
+				return d.config.Dockerfile, nil
+			},
+		}, nil
+	}
+
+	return &imageBuild{
+		dockerfile: dockerfileData,
+		buildArgs:  buildArgs,
+		prepare:    WriteDockerfileToDisk,
+	}, nil
+}
+
+// contentHash identifies a build by its Dockerfile, build args and the hash
+// of its build context.
+func contentHash(dockerfile []byte, buildArgs map[string]*string, context string) string {
+	h := sha256.New()
+	h.Write(dockerfile)
+	for _, name := range slices.Sorted(maps.Keys(buildArgs)) {
+		h.Write([]byte("\x00" + name + "=" + *buildArgs[name]))
+	}
+	h.Write([]byte("\x00" + context))
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// hashContext hashes the paths, modes and contents of the files Docker
+// would be sent as the build context of dir: all of them except those
+// excluded by its .dockerignore, which never excludes itself or the
+// Dockerfile.
+func hashContext(dir string, dockerfile string) (string, error) {
+	var patterns []string
+	f, err := os.Open(filepath.Join(dir, ".dockerignore"))
+	if err == nil {
+		patterns, err = ignorefile.ReadAll(f)
+		f.Close()
+		if err != nil {
+			return "", fmt.Errorf("read .dockerignore: %w", err)
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return "", err
+	}
+
+	pm, err := patternmatcher.New(patterns)
+	if err != nil {
+		return "", fmt.Errorf("parse .dockerignore: %w", err)
+	}
+
+	h := sha256.New()
+	err = filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if entry.IsDir() || rel == ".dockerignore" || rel == dockerfile {
+			return nil
+		}
+
+		excluded, err := pm.MatchesOrParentMatches(rel)
+		if err != nil || excluded {
+			return err
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		h.Write([]byte(fmt.Sprintf("%s\x00%o\x00", rel, info.Mode())))
+
+		switch {
+		case info.Mode()&fs.ModeSymlink != 0:
+			target, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			h.Write([]byte(target))
+		case info.Mode().IsRegular():
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			if _, err := io.Copy(h, f); err != nil {
+				return err
+			}
+		}
+		h.Write([]byte{0})
+
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// imageExists reports whether the Docker daemon has the image ref locally.
+func imageExists(ctx context.Context, ref string) (bool, error) {
+	cli, err := testcontainers.NewDockerClientWithOpts(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer cli.Close()
+
+	_, err = cli.ImageInspect(ctx, ref)
+	if client.IsErrNotFound(err) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
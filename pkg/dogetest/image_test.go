@@ -0,0 +1,86 @@
+package dogetest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHashContext(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, data string) {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	hash := func() string {
+		t.Helper()
+		sum, err := hashContext(dir, "Dockerfile")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sum
+	}
+
+	write("Dockerfile", "FROM debian\n")
+	write("dogecoin.conf", "regtest=1\n")
+	write("logs/debug.log", "much log\n")
+	write("logs/keep.log", "very keep\n")
+	write(".dockerignore", "logs\n!logs/keep.log\nDockerfile\n")
+	base := hash()
+
+	write("logs/debug.log", "such change\n")
+	if hash() != base {
+		t.Error("an ignored file changed the hash")
+	}
+
+	write("Dockerfile", "FROM alpine\n")
+	if hash() != base {
+		t.Error("the Dockerfile changed the context hash")
+	}
+
+	write("logs/keep.log", "wow\n")
+	kept := hash()
+	if kept == base {
+		t.Error("a file re-included by ! did not change the hash")
+	}
+
+	write("dogecoin.conf", "regtest=0\n")
+	if hash() == kept {
+		t.Error("a context file did not change the hash")
+	}
+
+	before := hash()
+	if err := os.Chmod(filepath.Join(dir, "dogecoin.conf"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if hash() == before {
+		t.Error("a mode change did not change the hash")
+	}
+
+	write(".dockerignore", "logs\n")
+	if hash() == before {
+		t.Error("a .dockerignore change did not change the hash")
+	}
+}
+
+func TestContentHash(t *testing.T) {
+	version := DefaultVersion
+	args := map[string]*string{"DOGE_VERSION": &version}
+
+	a := contentHash([]byte("FROM debian\n"), args, "context")
+	if b := contentHash([]byte("FROM debian\n"), args, "context"); a != b {
+		t.Errorf("same build hashed to %s and %s", a, b)
+	}
+	if b := contentHash([]byte("FROM debian\n"), args, "other"); a == b {
+		t.Error("the context hash did not change the tag")
+	}
+	if len(a) != 16 {
+		t.Errorf("tag %q, want 16 hex digits", a)
+	}
+}
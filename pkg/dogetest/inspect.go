@@ -0,0 +1,55 @@
+package dogetest
+
+import (
+	"fmt"
+
+	"github.com/dogecoinfoundation/dogetest/pkg/doge"
+	"github.com/dogecoinfoundation/dogetest/pkg/rpc"
+)
+
+// InspectInput checks the signatures of input index of tx, fetching the
+// output it spends from the node. See doge.InspectInput for what is reported.
+func (d *DogeTest) InspectInput(tx *rpc.RawTxn, index int) (*doge.InputInspection, error) {
+	if index < 0 || index >= len(tx.VIn) {
+		return nil, fmt.Errorf("input %d out of range", index)
+	}
+
+	var parsed *doge.Tx
+	var err error
+	if tx.Hex != "" {
+		parsed, err = doge.DecodeTxHex(tx.Hex)
+	} else {
+		parsed, err = d.GetTx(tx.TxID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	vin := tx.VIn[index]
+	prevTx, err := d.GetTx(vin.TxID)
+	if err != nil {
+		return nil, fmt.Errorf("previous transaction %s: %w", vin.TxID, err)
+	}
+	if vin.VOut >= len(prevTx.TxOut) {
+		return nil, fmt.Errorf("previous transaction %s has no output %d", vin.TxID, vin.VOut)
+	}
+
+	return doge.InspectInput(parsed, index, prevTx.TxOut[vin.VOut])
+}
+
+// GetTx fetches and decodes a transaction, falling back to the wallet when
+// the node has no transaction index and the transaction is no longer in the
+// mempool or UTXO set.
+func (d *DogeTest) GetTx(txid string) (*doge.Tx, error) {
+	raw, err := d.Rpc.GetRawTransaction(txid)
+	if err == nil {
+		return doge.DecodeTxHex(raw)
+	}
+
+	walletTx, walletErr := d.Rpc.GetTransaction(txid)
+	if walletErr != nil {
+		return nil, err
+	}
+
+	return doge.DecodeTxHex(walletTx.Hex)
+}
@@ -0,0 +1,30 @@
+package dogetest_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/dogecoinfoundation/dogetest/pkg/dogetest/testutil"
+)
+
+// TestNodesShareNetwork starts two nodes without a NetworkName and checks
+// that they join the same network.
+func TestNodesShareNetwork(t *testing.T) {
+	a := testutil.New(t)
+	b := testutil.New(t)
+
+	ctx := context.Background()
+	networksA, err := a.Container.Networks(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	networksB, err := b.Container.Networks(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(networksA) != 1 || !slices.Equal(networksA, networksB) {
+		t.Errorf("nodes are on %v and %v, want one shared network", networksA, networksB)
+	}
+}
@@ -0,0 +1,88 @@
+package dogetest
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrReservedOption is returned by Start when Conf or Args set an option the
+// package manages itself.
+var ErrReservedOption = errors.New("reserved dogecoind option")
+
+// reservedOptions are set by the package for every node: the network, the
+// RPC server and credentials its helpers connect with, the files and ports
+// each backend manages, and the wallet most helpers use.
+var reservedOptions = []string{
+	"regtest",
+	"testnet",
+	"server",
+	"rpcuser",
+	"rpcpassword",
+	"rpcauth",
+	"rpcport",
+	"rpcbind",
+	"rpcallowip",
+	"port",
+	"conf",
+	"datadir",
+	"daemon",
+	"printtoconsole",
+	"disablewallet",
+}
+
+// confPath is where a container's generated dogecoin.conf is written.
+const confPath = "/dogecoin/dogecoin.conf"
+
+// validateOptions checks DogeTestConfig.Conf and Args for reserved options.
+func (d *DogeTest) validateOptions() error {
+	if d.config.Attach != nil && (len(d.config.Conf) > 0 || len(d.config.Args) > 0) {
+		return errors.New("Conf and Args cannot be used with Attach")
+	}
+
+	for _, line := range d.config.Conf {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if err := checkOption(line); err != nil {
+			return err
+		}
+	}
+
+	for _, arg := range d.config.Args {
+		if !strings.HasPrefix(arg, "-") {
+			return fmt.Errorf("dogecoind argument %q does not start with -", arg)
+		}
+		if err := checkOption(arg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkOption rejects a name=value option, or its negated -no form, whose
+// name is reserved. Leading dashes are ignored, so a conf line written like
+// an argument is caught as well.
+func checkOption(option string) error {
+	name, _, _ := strings.Cut(option, "=")
+	name = strings.TrimLeft(strings.TrimSpace(name), "-")
+
+	if slices.Contains(reservedOptions, name) ||
+		(strings.HasPrefix(name, "no") && slices.Contains(reservedOptions, name[2:])) {
+		return fmt.Errorf("%w: %s is set by dogetest", ErrReservedOption, name)
+	}
+
+	return nil
+}
+
+// confFile returns the dogecoin.conf a node starts with: the backend's own
+// settings followed by DogeTestConfig.Conf.
+func (d *DogeTest) confFile(settings []string) []byte {
+	lines := append([]string{"# Generated by dogetest"}, settings...)
+	lines = append(lines, d.config.Conf...)
+
+	return []byte(strings.Join(lines, "\n") + "\n")
+}
@@ -0,0 +1,64 @@
+package dogetest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dogecoinfoundation/dogetest/pkg/rpc"
+)
+
+func TestValidateOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		conf     []string
+		args     []string
+		reserved bool // the error is ErrReservedOption
+		wantErr  bool
+	}{
+		{name: "allowed conf", conf: []string{"txindex=1", "maxmempool=5", "", "# rpcport=1"}},
+		{name: "allowed args", args: []string{"-txindex=1", "-debug", "--acceptnonstdtxn=1"}},
+		{name: "allowed no form", conf: []string{"nolisten"}, args: []string{"-noconnect"}},
+
+		{name: "reserved conf", conf: []string{"rpcport=22555"}, reserved: true},
+		{name: "reserved arg", args: []string{"-rpcuser=shibe"}, reserved: true},
+		{name: "reserved without value", conf: []string{"regtest"}, reserved: true},
+		{name: "set to 0", conf: []string{"server=0"}, reserved: true},
+		{name: "no form in conf", conf: []string{"nodisablewallet"}, reserved: true},
+		{name: "no form in args", args: []string{"-noprinttoconsole"}, reserved: true},
+		{name: "no form with value", args: []string{"-nodaemon=1"}, reserved: true},
+		{name: "double dash", args: []string{"--datadir=/tmp"}, reserved: true},
+		{name: "leading dash in conf", conf: []string{"-testnet=1"}, reserved: true},
+		{name: "whitespace in conf", conf: []string{"  rpcpassword = wow  "}, reserved: true},
+		{name: "whitespace in args", args: []string{"-port =1"}, reserved: true},
+		{name: "after allowed options", conf: []string{"txindex=1", "rpcbind=0.0.0.0"}, reserved: true},
+
+		{name: "arg without dash", args: []string{"txindex=1"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &DogeTest{config: DogeTestConfig{Conf: tt.conf, Args: tt.args}}
+
+			err := d.validateOptions()
+			switch {
+			case tt.reserved:
+				if !errors.Is(err, ErrReservedOption) {
+					t.Errorf("err = %v, want ErrReservedOption", err)
+				}
+			case tt.wantErr:
+				if err == nil || errors.Is(err, ErrReservedOption) {
+					t.Errorf("err = %v, want an error other than ErrReservedOption", err)
+				}
+			case err != nil:
+				t.Errorf("err = %v, want none", err)
+			}
+		})
+	}
+
+	t.Run("attach", func(t *testing.T) {
+		d := &DogeTest{config: DogeTestConfig{Attach: &rpc.Config{}, Conf: []string{"txindex=1"}}}
+		if err := d.validateOptions(); err == nil {
+			t.Error("accepted Conf with Attach")
+		}
+	})
+}
@@ -0,0 +1,307 @@
+package dogetest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/dogecoinfoundation/dogetest/pkg/rpc"
+)
+
+// partition records the two sides of Cluster.Partition until Heal.
+type partition struct {
+	groups [2][]int
+}
+
+// HealReport describes how a partition was resolved.
+type HealReport struct {
+	// ForkHeight is the height of the last block both sides shared.
+	ForkHeight int64
+	// Tips holds the best block of each side (its first node) just before
+	// the partition was healed.
+	Tips [2]string
+	// Winner is the side whose chain the cluster settled on: 0 for groupA,
+	// 1 for groupB. If only one side mined, that side wins.
+	Winner int
+	// BestBlock is the best block all nodes agree on after healing.
+	BestBlock string
+	// Dropped lists the transactions that were mined or in a mempool on
+	// either side and are now neither in the best chain nor in any
+	// mempool, e.g. the losing half of a double spend. Coinbase
+	// transactions of orphaned blocks are not listed.
+	Dropped []string
+}
+
+// Partition splits the cluster in two: every node of groupA disconnects
+// from and bans every node of groupB and vice versa, so each side can mine
+// its own chain. Connections within a group are kept, but only those the
+// topology provides.
+func (c *Cluster) Partition(groupA []int, groupB []int) error {
+	if c.partition != nil {
+		return errors.New("cluster is already partitioned")
+	}
+	if len(groupA) == 0 || len(groupB) == 0 {
+		return errors.New("both groups need at least one node")
+	}
+	for _, i := range groupA {
+		if slices.Contains(groupB, i) {
+			return fmt.Errorf("node %d is in both groups", i)
+		}
+	}
+	// Bans are by container IP, so check every node before banning any.
+	for _, i := range slices.Concat(groupA, groupB) {
+		if i < 0 || i >= len(c.Nodes) {
+			return fmt.Errorf("no node %d in a cluster of %d", i, len(c.Nodes))
+		}
+		if c.Nodes[i].Container == nil {
+			return fmt.Errorf("node %d does not run in a container", i)
+		}
+	}
+
+	ctx := context.Background()
+	for _, a := range groupA {
+		for _, b := range groupB {
+			if err := c.ban(ctx, a, b); err != nil {
+				return err
+			}
+			if err := c.ban(ctx, b, a); err != nil {
+				return err
+			}
+		}
+	}
+	c.partition = &partition{groups: [2][]int{groupA, groupB}}
+
+	ctx, cancel := context.WithTimeout(ctx, peerTimeout)
+	defer cancel()
+
+	return waitFor(ctx, "partition", func() (bool, string, error) {
+		for side, group := range c.partition.groups {
+			for _, i := range group {
+				peers, err := c.Rpc(i).GetPeerInfo()
+				if err != nil {
+					return false, "", err
+				}
+				for _, j := range c.partition.groups[1-side] {
+					connected, err := c.hasPeer(ctx, peers, j)
+					if err != nil {
+						return false, "", err
+					}
+					if connected {
+						return false, fmt.Sprintf("node %d still connected to node %d", i, j), nil
+					}
+				}
+			}
+		}
+		return true, "", nil
+	})
+}
+
+// Heal lifts the bans of Partition, reconnects the topology and waits until
+// all nodes agree on the best block, then reports which side's chain won
+// and which transactions were dropped. Mempools are not synced: the losing
+// side's transactions and conflicting double spends need not reach every
+// node, so a transaction counts as kept while any node still holds it.
+// Heal clears every ban on the partitioned nodes. Nodes keep the first
+// chain they saw among chains of equal work, so mine more blocks on the
+// side that should win, or the nodes never agree and Heal fails when ctx
+// is done.
+func (c *Cluster) Heal(ctx context.Context) (*HealReport, error) {
+	if c.partition == nil {
+		return nil, errors.New("cluster is not partitioned")
+	}
+	groups := c.partition.groups
+
+	report := &HealReport{}
+	sides := [2]int{groups[0][0], groups[1][0]}
+
+	heights := [2]int64{}
+	for side, node := range sides {
+		height, err := c.Rpc(node).GetBlockCount()
+		if err != nil {
+			return nil, err
+		}
+		heights[side] = height
+
+		report.Tips[side], err = c.Rpc(node).GetBestBlockHash()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	forkHeight, err := c.forkHeight(sides, min(heights[0], heights[1]))
+	if err != nil {
+		return nil, err
+	}
+	report.ForkHeight = forkHeight
+
+	// Everything either side mined or holds in its mempool may be dropped.
+	var candidates []string
+	for side, node := range sides {
+		txids, err := c.minedSince(node, forkHeight)
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, txids...)
+
+		for _, i := range groups[side] {
+			mempool, err := c.Rpc(i).GetRawMempool()
+			if err != nil {
+				return nil, err
+			}
+			candidates = append(candidates, mempool...)
+		}
+	}
+
+	for _, group := range groups {
+		for _, i := range group {
+			if _, err := c.Rpc(i).ClearBanned(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	c.partition = nil
+
+	for _, edge := range c.config.Topology.edges(len(c.Nodes)) {
+		if err := c.Connect(edge[0], edge[1]); err != nil {
+			return nil, err
+		}
+	}
+	if err := c.waitForTopology(ctx); err != nil {
+		return nil, err
+	}
+	if err := c.SyncBlocks(ctx); err != nil {
+		return nil, err
+	}
+
+	report.BestBlock, err = c.Rpc(0).GetBestBlockHash()
+	if err != nil {
+		return nil, err
+	}
+
+	// The winner is the side whose tip is on the best chain; if both are
+	// (one side did not mine), the higher one.
+	report.Winner = -1
+	for side := range sides {
+		hash, err := c.Rpc(0).GetBlockHash(heights[side])
+		if err == nil && hash == report.Tips[side] && (report.Winner < 0 || heights[side] > heights[report.Winner]) {
+			report.Winner = side
+		}
+	}
+	if report.Winner < 0 {
+		return nil, fmt.Errorf("neither side's tip is on the best chain %s", report.BestBlock)
+	}
+
+	kept, err := c.minedSince(0, forkHeight)
+	if err != nil {
+		return nil, err
+	}
+	for i := range c.Nodes {
+		mempool, err := c.Rpc(i).GetRawMempool()
+		if err != nil {
+			return nil, err
+		}
+		kept = append(kept, mempool...)
+	}
+
+	for _, txid := range candidates {
+		if !slices.Contains(kept, txid) && !slices.Contains(report.Dropped, txid) {
+			report.Dropped = append(report.Dropped, txid)
+		}
+	}
+
+	return report, nil
+}
+
+// ban makes node i ban node j, which also drops their connection.
+func (c *Cluster) ban(ctx context.Context, i int, j int) error {
+	ip, err := c.containerIP(ctx, j)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.Rpc(i).SetBan(rpc.SetBanParams{Subnet: ip, Command: "add"})
+	if err != nil && !strings.Contains(err.Error(), "already banned") {
+		return fmt.Errorf("node %d ban node %d: %w", i, j, err)
+	}
+
+	return nil
+}
+
+// hasPeer reports whether peers includes node j, by its container IP for
+// inbound connections or by name for connections made by Connect.
+func (c *Cluster) hasPeer(ctx context.Context, peers []rpc.PeerInfo, j int) (bool, error) {
+	ip, err := c.containerIP(ctx, j)
+	if err != nil {
+		return false, err
+	}
+	for _, peer := range peers {
+		if peer.Addr == c.Nodes[j].p2pAddress() || strings.HasPrefix(peer.Addr, ip+":") {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// containerIP returns the IP of node j on the cluster's network.
+func (c *Cluster) containerIP(ctx context.Context, j int) (string, error) {
+	if c.Nodes[j].Container == nil {
+		return "", fmt.Errorf("node %d does not run in a container", j)
+	}
+
+	ip, err := c.Nodes[j].Container.ContainerIP(ctx)
+	if err != nil {
+		return "", fmt.Errorf("node %d container IP: %w", j, err)
+	}
+	if ip == "" {
+		return "", fmt.Errorf("node %d has no container IP", j)
+	}
+
+	return ip, nil
+}
+
+// forkHeight returns the height of the last block both nodes share, at or
+// below height.
+func (c *Cluster) forkHeight(nodes [2]int, height int64) (int64, error) {
+	for ; height > 0; height-- {
+		a, err := c.Rpc(nodes[0]).GetBlockHash(height)
+		if err != nil {
+			return 0, err
+		}
+		b, err := c.Rpc(nodes[1]).GetBlockHash(height)
+		if err != nil {
+			return 0, err
+		}
+		if a == b {
+			break
+		}
+	}
+	return height, nil
+}
+
+// minedSince returns the non-coinbase transactions in node's best chain
+// above height.
+func (c *Cluster) minedSince(node int, height int64) ([]string, error) {
+	tip, err := c.Rpc(node).GetBlockCount()
+	if err != nil {
+		return nil, err
+	}
+
+	var txids []string
+	for h := height + 1; h <= tip; h++ {
+		hash, err := c.Rpc(node).GetBlockHash(h)
+		if err != nil {
+			return nil, err
+		}
+		block, err := c.Rpc(node).GetBlockVerbosity(hash, 1)
+		if err != nil {
+			return nil, err
+		}
+		for _, tx := range block.Tx[1:] {
+			txids = append(txids, tx.TxID)
+		}
+	}
+
+	return txids, nil
+}
@@ -0,0 +1,95 @@
+package dogetest_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/dogecoinfoundation/dogetest/pkg/doge"
+	"github.com/dogecoinfoundation/dogetest/pkg/dogetest"
+	"github.com/dogecoinfoundation/dogetest/pkg/dogetest/testutil"
+	"github.com/dogecoinfoundation/dogetest/pkg/txbuilder"
+)
+
+// TestHealDoubleSpend spends one output differently on each side of a
+// partition, lets the second side mine more, and checks that Heal reports
+// it as the winner and the first side's spend as dropped.
+func TestHealDoubleSpend(t *testing.T) {
+	cluster := testutil.NewCluster(t, dogetest.ClusterConfig{Nodes: 2, Topology: dogetest.Line})
+	book := testutil.Addresses(t, cluster.Nodes[0], []dogetest.AddressSetup{{Label: "double", InitialBalance: 10}})
+	from := book.Addresses[0]
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	if err := cluster.SyncBlocks(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	wallet, err := cluster.Nodes[0].GetWallet(from.Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(wallet.Unspents) == 0 {
+		t.Fatalf("no unspent outputs at %s", from.Address)
+	}
+	utxo := wallet.Unspents[0]
+
+	// spend pays utxo to a new key, so each call conflicts with the others.
+	spend := func() *doge.Tx {
+		t.Helper()
+		to, err := doge.GenerateKeyPair(&doge.RegTestParams)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b := txbuilder.New()
+		if err := b.AddP2PKHInput(utxo, from.PrivateKey); err != nil {
+			t.Fatal(err)
+		}
+		if err := b.SetChange(to.Address().String(), txbuilder.DefaultFeeRate); err != nil {
+			t.Fatal(err)
+		}
+		tx, err := b.Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tx
+	}
+
+	if err := cluster.Partition([]int{0}, []int{1}); err != nil {
+		t.Fatal(err)
+	}
+
+	txA, err := txbuilder.Broadcast(cluster.Rpc(0), spend())
+	if err != nil {
+		t.Fatal(err)
+	}
+	txB, err := txbuilder.Broadcast(cluster.Rpc(1), spend())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cluster.Nodes[0].Generate(1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cluster.Nodes[1].Generate(2); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := cluster.Heal(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.Winner != 1 {
+		t.Errorf("Winner = %d, want 1, the side with more work", report.Winner)
+	}
+	if report.BestBlock != report.Tips[1] {
+		t.Errorf("BestBlock = %s, want side 1's tip %s", report.BestBlock, report.Tips[1])
+	}
+	if !slices.Equal(report.Dropped, []string{txA}) {
+		t.Errorf("Dropped = %v, want [%s]", report.Dropped, txA)
+	}
+	if slices.Contains(report.Dropped, txB) {
+		t.Errorf("the winning spend %s was dropped", txB)
+	}
+}
@@ -0,0 +1,313 @@
+package dogetest
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dogecoinfoundation/dogetest/pkg/rpc"
+)
+
+// processTimeout bounds how long the backends wait for dogecoind to open its
+// RPC port, and to exit on Stop or after encryptwallet. Tests shorten it.
+var processTimeout = 30 * time.Second
+
+// processBackend runs a local dogecoind as a child process, with a
+// temporary data directory that Stop removes.
+type processBackend struct {
+	d       *DogeTest
+	datadir string
+	rpcPort int
+	p2pPort int
+
+	cmd     *exec.Cmd
+	exited  chan struct{}
+	logFile *os.File
+}
+
+func (p *processBackend) start(ctx context.Context) (*rpc.Config, error) {
+	datadir, err := os.MkdirTemp("", "dogetest-node-")
+	if err != nil {
+		return nil, err
+	}
+	p.datadir = datadir
+
+	p.rpcPort, err = freePort()
+	if err != nil {
+		return nil, err
+	}
+	p.p2pPort, err = freePort()
+	if err != nil {
+		return nil, err
+	}
+
+	conf := p.d.confFile([]string{
+		"regtest=1",
+		"server=1",
+		"rpcuser=test",
+		"rpcpassword=test",
+		"rpcport=" + strconv.Itoa(p.rpcPort),
+		"port=" + strconv.Itoa(p.p2pPort),
+		"rpcbind=127.0.0.1",
+		"rpcallowip=127.0.0.1",
+		"listenonion=0",
+	})
+	err = os.WriteFile(filepath.Join(datadir, "dogecoin.conf"), conf, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.launch(ctx)
+}
+
+// launch starts dogecoind on the data directory and waits until its RPC
+// port accepts connections. Output goes to console.log in the data
+// directory and, with LogContainers or Log, to the standard logger or Log.
+func (p *processBackend) launch(ctx context.Context) (*rpc.Config, error) {
+	logPath := filepath.Join(p.datadir, "console.log")
+	logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	p.logFile = logFile
+
+	logLine := p.d.logLine()
+
+	var output io.Writer = logFile
+	var pipe *io.PipeWriter
+	scanned := make(chan struct{})
+	if logLine != nil {
+		var reader *io.PipeReader
+		reader, pipe = io.Pipe()
+		output = io.MultiWriter(logFile, pipe)
+		go func() {
+			scanner := bufio.NewScanner(reader)
+			for scanner.Scan() {
+				logLine(scanner.Text())
+			}
+			close(scanned)
+		}()
+	} else {
+		close(scanned)
+	}
+
+	args := append([]string{
+		"-datadir=" + p.datadir,
+		"-conf=" + filepath.Join(p.datadir, "dogecoin.conf"),
+		"-printtoconsole",
+	}, p.d.config.Args...)
+	cmd := exec.Command(p.d.config.Binary, args...)
+	cmd.Stdout = output
+	cmd.Stderr = output
+
+	err = cmd.Start()
+	if err != nil {
+		if pipe != nil {
+			pipe.Close()
+		}
+		return nil, err
+	}
+	p.cmd = cmd
+
+	exited := make(chan struct{})
+	p.exited = exited
+	go func() {
+		cmd.Wait()
+		if pipe != nil {
+			pipe.Close()
+		}
+		// Every line is logged before the node counts as exited, so none
+		// is logged after Stop returns.
+		<-scanned
+		close(exited)
+	}()
+
+	address := "127.0.0.1:" + strconv.Itoa(p.rpcPort)
+	p.d.logf("dogetest: dogecoind at %s (pid %d, datadir %s)", address, cmd.Process.Pid, p.datadir)
+
+	deadline := time.Now().Add(processTimeout)
+	for {
+		select {
+		case <-exited:
+			return nil, fmt.Errorf("dogecoind exited during startup: %v\n%s", cmd.ProcessState, logTail(logPath, 20))
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+
+		conn, err := net.DialTimeout("tcp", address, time.Second)
+		if err == nil {
+			conn.Close()
+			return localRpcConfig(address), nil
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("dogecoind rpc port not open after %v\n%s", processTimeout, logTail(logPath, 20))
+		}
+
+		time.Sleep(200 * time.Millisecond)
+	}
+}
+
+// restart waits for dogecoind to exit, as it does after encryptwallet, and
+// starts it again on the same data directory and ports.
+func (p *processBackend) restart(ctx context.Context) (*rpc.Config, error) {
+	select {
+	case <-p.exited:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(processTimeout):
+		return nil, fmt.Errorf("dogecoind did not exit within %v", processTimeout)
+	}
+	p.logFile.Close()
+
+	return p.launch(ctx)
+}
+
+// snapshot stops dogecoind, copies its regtest data directory to the
+// snapshot's directory and starts it again.
+func (p *processBackend) snapshot(ctx context.Context, name string) (*rpc.Config, error) {
+	dir, err := snapshotDir(name)
+	if err != nil {
+		return nil, err
+	}
+
+	err = p.shutdown(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.RemoveAll(dir)
+	if err != nil {
+		return nil, err
+	}
+	err = os.CopyFS(dir, os.DirFS(filepath.Join(p.datadir, "regtest")))
+	if err != nil {
+		return nil, err
+	}
+
+	return p.launch(ctx)
+}
+
+// restore stops dogecoind, replaces its regtest data directory with the
+// snapshot's and starts it again.
+func (p *processBackend) restore(ctx context.Context, name string) (*rpc.Config, error) {
+	dir, err := snapshotDir(name)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(dir); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrNoSnapshot, name)
+	}
+
+	err = p.shutdown(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	regtest := filepath.Join(p.datadir, "regtest")
+	err = os.RemoveAll(regtest)
+	if err != nil {
+		return nil, err
+	}
+	err = os.CopyFS(regtest, os.DirFS(dir))
+	if err != nil {
+		return nil, err
+	}
+
+	return p.launch(ctx)
+}
+
+// shutdown stops dogecoind over RPC, so its data directory is flushed, and
+// waits for it to exit.
+func (p *processBackend) shutdown(ctx context.Context) error {
+	_, err := p.d.Rpc.Stop()
+	if err != nil {
+		return err
+	}
+
+	select {
+	case <-p.exited:
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(processTimeout):
+		return fmt.Errorf("dogecoind did not exit within %v", processTimeout)
+	}
+	p.logFile.Close()
+
+	return nil
+}
+
+// stop interrupts dogecoind, which shuts down cleanly on SIGINT, kills it
+// if it does not exit in time, and removes the data directory.
+func (p *processBackend) stop() error {
+	if p.cmd != nil {
+		select {
+		case <-p.exited:
+		default:
+			if err := p.cmd.Process.Signal(os.Interrupt); err != nil {
+				// Interrupt is not supported on Windows.
+				p.cmd.Process.Kill()
+			}
+
+			select {
+			case <-p.exited:
+			case <-time.After(processTimeout):
+				p.cmd.Process.Kill()
+				<-p.exited
+			}
+		}
+		p.logFile.Close()
+	}
+
+	if p.datadir != "" {
+		return os.RemoveAll(p.datadir)
+	}
+
+	return nil
+}
+
+// logTail returns the last n lines of a log file, for startup errors.
+func logTail(path string, n int) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// usedPorts holds the ports freePort has handed out, so nodes started in
+// parallel never get the same one before either has bound it.
+var usedPorts sync.Map
+
+// freePort asks the OS for a TCP port that is free at the moment and was
+// not handed out before.
+func freePort() (int, error) {
+	for {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			return 0, err
+		}
+		port := listener.Addr().(*net.TCPAddr).Port
+		listener.Close()
+
+		if _, used := usedPorts.LoadOrStore(port, true); !used {
+			return port, nil
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package dogetest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// stubEnv makes the test binary act as dogecoind, see runStub.
+const stubEnv = "DOGETEST_STUB"
+
+func TestMain(m *testing.M) {
+	if mode := os.Getenv(stubEnv); mode != "" {
+		os.Exit(runStub(mode, os.Args[1:]))
+	}
+
+	os.Exit(m.Run())
+}
+
+// runStub stands in for dogecoind. It logs startup lines, then in mode
+// "fail" exits with status 1; otherwise it accepts connections on the
+// rpcport of its -conf and, in mode "serve", exits on SIGINT, while in mode
+// "stubborn" it ignores SIGINT until killed.
+func runStub(mode string, args []string) int {
+	for i := 1; i <= 30; i++ {
+		fmt.Printf("stub line %d\n", i)
+	}
+	if mode == "fail" {
+		fmt.Fprintln(os.Stderr, "Error: much failure")
+		return 1
+	}
+
+	var port string
+	for _, arg := range args {
+		conf, ok := strings.CutPrefix(arg, "-conf=")
+		if !ok {
+			continue
+		}
+		data, err := os.ReadFile(conf)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			if value, ok := strings.CutPrefix(line, "rpcport="); ok {
+				port = value
+			}
+		}
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	if mode == "stubborn" {
+		signal.Ignore(os.Interrupt)
+	} else {
+		signal.Notify(interrupt, os.Interrupt)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:"+port)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	<-interrupt
+	fmt.Println("Shutdown: done")
+	return 0
+}
+
+// newStubBackend returns a process backend running the test binary as
+// dogecoind in mode, and the lines passed to Log.
+func newStubBackend(t *testing.T, mode string) (*processBackend, func() []string) {
+	t.Helper()
+	t.Setenv(stubEnv, mode)
+
+	var mu sync.Mutex
+	var lines []string
+	d := &DogeTest{config: DogeTestConfig{
+		Binary: os.Args[0],
+		Log: func(line string) {
+			mu.Lock()
+			lines = append(lines, line)
+			mu.Unlock()
+		},
+	}}
+
+	return &processBackend{d: d}, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return slices.Clone(lines)
+	}
+}
+
+func TestProcessStartupFailure(t *testing.T) {
+	p, _ := newStubBackend(t, "fail")
+
+	_, err := p.start(context.Background())
+	if err == nil {
+		t.Fatal("start succeeded, want an error")
+	}
+
+	msg := err.Error()
+	if !strings.Contains(msg, "dogecoind exited during startup") {
+		t.Errorf("err = %q, want it to say dogecoind exited", msg)
+	}
+	// The last 20 lines of output: stub lines 12 to 30 and the error.
+	if !strings.Contains(msg, "stub line 12\n") || !strings.Contains(msg, "Error: much failure") {
+		t.Errorf("err = %q, want the end of the output", msg)
+	}
+	if strings.Contains(msg, "stub line 11\n") {
+		t.Errorf("err = %q, want only the last 20 lines", msg)
+	}
+
+	if err := p.stop(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(p.datadir); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("datadir %s not removed: %v", p.datadir, err)
+	}
+}
+
+func TestProcessStop(t *testing.T) {
+	p, lines := newStubBackend(t, "serve")
+
+	config, err := p.start(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := fmt.Sprintf("127.0.0.1:%d", p.rpcPort); !strings.Contains(config.RpcUrl, want) {
+		t.Errorf("RpcUrl = %s, want it at %s", config.RpcUrl, want)
+	}
+
+	start := time.Now()
+	if err := p.stop(); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > processTimeout/2 {
+		t.Errorf("stop took %v, want dogecoind to exit on SIGINT", elapsed)
+	}
+
+	got := strings.Join(lines(), "\n")
+	for _, want := range []string{"dogetest: dogecoind at 127.0.0.1:", "stub line 30", "Shutdown: done"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Log got %q, want %q", got, want)
+		}
+	}
+
+	if _, err := os.Stat(p.datadir); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("datadir %s not removed: %v", p.datadir, err)
+	}
+}
+
+func TestProcessStopKill(t *testing.T) {
+	timeout := processTimeout
+	processTimeout = time.Second
+	t.Cleanup(func() { processTimeout = timeout })
+
+	p, lines := newStubBackend(t, "stubborn")
+
+	if _, err := p.start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := p.stop(); err != nil {
+		t.Fatal(err)
+	}
+	if state := p.cmd.ProcessState.String(); state != "signal: killed" {
+		t.Errorf("dogecoind ended with %s, want it killed after ignoring SIGINT", state)
+	}
+	if got := strings.Join(lines(), "\n"); strings.Contains(got, "Shutdown: done") {
+		t.Errorf("Log got %q, want no clean shutdown", got)
+	}
+
+	if _, err := os.Stat(p.datadir); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("datadir %s not removed: %v", p.datadir, err)
+	}
+}
+
+func TestFreePort(t *testing.T) {
+	var mu sync.Mutex
+	seen := map[int]bool{}
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			port, err := freePort()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if seen[port] {
+				t.Errorf("port %d handed out twice", port)
+			}
+			seen[port] = true
+		}()
+	}
+	wg.Wait()
+
+	for port := range seen {
+		listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+		if err != nil {
+			t.Errorf("port %d is not free: %v", port, err)
+			continue
+		}
+		listener.Close()
+	}
+}
@@ -0,0 +1,278 @@
+package dogetest
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	_ "embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+//go:embed Dockerfile.local
+var localDockerfileData []byte
+
+var ErrChecksumMismatch = errors.New("release checksum mismatch")
+
+// ReleaseChecksums maps the file names of official Dogecoin Core release
+// tarballs to their hex SHA-256, so a listed tarball is verified without
+// DogeTestConfig.ReleaseSHA256. It starts empty: fill it with
+// AddReleaseChecksums, or by hand, from a release's SHA256SUMS.asc after
+// checking its signature.
+var ReleaseChecksums = map[string]string{}
+
+// sumsLine matches a line of a SHA256SUMS file: the hex digest, then the
+// file name, marked with * in binary mode.
+var sumsLine = regexp.MustCompile(`^([0-9a-fA-F]{64}) [ *](\S+)$`)
+
+// AddReleaseChecksums adds the entries of a SHA256SUMS file, or of the
+// clearsigned SHA256SUMS.asc, to ReleaseChecksums. It does not check the
+// signature; do that first. Call it before starting nodes, for example in
+// TestMain.
+func AddReleaseChecksums(sums io.Reader) error {
+	scanner := bufio.NewScanner(sums)
+	added := 0
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "-----BEGIN PGP SIGNATURE-----" {
+			break
+		}
+
+		m := sumsLine.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		ReleaseChecksums[path.Base(m[2])] = strings.ToLower(m[1])
+		added++
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if added == 0 {
+		return errors.New("no checksums found")
+	}
+
+	return nil
+}
+
+// releaseBuild builds an image from the local release in
+// DogeTestConfig.Release, verifying a tarball's checksum first.
+func (d *DogeTest) releaseBuild() (*imageBuild, error) {
+	release := d.config.Release
+
+	info, err := os.Stat(release)
+	if err != nil {
+		return nil, err
+	}
+
+	var sum string
+	if info.IsDir() {
+		sum, err = hashBinaries(releaseBinDir(release))
+	} else {
+		sum, err = d.verifyRelease(release)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &imageBuild{
+		dockerfile: localDockerfileData,
+		buildArgs:  map[string]*string{"RELEASE_SHA256": &sum},
+		prepare: func() (string, error) {
+			return writeReleaseContext(release, info.IsDir())
+		},
+	}, nil
+}
+
+// verifyRelease checks the SHA-256 of a release tarball against
+// DogeTestConfig.ReleaseSHA256, or ReleaseChecksums if that is empty, and
+// returns it.
+func (d *DogeTest) verifyRelease(tarball string) (string, error) {
+	expected := d.config.ReleaseSHA256
+	if expected == "" {
+		expected = ReleaseChecksums[filepath.Base(tarball)]
+	}
+	if expected == "" {
+		return "", fmt.Errorf("no known checksum for %s: set ReleaseSHA256 or add it to ReleaseChecksums", filepath.Base(tarball))
+	}
+
+	f, err := os.Open(tarball)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	sum := hex.EncodeToString(h.Sum(nil))
+
+	if !strings.EqualFold(sum, expected) {
+		return "", fmt.Errorf("%w: %s has %s, expected %s", ErrChecksumMismatch, filepath.Base(tarball), sum, expected)
+	}
+
+	return sum, nil
+}
+
+// writeReleaseContext creates a build context holding Dockerfile.local and
+// the release binaries in bin/, and returns the Dockerfile's path.
+func writeReleaseContext(release string, isDir bool) (string, error) {
+	dir, err := os.MkdirTemp("", "dogetest-release-")
+	if err != nil {
+		return "", err
+	}
+
+	binDir := filepath.Join(dir, "bin")
+	if err := os.Mkdir(binDir, 0755); err != nil {
+		return "", err
+	}
+
+	if isDir {
+		err = copyBinaries(releaseBinDir(release), binDir)
+	} else {
+		err = extractBinaries(release, binDir)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := os.Stat(filepath.Join(binDir, "dogecoind")); err != nil {
+		return "", fmt.Errorf("release %s has no dogecoind", release)
+	}
+
+	dockerfilePath := filepath.Join(dir, "Dockerfile")
+	if err := os.WriteFile(dockerfilePath, localDockerfileData, 0644); err != nil {
+		return "", err
+	}
+
+	return dockerfilePath, nil
+}
+
+// releaseBinDir returns the bin directory of an unpacked release, or dir
+// itself if it holds the binaries directly.
+func releaseBinDir(dir string) string {
+	if _, err := os.Stat(filepath.Join(dir, "bin", "dogecoind")); err == nil {
+		return filepath.Join(dir, "bin")
+	}
+	return dir
+}
+
+// extractBinaries unpacks the files under bin/ of a release tarball.
+func extractBinaries(tarball string, binDir string) error {
+	f, err := os.Open(tarball)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if hdr.Typeflag != tar.TypeReg || path.Base(path.Dir(hdr.Name)) != "bin" {
+			continue
+		}
+
+		err = writeFile(filepath.Join(binDir, path.Base(hdr.Name)), tr, 0755)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// copyBinaries copies the regular files of src into binDir.
+func copyBinaries(src string, binDir string) error {
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+
+		f, err := os.Open(filepath.Join(src, entry.Name()))
+		if err != nil {
+			return err
+		}
+		err = writeFile(filepath.Join(binDir, entry.Name()), f, 0755)
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// hashBinaries hashes the names and contents of the regular files in dir,
+// to identify a binaries directory in the image's content hash.
+func hashBinaries(dir string) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if entry.Type().IsRegular() {
+			names = append(names, entry.Name())
+		}
+	}
+	if !slices.Contains(names, "dogecoind") {
+		return "", fmt.Errorf("release %s has no dogecoind", dir)
+	}
+
+	h := sha256.New()
+	for _, name := range names {
+		f, err := os.Open(filepath.Join(dir, name))
+		if err != nil {
+			return "", err
+		}
+		h.Write([]byte(name + "\x00"))
+		_, err = io.Copy(h, f)
+		f.Close()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func writeFile(name string, r io.Reader, mode os.FileMode) error {
+	f, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
@@ -0,0 +1,286 @@
+package dogetest
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// releaseName is the file name of the synthetic release tarballs.
+const releaseName = "dogecoin-1.14.9-x86_64-linux-gnu.tar.gz"
+
+// writeTarball writes a tar.gz to dir under name holding files, with
+// directories, a symlink and files outside bin/ that extractBinaries skips,
+// and returns its path and hex SHA-256.
+func writeTarball(t *testing.T, dir string, name string, files map[string]string) (string, string) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+
+	add := func(hdr *tar.Header, data string) {
+		hdr.Size = int64(len(data))
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	add(&tar.Header{Name: "dogecoin-1.14.9/", Typeflag: tar.TypeDir, Mode: 0755}, "")
+	add(&tar.Header{Name: "dogecoin-1.14.9/bin/", Typeflag: tar.TypeDir, Mode: 0755}, "")
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		add(&tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0644}, files[name])
+	}
+	add(&tar.Header{Name: "dogecoin-1.14.9/bin/dogecoin-link", Typeflag: tar.TypeSymlink, Linkname: "dogecoind"}, "")
+
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(buf.Bytes())
+
+	return path, hex.EncodeToString(sum[:])
+}
+
+var releaseFiles = map[string]string{
+	"dogecoin-1.14.9/bin/dogecoind":               "much node",
+	"dogecoin-1.14.9/bin/dogecoin-cli":            "such cli",
+	"dogecoin-1.14.9/include/dogecoinconsensus.h": "wow header",
+	"dogecoin-1.14.9/share/man/man1/dogecoind.1":  "very manual",
+}
+
+func TestVerifyRelease(t *testing.T) {
+	tarball, sum := writeTarball(t, t.TempDir(), releaseName, releaseFiles)
+	other := strings.Repeat("0", 64)
+
+	saved := ReleaseChecksums
+	t.Cleanup(func() { ReleaseChecksums = saved })
+
+	tests := []struct {
+		name      string
+		configSum string
+		table     map[string]string
+		wantErr   error
+		errText   string
+	}{
+		{name: "config matches", configSum: sum},
+		{name: "config matches in upper case", configSum: strings.ToUpper(sum)},
+		{name: "config mismatch", configSum: other, wantErr: ErrChecksumMismatch},
+		{name: "table matches", table: map[string]string{releaseName: sum}},
+		{name: "table mismatch", table: map[string]string{releaseName: other}, wantErr: ErrChecksumMismatch},
+		{name: "config overrides table", configSum: sum, table: map[string]string{releaseName: other}},
+		{name: "unknown", table: map[string]string{"dogecoin-1.14.8-x86_64-linux-gnu.tar.gz": sum}, errText: "no known checksum"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ReleaseChecksums = tt.table
+			if ReleaseChecksums == nil {
+				ReleaseChecksums = map[string]string{}
+			}
+			d := &DogeTest{config: DogeTestConfig{Release: tarball, ReleaseSHA256: tt.configSum}}
+
+			got, err := d.verifyRelease(tarball)
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+			case tt.errText != "":
+				if err == nil || !strings.Contains(err.Error(), tt.errText) {
+					t.Fatalf("err = %v, want %q", err, tt.errText)
+				}
+			case err != nil:
+				t.Fatal(err)
+			case got != sum:
+				t.Errorf("sum = %s, want %s", got, sum)
+			}
+		})
+	}
+}
+
+func TestAddReleaseChecksums(t *testing.T) {
+	saved := ReleaseChecksums
+	ReleaseChecksums = map[string]string{}
+	t.Cleanup(func() { ReleaseChecksums = saved })
+
+	a := strings.Repeat("ab", 32)
+	b := strings.Repeat("CD", 32)
+	sums := "-----BEGIN PGP SIGNED MESSAGE-----\n" +
+		"Hash: SHA256\n" +
+		"\n" +
+		a + "  " + releaseName + "\r\n" +
+		b + " *dogecoin-1.14.9-win64.zip\n" +
+		"-----BEGIN PGP SIGNATURE-----\n" +
+		strings.Repeat("ef", 32) + "  not-a-release.tar.gz\n" +
+		"-----END PGP SIGNATURE-----\n"
+
+	if err := AddReleaseChecksums(strings.NewReader(sums)); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		releaseName:                 a,
+		"dogecoin-1.14.9-win64.zip": strings.ToLower(b),
+	}
+	if !maps.Equal(ReleaseChecksums, want) {
+		t.Errorf("ReleaseChecksums = %v, want %v", ReleaseChecksums, want)
+	}
+
+	if err := AddReleaseChecksums(strings.NewReader("Hash: SHA256\n")); err == nil {
+		t.Error("got no error for a file without checksums")
+	}
+}
+
+func TestExtractBinaries(t *testing.T) {
+	dir := t.TempDir()
+	tarball, _ := writeTarball(t, dir, releaseName, releaseFiles)
+	binDir := filepath.Join(dir, "bin")
+	if err := os.Mkdir(binDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := extractBinaries(tarball, binDir); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{"dogecoind": "much node", "dogecoin-cli": "such cli"}
+	if got := readBinaries(t, binDir); !maps.Equal(got, want) {
+		t.Errorf("extracted %v, want %v", got, want)
+	}
+	info, err := os.Stat(filepath.Join(binDir, "dogecoind"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0755 {
+		t.Errorf("dogecoind mode = %v, want 0755", info.Mode().Perm())
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "broken.tar.gz"), []byte("not gzip"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := extractBinaries(filepath.Join(dir, "broken.tar.gz"), binDir); err == nil {
+		t.Error("got no error for a file that is not a tar.gz")
+	}
+}
+
+func TestCopyBinaries(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "dogecoin-1.14.9")
+	srcBin := filepath.Join(src, "bin")
+	if err := os.MkdirAll(filepath.Join(srcBin, "plugins"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, data := range map[string]string{"dogecoind": "much node", "dogecoin-tx": "such tx"} {
+		if err := os.WriteFile(filepath.Join(srcBin, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink("dogecoind", filepath.Join(srcBin, "dogecoin-link")); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := releaseBinDir(src); got != srcBin {
+		t.Errorf("releaseBinDir(%s) = %s, want its bin", src, got)
+	}
+	if got := releaseBinDir(srcBin); got != srcBin {
+		t.Errorf("releaseBinDir(%s) = %s, want itself", srcBin, got)
+	}
+
+	binDir := t.TempDir()
+	if err := copyBinaries(srcBin, binDir); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"dogecoind": "much node", "dogecoin-tx": "such tx"}
+	if got := readBinaries(t, binDir); !maps.Equal(got, want) {
+		t.Errorf("copied %v, want %v", got, want)
+	}
+}
+
+func TestHashBinaries(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, data string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	hash := func() string {
+		t.Helper()
+		sum, err := hashBinaries(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sum
+	}
+
+	write("dogecoin-cli", "such cli")
+	if _, err := hashBinaries(dir); err == nil || !strings.Contains(err.Error(), "no dogecoind") {
+		t.Fatalf("err = %v, want an error without dogecoind", err)
+	}
+
+	write("dogecoind", "much node")
+	base := hash()
+	if hash() != base {
+		t.Error("the same binaries hashed differently")
+	}
+
+	write("dogecoind", "much other node")
+	changed := hash()
+	if changed == base {
+		t.Error("a changed binary kept the hash")
+	}
+
+	// Names are hashed, so moving bytes between files changes the hash.
+	write("dogecoin-cli", "such climuch other node")
+	write("dogecoind", "")
+	if hash() == changed {
+		t.Error("moving content between binaries kept the hash")
+	}
+
+	if err := os.Mkdir(filepath.Join(dir, "plugins"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	before := hash()
+	if err := os.WriteFile(filepath.Join(dir, "plugins", "x"), []byte("wow"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if hash() != before {
+		t.Error("a file in a subdirectory changed the hash")
+	}
+}
+
+// readBinaries returns the names and contents of the files in dir.
+func readBinaries(t *testing.T, dir string) map[string]string {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{}
+	for _, entry := range entries {
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[entry.Name()] = string(data)
+	}
+	return files
+}
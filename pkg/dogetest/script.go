@@ -0,0 +1,57 @@
+package dogetest
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"slices"
+
+	"github.com/dogecoinfoundation/dogetest/pkg/doge"
+)
+
+// CheckScript decodes script both with the Go parser in pkg/doge and with the
+// node's decodescript, and returns an error describing the first field on
+// which they disagree.
+func (d *DogeTest) CheckScript(script []byte) error {
+	ops, err := doge.ParseScript(script)
+	if err == nil && !bytes.Equal(doge.AssembleScript(ops), script) {
+		return fmt.Errorf("script %x does not round-trip: %x", script, doge.AssembleScript(ops))
+	}
+
+	decoded, err := d.Rpc.DecodeScript(hex.EncodeToString(script))
+	if err != nil {
+		return err
+	}
+
+	asm := doge.Disassemble(script)
+	if asm != decoded.Asm {
+		return fmt.Errorf("asm mismatch: go %q, node %q", asm, decoded.Asm)
+	}
+
+	info := doge.ClassifyScript(script)
+	if info.Class.String() != decoded.Type {
+		return fmt.Errorf("type mismatch: go %q, node %q", info.Class, decoded.Type)
+	}
+
+	if info.Class != doge.ScriptHashTy {
+		p2sh := doge.Base58CheckEncode(doge.RegTestParams.ScriptHashAddrID, doge.Hash160(script))
+		if p2sh != decoded.P2SH {
+			return fmt.Errorf("p2sh mismatch: go %q, node %q", p2sh, decoded.P2SH)
+		}
+	}
+
+	if info.Class == doge.NonStandardTy || info.Class == doge.NullDataTy {
+		return nil
+	}
+
+	if int64(info.RequiredSigs) != decoded.ReqSigs {
+		return fmt.Errorf("reqSigs mismatch: go %d, node %d", info.RequiredSigs, decoded.ReqSigs)
+	}
+
+	addresses := info.Addresses(&doge.RegTestParams)
+	if !slices.Equal(addresses, decoded.Addresses) {
+		return fmt.Errorf("addresses mismatch: go %v, node %v", addresses, decoded.Addresses)
+	}
+
+	return nil
+}
@@ -0,0 +1,154 @@
+package dogetest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sync"
+
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/client"
+	"github.com/testcontainers/testcontainers-go"
+)
+
+var (
+	// ErrNoSnapshot is returned by Restore for a name no node has
+	// snapshotted.
+	ErrNoSnapshot = errors.New("no such snapshot")
+	// ErrNotRunning is returned by Snapshot and Restore before Start has
+	// succeeded, or after Stop.
+	ErrNotRunning = errors.New("node is not running")
+)
+
+// snapshotRepo is the repository of container snapshot images, tagged with
+// the session and the snapshot's name.
+const snapshotRepo = "dogetest/snapshot"
+
+// snapshotName restricts names to what is valid in an image tag and a file
+// name.
+var snapshotName = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.-]{0,63}$`)
+
+var (
+	// snapshotMu guards snapshotRoot, the temporary directory of the
+	// process's snapshots, created by the first one, and snapshotImages,
+	// the images committed by container snapshots.
+	snapshotMu     sync.Mutex
+	snapshotRoot   string
+	snapshotImages []string
+)
+
+// Snapshot saves the node's chain and wallet under name, so that Restore can
+// return this node, or another started with the same backend in this
+// process, to the same state without mining or funding again. The node is
+// stopped while its data is copied, which drops its peers and mock time.
+//
+// Container snapshots are images, process snapshots are copies of the
+// regtest data directory; RemoveSnapshots deletes both. Containers with
+// volumes, such as from a user Dockerfile with VOLUME, cannot be
+// snapshotted.
+func (d *DogeTest) Snapshot(name string) error {
+	if !snapshotName.MatchString(name) {
+		return fmt.Errorf("invalid snapshot name %q", name)
+	}
+	if !d.running {
+		return fmt.Errorf("snapshot %s: %w", name, ErrNotRunning)
+	}
+
+	ctx := context.Background()
+
+	rpcConfig, err := d.backend.snapshot(ctx, name)
+	if err != nil {
+		return fmt.Errorf("snapshot %s: %w", name, err)
+	}
+
+	return d.connectRpc(ctx, rpcConfig)
+}
+
+// Restore restarts the node from the snapshot saved under name, replacing
+// its chain and wallet. Addresses set up before the snapshot can be used
+// again.
+func (d *DogeTest) Restore(name string) error {
+	if !snapshotName.MatchString(name) {
+		return fmt.Errorf("invalid snapshot name %q", name)
+	}
+	if !d.running {
+		return fmt.Errorf("restore %s: %w", name, ErrNotRunning)
+	}
+
+	ctx := context.Background()
+
+	rpcConfig, err := d.backend.restore(ctx, name)
+	if err != nil {
+		return fmt.Errorf("restore %s: %w", name, err)
+	}
+
+	return d.connectRpc(ctx, rpcConfig)
+}
+
+// RemoveSnapshots deletes the snapshots taken in this process, for
+// example at the end of TestMain.
+func RemoveSnapshots() error {
+	snapshotMu.Lock()
+	root, images := snapshotRoot, snapshotImages
+	snapshotRoot, snapshotImages = "", nil
+	snapshotMu.Unlock()
+
+	var errs []error
+	if root != "" {
+		errs = append(errs, os.RemoveAll(root))
+	}
+
+	if len(images) > 0 {
+		ctx := context.Background()
+		cli, err := testcontainers.NewDockerClientWithOpts(ctx)
+		if err != nil {
+			return errors.Join(append(errs, err)...)
+		}
+		defer cli.Close()
+
+		for _, ref := range images {
+			_, err := cli.ImageRemove(ctx, ref, image.RemoveOptions{})
+			if err != nil && !client.IsErrNotFound(err) {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// snapshotImage is the image a container snapshot is committed to.
+func snapshotImage(name string) string {
+	return snapshotRepo + ":" + session + "-" + name
+}
+
+// addSnapshotImage records a committed image for RemoveSnapshots.
+func addSnapshotImage(ref string) {
+	snapshotMu.Lock()
+	defer snapshotMu.Unlock()
+
+	if !slices.Contains(snapshotImages, ref) {
+		snapshotImages = append(snapshotImages, ref)
+	}
+}
+
+// snapshotDir is the directory a process snapshot is copied to, in a
+// temporary directory shared by the process's nodes.
+func snapshotDir(name string) (string, error) {
+	snapshotMu.Lock()
+	defer snapshotMu.Unlock()
+
+	if snapshotRoot == "" {
+		root, err := os.MkdirTemp("", "dogetest-snapshots-")
+		if err != nil {
+			return "", err
+		}
+		snapshotRoot = root
+	}
+
+	return filepath.Join(snapshotRoot, name), nil
+}
@@ -0,0 +1,114 @@
+package dogetest_test
+
+import (
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/dogecoinfoundation/dogetest/pkg/doge"
+	"github.com/dogecoinfoundation/dogetest/pkg/dogetest"
+	"github.com/dogecoinfoundation/dogetest/pkg/dogetest/testutil"
+	"github.com/dogecoinfoundation/dogetest/pkg/txbuilder"
+)
+
+// TestSnapshotRestore funds an address, snapshots the node, spends the
+// funds and checks that Restore brings them back, on a local dogecoind.
+func TestSnapshotRestore(t *testing.T) {
+	path, err := exec.LookPath("dogecoind")
+	if err != nil {
+		t.Skip("dogecoind is not in PATH")
+	}
+	t.Cleanup(func() {
+		if err := dogetest.RemoveSnapshots(); err != nil {
+			t.Errorf("remove snapshots: %v", err)
+		}
+	})
+
+	node := testutil.New(t, testutil.WithBinary(path))
+	book := testutil.Addresses(t, node, []dogetest.AddressSetup{{Label: "snapshot", InitialBalance: 10}})
+	from := book.Addresses[0]
+
+	balance := func() float64 {
+		t.Helper()
+		wallet, err := node.GetWallet(from.Address)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return wallet.GetBalance()
+	}
+
+	funded := balance()
+	if funded == 0 {
+		t.Fatalf("no funds at %s", from.Address)
+	}
+
+	if err := node.Snapshot("funded"); err != nil {
+		t.Fatal(err)
+	}
+
+	to, err := doge.GenerateKeyPair(&doge.RegTestParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wallet, err := node.GetWallet(from.Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := txbuilder.New()
+	for _, utxo := range wallet.Unspents {
+		if err := b.AddP2PKHInput(utxo, from.PrivateKey); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := b.SetChange(to.Address().String(), txbuilder.DefaultFeeRate); err != nil {
+		t.Fatal(err)
+	}
+	tx, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := txbuilder.Broadcast(node.Rpc, tx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := node.ConfirmBlocks(); err != nil {
+		t.Fatal(err)
+	}
+	if spent := balance(); spent != 0 {
+		t.Fatalf("balance after spending = %v, want 0", spent)
+	}
+
+	if err := node.Restore("funded"); err != nil {
+		t.Fatal(err)
+	}
+	if restored := balance(); restored != funded {
+		t.Errorf("balance after restore = %v, want %v", restored, funded)
+	}
+}
+
+// TestSnapshotNotRunning checks that Snapshot and Restore fail cleanly on a
+// node that was never started or whose Start failed.
+func TestSnapshotNotRunning(t *testing.T) {
+	unstarted, err := dogetest.NewDogeTest(dogetest.DogeTestConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	failed, err := dogetest.NewDogeTest(dogetest.DogeTestConfig{Binary: filepath.Join(t.TempDir(), "dogecoind")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := failed.Start(); err == nil {
+		t.Fatal("Start succeeded without a dogecoind binary")
+	}
+	t.Cleanup(func() { failed.Stop() })
+
+	for name, node := range map[string]*dogetest.DogeTest{"unstarted": unstarted, "failed": failed} {
+		if err := node.Snapshot("much"); !errors.Is(err, dogetest.ErrNotRunning) {
+			t.Errorf("%s: Snapshot = %v, want ErrNotRunning", name, err)
+		}
+		if err := node.Restore("much"); !errors.Is(err, dogetest.ErrNotRunning) {
+			t.Errorf("%s: Restore = %v, want ErrNotRunning", name, err)
+		}
+	}
+}
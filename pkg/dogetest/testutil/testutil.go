@@ -0,0 +1,284 @@
+// Package testutil runs dogetest nodes from Go tests: New starts a node for
+// one test, and Main starts one node that a package's tests share through
+// Shared.
+package testutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dogecoinfoundation/dogetest/pkg/dogetest"
+	"github.com/testcontainers/testcontainers-go"
+)
+
+// Option changes the config of the node New or Main starts.
+type Option func(*dogetest.DogeTestConfig)
+
+// WithConfig starts the node with config, which later options change.
+func WithConfig(config dogetest.DogeTestConfig) Option {
+	return func(c *dogetest.DogeTestConfig) {
+		*c = config
+	}
+}
+
+// WithBinary runs a local dogecoind instead of a container, see
+// DogeTestConfig.Binary.
+func WithBinary(path string) Option {
+	return func(c *dogetest.DogeTestConfig) {
+		c.Binary = path
+	}
+}
+
+// WithConf adds dogecoin.conf lines, see DogeTestConfig.Conf.
+func WithConf(lines ...string) Option {
+	return func(c *dogetest.DogeTestConfig) {
+		c.Conf = append(c.Conf, lines...)
+	}
+}
+
+// WithArgs adds dogecoind arguments, see DogeTestConfig.Args.
+func WithArgs(args ...string) Option {
+	return func(c *dogetest.DogeTestConfig) {
+		c.Args = append(c.Args, args...)
+	}
+}
+
+func newConfig(opts []Option) dogetest.DogeTestConfig {
+	var config dogetest.DogeTestConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
+	return config
+}
+
+// New starts a node for t and stops it once t and its subtests are done.
+// The node's output goes to t.Log, which go test shows with -v or when t
+// fails. New skips t when Docker is not available, unless the node runs a
+// local Binary or attaches to a running node.
+func New(t testing.TB, opts ...Option) *dogetest.DogeTest {
+	t.Helper()
+
+	config := newConfig(opts)
+	config.Log = func(line string) {
+		t.Log(line)
+	}
+
+	if err := dockerAvailable(config); err != nil {
+		t.Skipf("dogetest: Docker is not available: %v", err)
+	}
+
+	node, err := dogetest.NewDogeTest(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Registered before Start, so a node that fails to start is removed.
+	t.Cleanup(func() {
+		if err := node.Stop(); err != nil {
+			t.Errorf("stop node: %v", err)
+		}
+	})
+
+	if err := node.Start(); err != nil {
+		t.Fatalf("start node: %v", err)
+	}
+
+	return node
+}
+
+// NewCluster starts a cluster for t and stops it once t and its subtests
+// are done. Its nodes' output goes to t.Log. NewCluster skips t when Docker
+// is not available.
+func NewCluster(t testing.TB, config dogetest.ClusterConfig) *dogetest.Cluster {
+	t.Helper()
+
+	config.Node.Log = func(line string) {
+		t.Log(line)
+	}
+
+	if err := dockerAvailable(config.Node); err != nil {
+		t.Skipf("dogetest: Docker is not available: %v", err)
+	}
+
+	cluster, err := dogetest.NewCluster(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		if err := cluster.Stop(); err != nil {
+			t.Errorf("stop cluster: %v", err)
+		}
+	})
+
+	if err := cluster.Start(); err != nil {
+		t.Fatalf("start cluster: %v", err)
+	}
+
+	return cluster
+}
+
+// shared is the node started by Main.
+var shared struct {
+	node *dogetest.DogeTest
+	// skip is why tests using the node are skipped, err why they fail.
+	skip string
+	err  error
+
+	// turn is held by the test using the node.
+	turn sync.Mutex
+	// mu guards holder, the name of that test.
+	mu     sync.Mutex
+	holder string
+}
+
+// Main starts one node, runs the package's tests and stops the node. Call
+// it from TestMain, and get the node in tests with Shared:
+//
+//	func TestMain(m *testing.M) {
+//		testutil.Main(m)
+//	}
+//
+// When Docker is not available, tests that call Shared are skipped and the
+// others still run. The node's output goes to the standard logger with
+// LogContainers. Snapshots taken by the package's tests are removed at the
+// end.
+func Main(m *testing.M, opts ...Option) {
+	code := run(m, newConfig(opts))
+
+	if err := dogetest.RemoveSnapshots(); err != nil {
+		log.Println("remove snapshots:", err)
+	}
+
+	os.Exit(code)
+}
+
+func run(m *testing.M, config dogetest.DogeTestConfig) int {
+	if err := dockerAvailable(config); err != nil {
+		shared.skip = fmt.Sprintf("dogetest: Docker is not available: %v", err)
+		return m.Run()
+	}
+
+	node, err := dogetest.NewDogeTest(config)
+	if err != nil {
+		shared.err = err
+		return m.Run()
+	}
+	defer func() {
+		if err := node.Stop(); err != nil {
+			log.Println("stop shared node:", err)
+		}
+	}()
+
+	if err := node.Start(); err != nil {
+		shared.err = fmt.Errorf("start shared node: %w", err)
+		return m.Run()
+	}
+	shared.node = node
+
+	return m.Run()
+}
+
+// Shared returns the node started by Main. Tests using it take turns, even
+// with t.Parallel, so one test's blocks and transactions never appear in
+// the middle of another; subtests of the test holding the node use it
+// directly. After each test the node's mock time is reset.
+//
+// Tests share the chain and the wallet, so each should fund its own
+// addresses with Addresses rather than rely on heights or balances.
+func Shared(t testing.TB) *dogetest.DogeTest {
+	t.Helper()
+
+	if shared.skip != "" {
+		t.Skip(shared.skip)
+	}
+	if shared.err != nil {
+		t.Fatal(shared.err)
+	}
+	if shared.node == nil {
+		t.Fatal("testutil.Shared needs testutil.Main in TestMain")
+	}
+
+	shared.mu.Lock()
+	holder := shared.holder
+	shared.mu.Unlock()
+	if holder != "" && (t.Name() == holder || strings.HasPrefix(t.Name(), holder+"/")) {
+		return shared.node
+	}
+
+	shared.turn.Lock()
+	shared.mu.Lock()
+	shared.holder = t.Name()
+	shared.mu.Unlock()
+
+	t.Cleanup(func() {
+		err := shared.node.SetMockTime(time.Time{})
+		if err != nil && !errors.Is(err, dogetest.ErrNotRegtest) {
+			t.Errorf("reset mock time: %v", err)
+		}
+
+		shared.mu.Lock()
+		shared.holder = ""
+		shared.mu.Unlock()
+		shared.turn.Unlock()
+	})
+
+	return shared.node
+}
+
+// Addresses funds addresses for t with SetupAddresses. In the node's wallet
+// each label is prefixed with the test's name, so tests on a shared node
+// that use the same labels, or a Mnemonic, get distinct addresses; the
+// returned book uses the labels as given.
+func Addresses(t testing.TB, node *dogetest.DogeTest, setups []dogetest.AddressSetup) *dogetest.AddressBook {
+	t.Helper()
+
+	prefixed := make([]dogetest.AddressSetup, len(setups))
+	for i, setup := range setups {
+		prefixed[i] = setup
+		prefixed[i].Label = t.Name() + "/" + setup.Label
+	}
+
+	book, err := node.SetupAddresses(prefixed)
+	if err != nil {
+		t.Fatalf("setup addresses: %v", err)
+	}
+
+	for i := range book.Addresses {
+		book.Addresses[i].Label = setups[i].Label
+	}
+
+	return book
+}
+
+// dockerAvailable checks that the Docker daemon answers, for nodes that
+// run in a container.
+func dockerAvailable(config dogetest.DogeTestConfig) (err error) {
+	if config.Binary != "" || config.Attach != nil {
+		return nil
+	}
+
+	// testcontainers panics when it finds no Docker host.
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	provider, err := testcontainers.NewDockerProvider()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return provider.Health(ctx)
+}
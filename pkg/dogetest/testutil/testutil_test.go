@@ -0,0 +1,191 @@
+package testutil
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dogecoinfoundation/dogetest/pkg/dogetest"
+	"github.com/dogecoinfoundation/dogetest/pkg/rpc"
+)
+
+func TestOptions(t *testing.T) {
+	config := newConfig([]Option{
+		WithConf("txindex=1"),
+		WithConfig(dogetest.DogeTestConfig{Version: "1.14.8", Args: []string{"-debug=net"}}),
+		WithBinary("/usr/local/bin/dogecoind"),
+		WithConf("maxmempool=5", "dustlimit=0.001"),
+		WithConf("acceptnonstdtxn=1"),
+		WithArgs("-debug=rpc"),
+	})
+
+	// WithConfig replaces what earlier options set.
+	want := dogetest.DogeTestConfig{
+		Version: "1.14.8",
+		Binary:  "/usr/local/bin/dogecoind",
+		Conf:    []string{"maxmempool=5", "dustlimit=0.001", "acceptnonstdtxn=1"},
+		Args:    []string{"-debug=net", "-debug=rpc"},
+	}
+	if !reflect.DeepEqual(config, want) {
+		t.Errorf("config = %+v, want %+v", config, want)
+	}
+
+	if config := newConfig(nil); !reflect.DeepEqual(config, dogetest.DogeTestConfig{}) {
+		t.Errorf("no options gave %+v, want the zero config", config)
+	}
+}
+
+func TestDockerNotNeeded(t *testing.T) {
+	for _, config := range []dogetest.DogeTestConfig{
+		{Binary: "/usr/local/bin/dogecoind"},
+		{Attach: &rpc.Config{RpcUrl: "http://127.0.0.1:1"}},
+	} {
+		if err := dockerAvailable(config); err != nil {
+			t.Errorf("dockerAvailable(%+v) = %v, want no check", config, err)
+		}
+	}
+}
+
+func TestNewSkipsWithoutDocker(t *testing.T) {
+	if dockerAvailable(dogetest.DogeTestConfig{}) == nil {
+		t.Skip("Docker is available")
+	}
+
+	var sub *testing.T
+	t.Run("new", func(t *testing.T) {
+		sub = t
+		New(t)
+		t.Error("New returned without Docker")
+	})
+	if !sub.Skipped() {
+		t.Error("New did not skip the test")
+	}
+}
+
+func TestSharedSkip(t *testing.T) {
+	saveShared(t)
+	shared.skip = "dogetest: Docker is not available: no Docker here"
+
+	var sub *testing.T
+	t.Run("shared", func(t *testing.T) {
+		sub = t
+		Shared(t)
+		t.Error("Shared returned without a node")
+	})
+	if !sub.Skipped() {
+		t.Error("Shared did not skip the test")
+	}
+}
+
+func TestSharedTurns(t *testing.T) {
+	saveShared(t)
+	node, setMockTime := newAttachedNode(t)
+	shared.node = node
+
+	var mu sync.Mutex
+	var holding, most int
+
+	// The tests run at once whatever -parallel is: Run may be called from
+	// several goroutines.
+	const tests = 4
+	var wg sync.WaitGroup
+	for range tests {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			t.Run("test", func(t *testing.T) {
+				got := Shared(t)
+
+				mu.Lock()
+				holding++
+				most = max(most, holding)
+				mu.Unlock()
+
+				// A subtest of the holder uses the node without waiting.
+				t.Run("sub", func(t *testing.T) {
+					if Shared(t) != got {
+						t.Error("subtest got another node")
+					}
+				})
+				time.Sleep(10 * time.Millisecond)
+
+				mu.Lock()
+				holding--
+				mu.Unlock()
+			})
+		}()
+	}
+	wg.Wait()
+
+	if most != 1 {
+		t.Errorf("%d tests held the node at once, want 1", most)
+	}
+	if n := setMockTime(); n != tests {
+		t.Errorf("mock time reset %d times, want once per test", n)
+	}
+}
+
+// saveShared restores the shared node's state when t is done.
+func saveShared(t *testing.T) {
+	node, skip, err := shared.node, shared.skip, shared.err
+	t.Cleanup(func() {
+		shared.node, shared.skip, shared.err = node, skip, err
+	})
+}
+
+// newAttachedNode starts a node attached to a regtest RPC server whose
+// setmocktime returns null, as Core's does, and returns how often
+// "setmocktime 0" was called.
+func newAttachedNode(t *testing.T) (*dogetest.DogeTest, func() int) {
+	t.Helper()
+
+	var mu sync.Mutex
+	resets := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+			Id     uint64            `json:"id"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+			return
+		}
+
+		var result any
+		switch req.Method {
+		case "getblockcount":
+			result = 0
+		case "getblockchaininfo":
+			result = map[string]any{"chain": "regtest"}
+		case "setmocktime":
+			if len(req.Params) == 1 && string(req.Params[0]) == "0" {
+				mu.Lock()
+				resets++
+				mu.Unlock()
+			}
+		default:
+			t.Errorf("unexpected %s", req.Method)
+		}
+		json.NewEncoder(w).Encode(map[string]any{"result": result, "error": nil, "id": req.Id})
+	}))
+	t.Cleanup(server.Close)
+
+	node, err := dogetest.NewDogeTest(dogetest.DogeTestConfig{Attach: &rpc.Config{RpcUrl: server.URL}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := node.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	return node, func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return resets
+	}
+}
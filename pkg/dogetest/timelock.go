@@ -0,0 +1,145 @@
+package dogetest
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dogecoinfoundation/dogetest/pkg/doge"
+)
+
+// maxMaturityBlocks bounds the blocks AdvanceTime mines before giving up.
+const maxMaturityBlocks = 100
+
+// IsNonFinal reports whether err is the node's rejection of a transaction
+// whose timelock has not matured: "non-final" for nLockTime,
+// "non-BIP68-final" for relative locks, or a failed CHECKLOCKTIMEVERIFY or
+// CHECKSEQUENCEVERIFY in one of its scripts.
+func IsNonFinal(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	msg := err.Error()
+	return strings.Contains(msg, "non-final") ||
+		strings.Contains(msg, "non-BIP68-final") ||
+		strings.Contains(msg, doge.ErrScriptUnsatisfiedLockTime.Error())
+}
+
+// SetMockTime fixes the node's clock at t, which becomes the time of new
+// blocks. The zero time returns the node to the system clock.
+func (d *DogeTest) SetMockTime(t time.Time) error {
+	if err := d.requireRegtest(); err != nil {
+		return err
+	}
+
+	if t.IsZero() {
+		return d.Rpc.SetMockTime(0)
+	}
+	return d.Rpc.SetMockTime(t.Unix())
+}
+
+// MedianTimePast returns the median time of the last 11 blocks, which
+// time-based locks are checked against.
+func (d *DogeTest) MedianTimePast() (time.Time, error) {
+	info, err := d.Rpc.GetBlockchainInfo()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Unix(info.MedianTime, 0), nil
+}
+
+// GenerateToHeight mines blocks until the best chain is height blocks long.
+func (d *DogeTest) GenerateToHeight(height int64) error {
+	count, err := d.Rpc.GetBlockCount()
+	if err != nil {
+		return err
+	}
+	if count >= height {
+		return nil
+	}
+
+	_, err = d.Generate(int(height - count))
+	return err
+}
+
+// AdvanceTime sets the node's mock time to until and mines blocks until the
+// median time past reaches it. The mock time stays set; see SetMockTime to
+// return to the system clock.
+func (d *DogeTest) AdvanceTime(until time.Time) error {
+	mtp, err := d.MedianTimePast()
+	if err != nil {
+		return err
+	}
+	if !mtp.Before(until) {
+		return nil
+	}
+
+	if err := d.SetMockTime(until); err != nil {
+		return err
+	}
+
+	for range maxMaturityBlocks {
+		if _, err := d.Generate(1); err != nil {
+			return err
+		}
+
+		mtp, err = d.MedianTimePast()
+		if err != nil {
+			return err
+		}
+		if !mtp.Before(until) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("median time past still %v after %d blocks, want %v", mtp, maxMaturityBlocks, until)
+}
+
+// MatureLockTime mines blocks, or advances mock time, until the nLockTime
+// of tx allows it into the next block. This also matures a
+// CHECKLOCKTIMEVERIFY input, whose lock can be at most the transaction's.
+// Relative locks need their outputs to age instead, e.g. with Generate.
+func (d *DogeTest) MatureLockTime(tx *doge.Tx) error {
+	if tx.LockTime < doge.LockTimeThreshold {
+		// The next block's height must exceed the lock.
+		return d.GenerateToHeight(int64(tx.LockTime))
+	}
+
+	// The median time past must exceed the lock.
+	return d.AdvanceTime(time.Unix(int64(tx.LockTime)+1, 0))
+}
+
+// CheckTimeLock asserts that the node rejects tx as non-final (see
+// IsNonFinal), calls mature, e.g. a closure around MatureLockTime or
+// Generate, and asserts the node then accepts tx. It returns the txid.
+func (d *DogeTest) CheckTimeLock(tx *doge.Tx, mature func() error) (string, error) {
+	txHex, err := tx.Hex()
+	if err != nil {
+		return "", err
+	}
+	txid, err := tx.TxID()
+	if err != nil {
+		return "", err
+	}
+
+	_, err = d.Rpc.SendRawTransaction(txHex)
+	if err == nil {
+		return "", fmt.Errorf("transaction %s accepted before its timelock matured", txid)
+	}
+	if !IsNonFinal(err) {
+		return "", fmt.Errorf("transaction %s rejected before maturity for another reason: %w", txid, err)
+	}
+
+	if err := mature(); err != nil {
+		return "", fmt.Errorf("mature: %w", err)
+	}
+
+	_, err = d.Rpc.SendRawTransaction(txHex)
+	if err != nil {
+		return "", fmt.Errorf("transaction %s rejected after maturity: %w", txid, err)
+	}
+
+	return txid, nil
+}
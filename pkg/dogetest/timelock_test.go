@@ -0,0 +1,131 @@
+package dogetest
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/dogecoinfoundation/dogetest/pkg/doge"
+)
+
+// fakeChain is a regtest chain whose blocks take the mock time, for the
+// fake node's setmocktime, generate and getblockchaininfo.
+type fakeChain struct {
+	mockTime int64
+	times    []int64
+}
+
+func (c *fakeChain) medianTimePast() int64 {
+	last := c.times[max(0, len(c.times)-11):]
+	sorted := slices.Clone(last)
+	slices.Sort(sorted)
+	return sorted[len(sorted)/2]
+}
+
+func (c *fakeChain) handle(method string, params []json.RawMessage) (any, string) {
+	switch method {
+	case "setmocktime":
+		// Core returns null, which must not count as a missing result.
+		json.Unmarshal(params[0], &c.mockTime)
+		return nil, ""
+	case "generate":
+		var n int
+		json.Unmarshal(params[0], &n)
+		hashes := []string{}
+		for range n {
+			c.times = append(c.times, c.mockTime)
+			hashes = append(hashes, "00")
+		}
+		return hashes, ""
+	case "getblockcount":
+		return len(c.times) - 1, ""
+	case "getblockchaininfo":
+		return map[string]any{"chain": "regtest", "mediantime": c.medianTimePast()}, ""
+	}
+	return nil, errUnknownMethod
+}
+
+func newFakeChain(t *testing.T, start time.Time) (*DogeTest, *fakeNode, *fakeChain) {
+	chain := &fakeChain{mockTime: start.Unix(), times: []int64{start.Unix()}}
+	d, node := newFakeNode(t, chain.handle)
+	return d, node, chain
+}
+
+func TestSetMockTime(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	d, _, chain := newFakeChain(t, start)
+
+	if err := d.SetMockTime(start.Add(time.Hour)); err != nil {
+		t.Fatalf("SetMockTime: %v", err)
+	}
+	if chain.mockTime != start.Add(time.Hour).Unix() {
+		t.Errorf("mock time = %d, want %d", chain.mockTime, start.Add(time.Hour).Unix())
+	}
+
+	if err := d.SetMockTime(time.Time{}); err != nil {
+		t.Fatalf("SetMockTime(zero): %v", err)
+	}
+	if chain.mockTime != 0 {
+		t.Errorf("mock time = %d, want 0", chain.mockTime)
+	}
+}
+
+func TestSetMockTimeNotRegtest(t *testing.T) {
+	d, node, _ := newFakeChain(t, time.Unix(1700000000, 0))
+	d.chain = "main"
+
+	err := d.SetMockTime(time.Unix(1800000000, 0))
+	if !errors.Is(err, ErrNotRegtest) {
+		t.Fatalf("err = %v, want ErrNotRegtest", err)
+	}
+	if node.count("setmocktime") != 0 {
+		t.Error("setmocktime was called on mainnet")
+	}
+}
+
+func TestAdvanceTime(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	d, _, _ := newFakeChain(t, start)
+
+	until := start.Add(2 * time.Hour)
+	if err := d.AdvanceTime(until); err != nil {
+		t.Fatalf("AdvanceTime: %v", err)
+	}
+
+	mtp, err := d.MedianTimePast()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mtp.Before(until) {
+		t.Errorf("median time past = %v, want at least %v", mtp, until)
+	}
+}
+
+func TestMatureLockTime(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+
+	t.Run("height", func(t *testing.T) {
+		d, _, chain := newFakeChain(t, start)
+
+		if err := d.MatureLockTime(&doge.Tx{LockTime: 20}); err != nil {
+			t.Fatalf("MatureLockTime: %v", err)
+		}
+		if height := len(chain.times) - 1; height != 20 {
+			t.Errorf("height = %d, want 20", height)
+		}
+	})
+
+	t.Run("time", func(t *testing.T) {
+		d, _, chain := newFakeChain(t, start)
+
+		lockTime := uint32(start.Add(time.Hour).Unix())
+		if err := d.MatureLockTime(&doge.Tx{LockTime: lockTime}); err != nil {
+			t.Fatalf("MatureLockTime: %v", err)
+		}
+		if mtp := chain.medianTimePast(); mtp <= int64(lockTime) {
+			t.Errorf("median time past = %d, want after %d", mtp, lockTime)
+		}
+	})
+}
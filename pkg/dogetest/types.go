@@ -3,6 +3,7 @@ package dogetest
 import (
 	"fmt"
 	"log"
+	"strings"
 
 	"github.com/dogecoinfoundation/dogetest/pkg/rpc"
 	"github.com/testcontainers/testcontainers-go"
@@ -57,3 +58,11 @@ func (lc *StdoutLogConsumer) Accept(l testcontainers.Log) {
 
 	log.Println(content)
 }
+
+// funcLogConsumer passes container logs to DogeTestConfig.Log.
+type funcLogConsumer func(line string)
+
+// Accept passes the log without its trailing newline
+func (lc funcLogConsumer) Accept(l testcontainers.Log) {
+	lc(strings.TrimRight(string(l.Content), "\r\n"))
+}
@@ -0,0 +1,80 @@
+package dogetest
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dogecoinfoundation/dogetest/pkg/doge"
+)
+
+// ScriptVerdict holds the offline verdict on a transaction's scripts next
+// to the node's answer to sendrawtransaction.
+type ScriptVerdict struct {
+	TxID string
+	// Traces holds the execution of each input under
+	// doge.StandardVerifyFlags, up to the first failing input.
+	Traces []*doge.Trace
+	// Expected is the reject reason the node should give, such as
+	// "mandatory-script-verify-flag-failed (Script failed an OP_EQUALVERIFY operation)",
+	// or empty when every input verifies.
+	Expected string
+	// NodeErr is the error returned by sendrawtransaction, nil if the
+	// transaction was accepted.
+	NodeErr error
+}
+
+// Agrees reports whether the node reached the same verdict as the Go
+// interpreter. A transaction whose scripts verify but which the node rejects
+// for other reasons (fee, dust, non-final) still agrees.
+func (v *ScriptVerdict) Agrees() bool {
+	if v.Expected == "" {
+		return v.NodeErr == nil || !strings.Contains(v.NodeErr.Error(), "script-verify-flag")
+	}
+	return v.NodeErr != nil && strings.Contains(v.NodeErr.Error(), v.Expected)
+}
+
+// VerifyAndBroadcast runs the scripts of tx through doge.VerifyScript with
+// the node's mempool flags, then submits tx with sendrawtransaction so the
+// two verdicts can be compared. The outputs being spent are fetched from
+// the node. The returned error is only set if that lookup fails.
+func (d *DogeTest) VerifyAndBroadcast(tx *doge.Tx) (*ScriptVerdict, error) {
+	txHex, err := tx.Hex()
+	if err != nil {
+		return nil, err
+	}
+	txid, err := tx.TxID()
+	if err != nil {
+		return nil, err
+	}
+	verdict := &ScriptVerdict{TxID: txid}
+
+	for i, in := range tx.TxIn {
+		prevTx, err := d.GetTx(in.PrevTxID)
+		if err != nil {
+			return nil, fmt.Errorf("previous transaction %s: %w", in.PrevTxID, err)
+		}
+		if int(in.PrevIndex) >= len(prevTx.TxOut) {
+			return nil, fmt.Errorf("previous transaction %s has no output %d", in.PrevTxID, in.PrevIndex)
+		}
+		prevScript := prevTx.TxOut[in.PrevIndex].ScriptPubKey
+
+		trace, err := doge.VerifyScript(in.ScriptSig, prevScript, tx, i, doge.StandardVerifyFlags)
+		verdict.Traces = append(verdict.Traces, trace)
+		if err == nil {
+			continue
+		}
+
+		// Like the node, check whether the input fails consensus rules or
+		// only policy, and report the error of the policy check either way.
+		if _, mandatoryErr := doge.VerifyScript(in.ScriptSig, prevScript, tx, i, doge.MandatoryVerifyFlags); mandatoryErr == nil {
+			verdict.Expected = fmt.Sprintf("non-mandatory-script-verify-flag (%v)", err)
+		} else {
+			verdict.Expected = fmt.Sprintf("mandatory-script-verify-flag-failed (%v)", err)
+		}
+		break
+	}
+
+	_, verdict.NodeErr = d.Rpc.SendRawTransaction(txHex)
+
+	return verdict, nil
+}
@@ -0,0 +1,96 @@
+package dogetest_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dogecoinfoundation/dogetest/pkg/doge"
+	"github.com/dogecoinfoundation/dogetest/pkg/dogetest"
+	"github.com/dogecoinfoundation/dogetest/pkg/dogetest/testutil"
+	"github.com/dogecoinfoundation/dogetest/pkg/txbuilder"
+)
+
+// TestVerifyAndBroadcast compares the Go interpreter's verdict with the
+// node's answer to sendrawtransaction for a policy failure, a consensus
+// failure and a valid spend of the same output.
+func TestVerifyAndBroadcast(t *testing.T) {
+	node := testutil.New(t)
+	book := testutil.Addresses(t, node, []dogetest.AddressSetup{{Label: "verify", InitialBalance: 10}})
+	from := book.Addresses[0]
+
+	key, err := doge.KeyPairFromWIF(from.PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key.Params = &doge.RegTestParams
+	other, err := doge.GenerateKeyPair(&doge.RegTestParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wallet, err := node.GetWallet(from.Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(wallet.Unspents) == 0 {
+		t.Fatalf("no unspent outputs at %s", from.Address)
+	}
+	utxo := wallet.Unspents[0]
+	prevScript := doge.PayToPubKeyHashScript(doge.Hash160(key.PubKey()))
+
+	// spend builds a transaction spending utxo whose scriptSig is the
+	// signature of signer and the public key of key, after extra pushes.
+	spend := func(signer *doge.KeyPair, extra ...[]byte) *doge.Tx {
+		t.Helper()
+
+		b := txbuilder.New()
+		b.AddInput(utxo, func(tx *doge.Tx, index int) ([]byte, error) {
+			sig, err := doge.SignInput(tx, index, prevScript, signer.PrivateKey, doge.SigHashAll)
+			if err != nil {
+				return nil, err
+			}
+			sb := doge.NewScriptBuilder()
+			for _, push := range extra {
+				sb.AddData(push)
+			}
+			return sb.AddData(sig).AddData(key.PubKey()).Script(), nil
+		})
+		if err := b.SetChange(from.Address, txbuilder.DefaultFeeRate); err != nil {
+			t.Fatal(err)
+		}
+		tx, err := b.Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tx
+	}
+
+	tests := []struct {
+		name     string
+		tx       *doge.Tx
+		expected string
+	}{
+		{"extra push", spend(key, []byte{0xaa}), "non-mandatory-script-verify-flag (Extra items left on stack after execution)"},
+		{"wrong key", spend(other), "mandatory-script-verify-flag-failed"},
+		{"valid", spend(key), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verdict, err := node.VerifyAndBroadcast(tt.tx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !verdict.Agrees() {
+				t.Errorf("verdicts differ: go %q, node %v", verdict.Expected, verdict.NodeErr)
+			}
+			if !strings.HasPrefix(verdict.Expected, tt.expected) || (tt.expected == "") != (verdict.Expected == "") {
+				t.Errorf("expected %q, want %q", verdict.Expected, tt.expected)
+			}
+			if tt.expected == "" && verdict.NodeErr != nil {
+				t.Errorf("node rejected a valid transaction: %v", verdict.NodeErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package dogetest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/dogecoinfoundation/dogetest/pkg/rpc"
+)
+
+// waitPollInterval is how often the wait helpers poll the node. Tests
+// shorten it.
+var waitPollInterval = 250 * time.Millisecond
+
+// waitMaxFailures is how many polls in a row may get no answer from the
+// node before a wait helper gives up, even if ctx has no deadline.
+const waitMaxFailures = 20
+
+// WaitForConfirmations blocks until the wallet transaction txid has at least
+// n confirmations, or ctx is done.
+func (d *DogeTest) WaitForConfirmations(ctx context.Context, txid string, n int64) error {
+	return waitFor(ctx, fmt.Sprintf("%d confirmations of %s", n, txid), func() (bool, string, error) {
+		tx, err := d.Rpc.GetTransaction(txid)
+		if err != nil {
+			return false, "", err
+		}
+
+		return tx.Confirmations >= n, fmt.Sprintf("%d confirmations", tx.Confirmations), nil
+	})
+}
+
+// WaitForHeight blocks until the best chain is at least height blocks long,
+// or ctx is done.
+func (d *DogeTest) WaitForHeight(ctx context.Context, height int64) error {
+	return waitFor(ctx, fmt.Sprintf("block height %d", height), func() (bool, string, error) {
+		count, err := d.Rpc.GetBlockCount()
+		if err != nil {
+			return false, "", err
+		}
+
+		return count >= height, fmt.Sprintf("height %d", count), nil
+	})
+}
+
+// WaitForBalance blocks until address holds at least amount, counting
+// unconfirmed outputs, or ctx is done.
+func (d *DogeTest) WaitForBalance(ctx context.Context, address string, amount float64) error {
+	return waitFor(ctx, fmt.Sprintf("balance of %v on %s", amount, address), func() (bool, string, error) {
+		wallet, err := d.GetWallet(address)
+		if err != nil {
+			return false, "", err
+		}
+
+		balance := wallet.GetBalance()
+		return balance >= amount, fmt.Sprintf("balance %v in %d unspents", balance, len(wallet.Unspents)), nil
+	})
+}
+
+// WaitForMempool blocks until txid is in the node's mempool, or ctx is done.
+func (d *DogeTest) WaitForMempool(ctx context.Context, txid string) error {
+	return waitFor(ctx, fmt.Sprintf("%s in mempool", txid), func() (bool, string, error) {
+		mempool, err := d.Rpc.GetRawMempool()
+		if err != nil {
+			return false, "", err
+		}
+
+		return slices.Contains(mempool, txid), fmt.Sprintf("%d transactions in mempool", len(mempool)), nil
+	})
+}
+
+// waitFor polls check until it reports done. Errors the node answers with
+// are treated as part of the observed state (the transaction may simply
+// not exist yet) and are reported if ctx ends before the condition is met.
+// Other errors mean the node did not answer; after waitMaxFailures of them
+// in a row waitFor returns the last one.
+func waitFor(ctx context.Context, what string, check func() (bool, string, error)) error {
+	lastState := "nothing observed"
+	failures := 0
+
+	for {
+		done, state, err := check()
+		if err == nil && done {
+			return nil
+		}
+
+		var rpcErr *rpc.Error
+		switch {
+		case err == nil:
+			lastState = state
+			failures = 0
+		case errors.As(err, &rpcErr):
+			lastState = err.Error()
+			failures = 0
+		default:
+			lastState = err.Error()
+			failures++
+			if failures >= waitMaxFailures {
+				return fmt.Errorf("waiting for %s: node did not answer %d times: %w", what, failures, err)
+			}
+		}
+
+		select {
+		case <-time.After(waitPollInterval):
+		case <-ctx.Done():
+			return fmt.Errorf("waiting for %s: %w (last observed: %s)", what, ctx.Err(), lastState)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package dogetest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dogecoinfoundation/dogetest/pkg/rpc"
+)
+
+// fastPolling shortens waitPollInterval for the test.
+func fastPolling(t *testing.T) {
+	saved := waitPollInterval
+	waitPollInterval = time.Millisecond
+	t.Cleanup(func() { waitPollInterval = saved })
+}
+
+func TestWaitHelpers(t *testing.T) {
+	fastPolling(t)
+
+	// Each method reports a different state on every call until the
+	// condition holds on the fourth.
+	calls := map[string]int{}
+	d, node := newFakeNode(t, func(method string, params []json.RawMessage) (any, string) {
+		calls[method]++
+		n := calls[method]
+		switch method {
+		case "gettransaction":
+			if n == 1 {
+				return nil, "Invalid or non-wallet transaction id"
+			}
+			return map[string]any{"txid": "much", "confirmations": n - 2}, ""
+		case "getblockcount":
+			return 100 + n, ""
+		case "listunspent":
+			var unspents []map[string]any
+			for range n - 1 {
+				unspents = append(unspents, map[string]any{"txid": "such", "amount": 2.5})
+			}
+			return unspents, ""
+		case "getrawmempool":
+			mempool := []string{"wow"}
+			if n >= 4 {
+				mempool = append(mempool, "much")
+			}
+			return mempool, ""
+		}
+		return nil, errUnknownMethod
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	tests := []struct {
+		method string
+		wait   func() error
+	}{
+		{"gettransaction", func() error { return d.WaitForConfirmations(ctx, "much", 2) }},
+		{"getblockcount", func() error { return d.WaitForHeight(ctx, 104) }},
+		{"listunspent", func() error { return d.WaitForBalance(ctx, "nWow", 7.5) }},
+		{"getrawmempool", func() error { return d.WaitForMempool(ctx, "much") }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			if err := tt.wait(); err != nil {
+				t.Fatal(err)
+			}
+			if n := node.count(tt.method); n != 4 {
+				t.Errorf("%s called %d times, want 4", tt.method, n)
+			}
+		})
+	}
+}
+
+func TestWaitTimeout(t *testing.T) {
+	fastPolling(t)
+
+	d, _ := newFakeNode(t, func(method string, params []json.RawMessage) (any, string) {
+		switch method {
+		case "getblockcount":
+			return 7, ""
+		case "gettransaction":
+			return nil, "Invalid or non-wallet transaction id"
+		}
+		return nil, errUnknownMethod
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := d.WaitForHeight(ctx, 10)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+	}
+	want := "waiting for block height 10: context deadline exceeded (last observed: height 7)"
+	if err.Error() != want {
+		t.Errorf("err = %q, want %q", err, want)
+	}
+
+	// Errors from Core are the state, not a reason to stop.
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err = d.WaitForConfirmations(ctx, "much", 1)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+	}
+	if !strings.Contains(err.Error(), "last observed: json-rpc: error from Core Node") {
+		t.Errorf("err = %q, want Core's error as the last state", err)
+	}
+}
+
+func TestWaitNodeGone(t *testing.T) {
+	fastPolling(t)
+
+	server := httptest.NewServer(nil)
+	server.Close()
+	d := &DogeTest{Rpc: rpc.NewRpcTransport(&rpc.Config{RpcUrl: server.URL}), chain: "regtest"}
+
+	// Without a deadline the wait ends because the node does not answer.
+	done := make(chan error, 1)
+	go func() { done <- d.WaitForHeight(context.Background(), 1) }()
+
+	select {
+	case err := <-done:
+		if err == nil || !strings.Contains(err.Error(), "node did not answer") {
+			t.Errorf("err = %v, want the node not answering", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("WaitForHeight kept polling a node that is gone")
+	}
+}
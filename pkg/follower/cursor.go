@@ -0,0 +1,107 @@
+package follower
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Cursor is the last block the follower delivered as connected.
+type Cursor struct {
+	Hash   string `json:"hash"`
+	Height int64  `json:"height"`
+}
+
+// CursorStore persists the follower's position. Load returns nil when no
+// cursor has been saved yet.
+type CursorStore interface {
+	Load() (*Cursor, error)
+	Save(cursor Cursor) error
+}
+
+type MemoryCursorStore struct {
+	mu     sync.Mutex
+	cursor *Cursor
+}
+
+func (s *MemoryCursorStore) Load() (*Cursor, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cursor == nil {
+		return nil, nil
+	}
+
+	cursor := *s.cursor
+	return &cursor, nil
+}
+
+func (s *MemoryCursorStore) Save(cursor Cursor) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cursor = &cursor
+	return nil
+}
+
+// FileCursorStore keeps the cursor as JSON in a file, replacing it
+// atomically on every save so a crash never leaves a torn cursor behind.
+type FileCursorStore struct {
+	Path string
+}
+
+func (s *FileCursorStore) Load() (*Cursor, error) {
+	data, err := os.ReadFile(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor Cursor
+	err = json.Unmarshal(data, &cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cursor, nil
+}
+
+func (s *FileCursorStore) Save(cursor Cursor) error {
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.Path), filepath.Base(s.Path)+".*")
+	if err != nil {
+		return err
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	// Flushed before the rename, so a crash leaves the old cursor or the
+	// new one, never an empty file.
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	if err := os.Rename(tmp.Name(), s.Path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,189 @@
+package follower
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dogecoinfoundation/dogetest/pkg/rpc"
+)
+
+type EventType int
+
+const (
+	// BlockConnected is emitted when a block is added to the best chain.
+	BlockConnected EventType = iota + 1
+	// BlockDisconnected is emitted when a previously connected block is
+	// removed from the best chain by a reorganisation. Disconnects are
+	// emitted tip first, before the blocks of the new branch are connected.
+	BlockDisconnected
+)
+
+func (e EventType) String() string {
+	switch e {
+	case BlockConnected:
+		return "BlockConnected"
+	case BlockDisconnected:
+		return "BlockDisconnected"
+	}
+
+	return fmt.Sprintf("EventType(%d)", int(e))
+}
+
+type Event struct {
+	Type   EventType
+	Height int64
+	Block  *rpc.Block
+}
+
+// Handler processes a single event. The cursor is only advanced (and
+// persisted) once the handler returns nil; returning an error stops Run and
+// the same event is delivered again on the next Run.
+type Handler func(event Event) error
+
+type Config struct {
+	// Store persists the cursor between runs. Defaults to an in-memory store.
+	Store CursorStore
+	// StartHeight is the first block connected when the store holds no cursor.
+	StartHeight int64
+	// Verbosity is passed to getblock for every emitted block, see
+	// rpc.BlockIteratorConfig. Defaults to 2.
+	Verbosity int
+	// PollInterval is how often the best block hash is polled once the
+	// follower has caught up. Defaults to one second.
+	PollInterval time.Duration
+}
+
+// Follower tracks the best chain of a node and turns changes of the tip
+// into an ordered stream of connect/disconnect events.
+type Follower struct {
+	transport *rpc.RpcTransport
+	config    Config
+}
+
+func NewFollower(transport *rpc.RpcTransport, config Config) *Follower {
+	if config.Store == nil {
+		config.Store = &MemoryCursorStore{}
+	}
+	if config.Verbosity == 0 {
+		config.Verbosity = 2
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = time.Second
+	}
+
+	return &Follower{
+		transport: transport,
+		config:    config,
+	}
+}
+
+// Run follows the chain, calling handler for every event, until ctx is
+// cancelled or handler or the node returns an error.
+func (f *Follower) Run(ctx context.Context, handler Handler) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		progressed, err := f.step(handler)
+		if err != nil {
+			return err
+		}
+
+		if progressed {
+			continue
+		}
+
+		select {
+		case <-time.After(f.config.PollInterval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// step emits at most one event and reports whether it did.
+func (f *Follower) step(handler Handler) (bool, error) {
+	cursor, err := f.config.Store.Load()
+	if err != nil {
+		return false, err
+	}
+
+	if cursor == nil {
+		return f.connectFirst(handler)
+	}
+
+	best, err := f.transport.GetBestBlockHash()
+	if err != nil {
+		return false, err
+	}
+
+	if best == cursor.Hash {
+		return false, nil
+	}
+
+	header, err := f.transport.GetBlockHeader(cursor.Hash)
+	if err != nil {
+		return false, err
+	}
+
+	if !header.IsOnChain() {
+		return true, f.emit(handler, BlockDisconnected, header.Hash, &Cursor{
+			Hash:   header.PreviousBlockHash,
+			Height: header.Height - 1,
+		})
+	}
+
+	if header.NextBlockHash == "" {
+		// The tip moved between the two calls; try again on the next poll.
+		return false, nil
+	}
+
+	return true, f.emit(handler, BlockConnected, header.NextBlockHash, &Cursor{
+		Hash:   header.NextBlockHash,
+		Height: header.Height + 1,
+	})
+}
+
+func (f *Follower) connectFirst(handler Handler) (bool, error) {
+	count, err := f.transport.GetBlockCount()
+	if err != nil {
+		return false, err
+	}
+
+	if count < f.config.StartHeight {
+		return false, nil
+	}
+
+	hash, err := f.transport.GetBlockHash(f.config.StartHeight)
+	if err != nil {
+		return false, err
+	}
+
+	return true, f.emit(handler, BlockConnected, hash, &Cursor{
+		Hash:   hash,
+		Height: f.config.StartHeight,
+	})
+}
+
+// emit fetches the block, hands it to handler and then moves the cursor to
+// next. For a disconnect the emitted block is the old cursor, for a
+// connect it is the new one.
+func (f *Follower) emit(handler Handler, eventType EventType, hash string, next *Cursor) error {
+	block, err := f.transport.GetBlockVerbosity(hash, f.config.Verbosity)
+	if err != nil {
+		return err
+	}
+
+	err = handler(Event{
+		Type:   eventType,
+		Height: block.Height,
+		Block:  block,
+	})
+	if err != nil {
+		return err
+	}
+
+	return f.config.Store.Save(*next)
+}
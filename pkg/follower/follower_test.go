@@ -0,0 +1,280 @@
+package follower
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dogecoinfoundation/dogetest/pkg/rpc"
+)
+
+// fakeChain serves the RPCs the follower uses for a chain of blocks whose
+// hashes are given per height; blocks left behind by setBest stay known
+// but off the best chain.
+type fakeChain struct {
+	mu     sync.Mutex
+	best   []string
+	height map[string]int64
+	prev   map[string]string
+}
+
+func newFakeChain(t *testing.T, best ...string) (*fakeChain, *rpc.RpcTransport) {
+	t.Helper()
+
+	c := &fakeChain{height: map[string]int64{}, prev: map[string]string{}}
+	c.setBest(best...)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+			Id     uint64            `json:"id"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+			return
+		}
+
+		result, err := c.handle(req.Method, req.Params)
+		res := map[string]any{"result": result, "error": nil, "id": req.Id}
+		if err != nil {
+			res["result"] = nil
+			res["error"] = map[string]any{"code": -5, "message": err.Error()}
+		}
+		json.NewEncoder(w).Encode(res)
+	}))
+	t.Cleanup(server.Close)
+
+	return c, rpc.NewRpcTransport(&rpc.Config{RpcUrl: server.URL})
+}
+
+// setBest makes best the best chain, block i at height i.
+func (c *fakeChain) setBest(best ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.best = best
+	for i, hash := range best {
+		c.height[hash] = int64(i)
+		if i > 0 {
+			c.prev[hash] = best[i-1]
+		}
+	}
+}
+
+func (c *fakeChain) handle(method string, params []json.RawMessage) (any, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var hash string
+	var height int64
+	if len(params) > 0 {
+		json.Unmarshal(params[0], &hash)
+		json.Unmarshal(params[0], &height)
+	}
+
+	switch method {
+	case "getbestblockhash":
+		return c.best[len(c.best)-1], nil
+	case "getblockcount":
+		return len(c.best) - 1, nil
+	case "getblockhash":
+		if height < 0 || height >= int64(len(c.best)) {
+			return nil, errors.New("Block height out of range")
+		}
+		return c.best[height], nil
+	case "getblockheader", "getblock":
+		h, ok := c.height[hash]
+		if !ok {
+			return nil, errors.New("Block not found")
+		}
+		block := map[string]any{"hash": hash, "height": h, "previousblockhash": c.prev[hash]}
+		if h < int64(len(c.best)) && c.best[h] == hash {
+			block["confirmations"] = int64(len(c.best)) - h
+			if h+1 < int64(len(c.best)) {
+				block["nextblockhash"] = c.best[h+1]
+			}
+		} else {
+			block["confirmations"] = -1
+		}
+		return block, nil
+	}
+
+	return nil, fmt.Errorf("Method not found: %s", method)
+}
+
+// event is an Event reduced to what the tests compare.
+type event struct {
+	Type   EventType
+	Height int64
+	Hash   string
+}
+
+// drain steps f until it has nothing to emit and returns the events.
+func drain(t *testing.T, f *Follower) []event {
+	t.Helper()
+
+	var events []event
+	for {
+		progressed, err := f.step(func(e Event) error {
+			events = append(events, event{e.Type, e.Height, e.Block.Hash})
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !progressed {
+			return events
+		}
+	}
+}
+
+func TestFollowerReorg(t *testing.T) {
+	chain, transport := newFakeChain(t, "a0", "a1", "a2", "a3", "a4", "a5")
+	store := &FileCursorStore{Path: filepath.Join(t.TempDir(), "cursor.json")}
+
+	// Catch up with Run, stopping once the tip is delivered.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	f := NewFollower(transport, Config{Store: store, StartHeight: 2, PollInterval: time.Millisecond})
+	var caughtUp []event
+	err := f.Run(ctx, func(e Event) error {
+		caughtUp = append(caughtUp, event{e.Type, e.Height, e.Block.Hash})
+		if e.Block.Hash == "a5" {
+			cancel()
+		}
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Run = %v, want context.Canceled", err)
+	}
+	want := []event{
+		{BlockConnected, 2, "a2"},
+		{BlockConnected, 3, "a3"},
+		{BlockConnected, 4, "a4"},
+		{BlockConnected, 5, "a5"},
+	}
+	if !reflect.DeepEqual(caughtUp, want) {
+		t.Errorf("catching up got %v, want %v", caughtUp, want)
+	}
+
+	// A longer branch from a2 replaces a3..a5.
+	chain.setBest("a0", "a1", "a2", "b3", "b4", "b5", "b6")
+	got := drain(t, f)
+	want = []event{
+		{BlockDisconnected, 5, "a5"},
+		{BlockDisconnected, 4, "a4"},
+		{BlockDisconnected, 3, "a3"},
+		{BlockConnected, 3, "b3"},
+		{BlockConnected, 4, "b4"},
+		{BlockConnected, 5, "b5"},
+		{BlockConnected, 6, "b6"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("reorg got %v, want %v", got, want)
+	}
+
+	// A new follower on the same file resumes after b6.
+	saved, err := (&FileCursorStore{Path: store.Path}).Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *saved != (Cursor{Hash: "b6", Height: 6}) {
+		t.Errorf("saved cursor = %+v, want b6 at 6", *saved)
+	}
+
+	chain.setBest("a0", "a1", "a2", "b3", "b4", "b5", "b6", "b7")
+	resumed := NewFollower(transport, Config{Store: &FileCursorStore{Path: store.Path}})
+	if got := drain(t, resumed); !reflect.DeepEqual(got, []event{{BlockConnected, 7, "b7"}}) {
+		t.Errorf("resumed follower got %v, want b7 only", got)
+	}
+}
+
+func TestFollowerHandlerError(t *testing.T) {
+	_, transport := newFakeChain(t, "a0", "a1")
+	store := &MemoryCursorStore{}
+	f := NewFollower(transport, Config{Store: store, StartHeight: 1})
+
+	failure := errors.New("much failure")
+	err := f.Run(context.Background(), func(e Event) error {
+		return failure
+	})
+	if !errors.Is(err, failure) {
+		t.Fatalf("Run = %v, want the handler's error", err)
+	}
+	if cursor, _ := store.Load(); cursor != nil {
+		t.Errorf("cursor moved to %+v after the handler failed", *cursor)
+	}
+
+	// The same block is delivered again.
+	if got := drain(t, f); !reflect.DeepEqual(got, []event{{BlockConnected, 1, "a1"}}) {
+		t.Errorf("got %v, want a1 again", got)
+	}
+}
+
+func TestFollowerWaitsForStartHeight(t *testing.T) {
+	chain, transport := newFakeChain(t, "a0", "a1")
+	f := NewFollower(transport, Config{StartHeight: 3})
+
+	if got := drain(t, f); len(got) != 0 {
+		t.Errorf("got %v below the start height", got)
+	}
+
+	chain.setBest("a0", "a1", "a2", "a3")
+	if got := drain(t, f); !reflect.DeepEqual(got, []event{{BlockConnected, 3, "a3"}}) {
+		t.Errorf("got %v, want a3 first", got)
+	}
+}
+
+func TestFileCursorStore(t *testing.T) {
+	dir := t.TempDir()
+	store := &FileCursorStore{Path: filepath.Join(dir, "cursor.json")}
+
+	cursor, err := store.Load()
+	if err != nil || cursor != nil {
+		t.Fatalf("Load of a missing file = %v, %v, want nil, nil", cursor, err)
+	}
+
+	for _, c := range []Cursor{{Hash: "a1", Height: 1}, {Hash: "b2", Height: 2}} {
+		if err := store.Save(c); err != nil {
+			t.Fatal(err)
+		}
+		got, err := store.Load()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if *got != c {
+			t.Errorf("Load = %+v, want %+v", *got, c)
+		}
+	}
+
+	// Only the cursor is left, no temporary files.
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	if !slices.Equal(names, []string{"cursor.json"}) {
+		t.Errorf("directory holds %v, want only cursor.json", names)
+	}
+
+	if err := os.WriteFile(store.Path, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Load(); err == nil {
+		t.Error("Load of a torn cursor succeeded")
+	}
+}
@@ -0,0 +1,108 @@
+package hdwallet
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	_ "embed"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// english is the BIP39 English wordlist, from
+// https://github.com/bitcoin/bips/blob/master/bip-0039/english.txt.
+//
+//go:embed english.txt
+var english string
+
+var (
+	wordlist = strings.Fields(english)
+
+	wordIndex = func() map[string]int {
+		index := make(map[string]int, len(wordlist))
+		for i, word := range wordlist {
+			index[word] = i
+		}
+		return index
+	}()
+)
+
+// NewMnemonic generates a random BIP39 mnemonic of 12 (128 bits) to 24
+// (256 bits) words.
+func NewMnemonic(bits int) (string, error) {
+	if bits < 128 || bits > 256 || bits%32 != 0 {
+		return "", fmt.Errorf("entropy of %d bits, want 128 to 256 in steps of 32", bits)
+	}
+
+	entropy := make([]byte, bits/8)
+	if _, err := rand.Read(entropy); err != nil {
+		return "", err
+	}
+
+	return mnemonicFromEntropy(entropy), nil
+}
+
+// mnemonicFromEntropy encodes entropy, followed by the first bits of its
+// SHA-256 as a checksum, as words of 11 bits each.
+func mnemonicFromEntropy(entropy []byte) string {
+	bits := len(entropy) * 8
+	checksumBits := bits / 32
+	sum := sha256.Sum256(entropy)
+
+	n := new(big.Int).SetBytes(entropy)
+	n.Lsh(n, uint(checksumBits))
+	n.Or(n, big.NewInt(int64(sum[0]>>(8-checksumBits))))
+
+	words := make([]string, (bits+checksumBits)/11)
+	mask := big.NewInt(2047)
+	for i := len(words) - 1; i >= 0; i-- {
+		words[i] = wordlist[new(big.Int).And(n, mask).Int64()]
+		n.Rsh(n, 11)
+	}
+
+	return strings.Join(words, " ")
+}
+
+// validMnemonic reports whether mnemonic has 12 to 24 words of the English
+// wordlist and a matching checksum.
+func validMnemonic(mnemonic string) bool {
+	words := strings.Fields(mnemonic)
+	if len(words) < 12 || len(words) > 24 || len(words)%3 != 0 {
+		return false
+	}
+
+	n := new(big.Int)
+	for _, word := range words {
+		index, ok := wordIndex[word]
+		if !ok {
+			return false
+		}
+		n.Lsh(n, 11)
+		n.Or(n, big.NewInt(int64(index)))
+	}
+
+	checksumBits := len(words) / 3
+	checksum := new(big.Int).And(n, big.NewInt(1<<checksumBits-1)).Int64()
+	n.Rsh(n, uint(checksumBits))
+
+	entropy := n.FillBytes(make([]byte, checksumBits*4))
+	sum := sha256.Sum256(entropy)
+
+	return int64(sum[0]>>(8-checksumBits)) == checksum
+}
+
+// SeedFromMnemonic validates mnemonic and returns its BIP39 seed, with an
+// optional passphrase. The words are joined by single spaces, so extra
+// whitespace or line breaks give the same seed; otherwise the mnemonic and
+// passphrase are used as given, without the NFKD normalization BIP39 asks
+// for, which only matters outside ASCII.
+func SeedFromMnemonic(mnemonic string, passphrase string) ([]byte, error) {
+	mnemonic = strings.Join(strings.Fields(mnemonic), " ")
+	if !validMnemonic(mnemonic) {
+		return nil, ErrInvalidMnemonic
+	}
+	return pbkdf2.Key([]byte(mnemonic), []byte("mnemonic"+passphrase), 2048, 64, sha512.New), nil
+}
@@ -0,0 +1,325 @@
+// Package hdwallet derives deterministic Dogecoin keys from a BIP39
+// mnemonic using BIP32 and the BIP44 path layout with coin type 3.
+package hdwallet
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/dogecoinfoundation/dogetest/pkg/doge"
+)
+
+// CoinType is the registered BIP44 coin type of Dogecoin.
+const CoinType = 3
+
+// HardenedKeyStart is the first hardened child index, written i' in paths.
+const HardenedKeyStart = 0x80000000
+
+var (
+	ErrInvalidMnemonic  = errors.New("invalid mnemonic")
+	ErrInvalidKey       = errors.New("derived key is invalid, use the next index")
+	ErrHardenedFromPub  = errors.New("cannot derive a hardened child from a public key")
+	ErrInvalidExtKeyLen = errors.New("extended key has the wrong length")
+)
+
+// ExtendedKey is a BIP32 private or public key with its chain code.
+type ExtendedKey struct {
+	key       []byte // 32-byte private key or 33-byte compressed public key
+	chainCode []byte
+	depth     uint8
+	parentFP  []byte
+	childNum  uint32
+	private   bool
+	params    *doge.ChainParams
+}
+
+// NewMaster derives the master key of a BIP32 seed.
+func NewMaster(seed []byte, params *doge.ChainParams) (*ExtendedKey, error) {
+	if len(seed) < 16 || len(seed) > 64 {
+		return nil, fmt.Errorf("seed length %d outside 16..64 bytes", len(seed))
+	}
+
+	mac := hmac.New(sha512.New, []byte("Bitcoin seed"))
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+
+	var k secp256k1.ModNScalar
+	if overflow := k.SetByteSlice(sum[:32]); overflow || k.IsZero() {
+		return nil, ErrInvalidKey
+	}
+
+	return &ExtendedKey{
+		key:       sum[:32],
+		chainCode: sum[32:],
+		parentFP:  []byte{0, 0, 0, 0},
+		private:   true,
+		params:    params,
+	}, nil
+}
+
+// NewMasterFromMnemonic is NewMaster of the seed of mnemonic, without a
+// BIP39 passphrase.
+func NewMasterFromMnemonic(mnemonic string, params *doge.ChainParams) (*ExtendedKey, error) {
+	seed, err := SeedFromMnemonic(mnemonic, "")
+	if err != nil {
+		return nil, err
+	}
+	return NewMaster(seed, params)
+}
+
+func (k *ExtendedKey) IsPrivate() bool {
+	return k.private
+}
+
+func (k *ExtendedKey) Depth() uint8 {
+	return k.depth
+}
+
+// PubKey returns the compressed public key.
+func (k *ExtendedKey) PubKey() []byte {
+	if !k.private {
+		return k.key
+	}
+	return secp256k1.PrivKeyFromBytes(k.key).PubKey().SerializeCompressed()
+}
+
+// KeyPair returns the key as a doge.KeyPair with compressed public key, for
+// WIF export, addresses and signing. It fails for public extended keys.
+func (k *ExtendedKey) KeyPair() (*doge.KeyPair, error) {
+	if !k.private {
+		return nil, errors.New("extended key is public")
+	}
+	return &doge.KeyPair{
+		PrivateKey: secp256k1.PrivKeyFromBytes(k.key),
+		Compressed: true,
+		Params:     k.params,
+	}, nil
+}
+
+// Address returns the P2PKH address of the key.
+func (k *ExtendedKey) Address() *doge.Address {
+	return doge.NewAddressPubKey(k.PubKey(), k.params)
+}
+
+// Neuter returns the public extended key, which can derive non-hardened
+// children only.
+func (k *ExtendedKey) Neuter() *ExtendedKey {
+	if !k.private {
+		return k
+	}
+	return &ExtendedKey{
+		key:       k.PubKey(),
+		chainCode: k.chainCode,
+		depth:     k.depth,
+		parentFP:  k.parentFP,
+		childNum:  k.childNum,
+		params:    k.params,
+	}
+}
+
+// Child derives child index i; indexes from HardenedKeyStart are hardened.
+func (k *ExtendedKey) Child(i uint32) (*ExtendedKey, error) {
+	hardened := i >= HardenedKeyStart
+	if hardened && !k.private {
+		return nil, ErrHardenedFromPub
+	}
+
+	data := make([]byte, 0, 37)
+	if hardened {
+		data = append(data, 0x00)
+		data = append(data, k.key...)
+	} else {
+		data = append(data, k.PubKey()...)
+	}
+	data = binary.BigEndian.AppendUint32(data, i)
+
+	mac := hmac.New(sha512.New, k.chainCode)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+
+	var tweak secp256k1.ModNScalar
+	if overflow := tweak.SetByteSlice(sum[:32]); overflow {
+		return nil, ErrInvalidKey
+	}
+
+	child := &ExtendedKey{
+		chainCode: sum[32:],
+		depth:     k.depth + 1,
+		parentFP:  doge.Hash160(k.PubKey())[:4],
+		childNum:  i,
+		private:   k.private,
+		params:    k.params,
+	}
+
+	if k.private {
+		var parent secp256k1.ModNScalar
+		parent.SetByteSlice(k.key)
+		tweak.Add(&parent)
+		if tweak.IsZero() {
+			return nil, ErrInvalidKey
+		}
+		key := tweak.Bytes()
+		child.key = key[:]
+		return child, nil
+	}
+
+	parent, err := secp256k1.ParsePubKey(k.key)
+	if err != nil {
+		return nil, err
+	}
+
+	var tweakPoint, parentPoint, result secp256k1.JacobianPoint
+	secp256k1.ScalarBaseMultNonConst(&tweak, &tweakPoint)
+	parent.AsJacobian(&parentPoint)
+	secp256k1.AddNonConst(&tweakPoint, &parentPoint, &result)
+	if (result.X.IsZero() && result.Y.IsZero()) || result.Z.IsZero() {
+		return nil, ErrInvalidKey
+	}
+	result.ToAffine()
+	child.key = secp256k1.NewPublicKey(&result.X, &result.Y).SerializeCompressed()
+
+	return child, nil
+}
+
+// Derive follows a path such as "m/44'/3'/0'/0/7" (or "44h/3h/0h/0/7")
+// from this key.
+func (k *ExtendedKey) Derive(path string) (*ExtendedKey, error) {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "m"), "/")
+	if path == "" {
+		return k, nil
+	}
+
+	key := k
+	for _, part := range strings.Split(path, "/") {
+		hardened := strings.HasSuffix(part, "'") || strings.HasSuffix(part, "h")
+		part = strings.TrimRight(part, "'h")
+
+		index, err := strconv.ParseUint(part, 10, 31)
+		if err != nil {
+			return nil, fmt.Errorf("path %q: bad index %q", path, part)
+		}
+		if hardened {
+			index += HardenedKeyStart
+		}
+
+		key, err = key.Child(uint32(index))
+		if err != nil {
+			return nil, fmt.Errorf("path %q: %w", path, err)
+		}
+	}
+
+	return key, nil
+}
+
+// String serializes the key as an extended private or public key with the
+// network's version bytes (tprv/tpub on testnet and regtest, dgpv/dgub on
+// mainnet).
+func (k *ExtendedKey) String() string {
+	version := k.params.HDPublicKeyID
+	if k.private {
+		version = k.params.HDPrivateKeyID
+	}
+
+	data := make([]byte, 0, 78)
+	data = append(data, version[:]...)
+	data = append(data, k.depth)
+	data = append(data, k.parentFP...)
+	data = binary.BigEndian.AppendUint32(data, k.childNum)
+	data = append(data, k.chainCode...)
+	if k.private {
+		data = append(data, 0x00)
+	}
+	data = append(data, k.key...)
+
+	return doge.Base58CheckEncode(data[0], data[1:])
+}
+
+// ParseExtendedKey decodes an extended key serialized by String or another
+// wallet, detecting the network from its version bytes. Testnet and regtest
+// share version bytes; such keys are reported as testnet.
+func ParseExtendedKey(s string) (*ExtendedKey, error) {
+	first, rest, err := doge.Base58CheckDecode(s)
+	if err != nil {
+		return nil, err
+	}
+	data := append([]byte{first}, rest...)
+	if len(data) != 78 {
+		return nil, ErrInvalidExtKeyLen
+	}
+
+	k := &ExtendedKey{
+		depth:     data[4],
+		parentFP:  data[5:9],
+		childNum:  binary.BigEndian.Uint32(data[9:13]),
+		chainCode: data[13:45],
+	}
+
+	for _, params := range doge.AllChainParams {
+		switch {
+		case bytes.Equal(data[:4], params.HDPrivateKeyID[:]):
+			k.private = true
+		case bytes.Equal(data[:4], params.HDPublicKeyID[:]):
+		default:
+			continue
+		}
+		k.params = params
+		break
+	}
+	if k.params == nil {
+		return nil, fmt.Errorf("unknown extended key version %x", data[:4])
+	}
+
+	if k.private {
+		if data[45] != 0x00 {
+			return nil, errors.New("malformed extended private key")
+		}
+		k.key = data[46:]
+	} else {
+		if _, err := secp256k1.ParsePubKey(data[45:]); err != nil {
+			return nil, err
+		}
+		k.key = data[45:]
+	}
+
+	return k, nil
+}
+
+// LabelPath returns the BIP44 receive path used for a label:
+// m/44'/3'/0'/0/i, with i taken from the SHA-256 of the label. A label thus
+// always maps to the same key, regardless of which other labels exist or the
+// order they are set up in.
+func LabelPath(label string) string {
+	return fmt.Sprintf("m/44'/%d'/0'/0/%d", CoinType, labelIndex(label))
+}
+
+func labelIndex(label string) uint32 {
+	sum := sha256.Sum256([]byte(label))
+	return binary.BigEndian.Uint32(sum[:4]) &^ HardenedKeyStart
+}
+
+// LabelKey derives the key of label under master, see LabelPath. In the
+// rare case that the derived index has no valid key, the following indexes
+// are tried.
+func LabelKey(master *ExtendedKey, label string) (*ExtendedKey, error) {
+	account, err := master.Derive(fmt.Sprintf("m/44'/%d'/0'/0", CoinType))
+	if err != nil {
+		return nil, err
+	}
+
+	index := labelIndex(label)
+	for {
+		key, err := account.Child(index)
+		if !errors.Is(err, ErrInvalidKey) {
+			return key, err
+		}
+		index = (index + 1) &^ HardenedKeyStart
+	}
+}
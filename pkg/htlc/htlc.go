@@ -0,0 +1,230 @@
+// Package htlc builds hashed timelock contracts, the building block of
+// atomic swaps: an output that the recipient can claim by revealing the
+// preimage of a hash, or the sender can take back once a lock time passes.
+package htlc
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/dogecoinfoundation/dogetest/pkg/doge"
+	"github.com/dogecoinfoundation/dogetest/pkg/rpc"
+	"github.com/dogecoinfoundation/dogetest/pkg/txbuilder"
+)
+
+// PreimageSize is the length the contract requires of the preimage, so that
+// a preimage accepted on one chain is accepted on the other.
+const PreimageSize = 32
+
+var (
+	ErrNotContract   = errors.New("script is not a hashed timelock contract")
+	ErrWrongPreimage = errors.New("preimage does not match the contract hash")
+)
+
+// Contract is a hashed timelock contract. Its redeem script is
+//
+//	OP_IF
+//	    OP_SIZE 32 OP_EQUALVERIFY OP_SHA256 <hash> OP_EQUALVERIFY <recipient>
+//	OP_ELSE
+//	    <lock time> OP_CHECKLOCKTIMEVERIFY OP_DROP <refund>
+//	OP_ENDIF
+//	OP_CHECKSIG
+type Contract struct {
+	Hash      []byte // SHA-256 of the preimage
+	Recipient []byte // public key that claims with the preimage
+	Refund    []byte // public key that refunds after LockTime
+	// LockTime is a block height below doge.LockTimeThreshold, a unix time
+	// from it.
+	LockTime uint32
+}
+
+// NewPreimage returns a random preimage and its SHA-256 hash.
+func NewPreimage() (preimage []byte, hash []byte, err error) {
+	preimage = make([]byte, PreimageSize)
+	if _, err := rand.Read(preimage); err != nil {
+		return nil, nil, err
+	}
+	sum := sha256.Sum256(preimage)
+	return preimage, sum[:], nil
+}
+
+// Script returns the redeem script of the contract.
+func (c *Contract) Script() []byte {
+	return doge.NewScriptBuilder().
+		AddOp(doge.OP_IF).
+		AddOp(doge.OP_SIZE).AddInt64(PreimageSize).AddOp(doge.OP_EQUALVERIFY).
+		AddOp(doge.OP_SHA256).AddData(c.Hash).AddOp(doge.OP_EQUALVERIFY).
+		AddData(c.Recipient).
+		AddOp(doge.OP_ELSE).
+		AddInt64(int64(c.LockTime)).AddOps(doge.OP_CHECKLOCKTIMEVERIFY, doge.OP_DROP).
+		AddData(c.Refund).
+		AddOp(doge.OP_ENDIF).
+		AddOp(doge.OP_CHECKSIG).
+		Script()
+}
+
+// Address returns the P2SH address funding the contract.
+func (c *Contract) Address(params *doge.ChainParams) *doge.Address {
+	return doge.NewAddressScript(c.Script(), params)
+}
+
+// ParseContract recognises a redeem script built by Contract.Script, e.g.
+// one received from the counterparty of a swap before funding the other
+// side.
+func ParseContract(script []byte) (*Contract, error) {
+	ops, err := doge.ParseScript(script)
+	if err != nil {
+		return nil, err
+	}
+
+	want := []byte{
+		doge.OP_IF, doge.OP_SIZE, 0, doge.OP_EQUALVERIFY, doge.OP_SHA256, 0, doge.OP_EQUALVERIFY, 0,
+		doge.OP_ELSE, 0, doge.OP_CHECKLOCKTIMEVERIFY, doge.OP_DROP, 0, doge.OP_ENDIF, doge.OP_CHECKSIG,
+	}
+	if len(ops) != len(want) {
+		return nil, ErrNotContract
+	}
+	for i, op := range want {
+		if op != 0 && ops[i].Op != op {
+			return nil, ErrNotContract
+		}
+	}
+
+	size, err := doge.ParseScriptNum(pushValue(ops[2]), true, 4)
+	if err != nil || size != PreimageSize {
+		return nil, ErrNotContract
+	}
+	lockTime, err := doge.ParseScriptNum(pushValue(ops[9]), true, 5)
+	if err != nil || lockTime < 0 || lockTime > 0xffffffff {
+		return nil, ErrNotContract
+	}
+
+	c := &Contract{
+		Hash:      ops[5].Data,
+		Recipient: ops[7].Data,
+		Refund:    ops[12].Data,
+		LockTime:  uint32(lockTime),
+	}
+	if len(c.Hash) != sha256.Size || !bytes.Equal(c.Script(), script) {
+		return nil, ErrNotContract
+	}
+
+	return c, nil
+}
+
+// ClaimTx returns a transaction spending the contract output utxo to
+// address with the preimage, signed by the recipient key, paying a fee of
+// feeRate koinu per kB.
+func ClaimTx(c *Contract, utxo rpc.UTXO, key *doge.KeyPair, preimage []byte, address string, feeRate int64) (*doge.Tx, error) {
+	sum := sha256.Sum256(preimage)
+	if len(preimage) != PreimageSize || !bytes.Equal(sum[:], c.Hash) {
+		return nil, ErrWrongPreimage
+	}
+	if !bytes.Equal(key.PubKey(), c.Recipient) {
+		return nil, errors.New("key is not the contract recipient")
+	}
+
+	b := txbuilder.New()
+	return spend(b, c, utxo, key, address, feeRate, func(sb *doge.ScriptBuilder) {
+		sb.AddData(preimage).AddOp(doge.OP_TRUE)
+	})
+}
+
+// RefundTx returns a transaction spending the contract output utxo back to
+// address, signed by the refund key. Its nLockTime is the contract's, so
+// the node rejects it as non-final until the lock time has passed.
+func RefundTx(c *Contract, utxo rpc.UTXO, key *doge.KeyPair, address string, feeRate int64) (*doge.Tx, error) {
+	if !bytes.Equal(key.PubKey(), c.Refund) {
+		return nil, errors.New("key is not the contract refund key")
+	}
+
+	b := txbuilder.New()
+	b.LockTime = c.LockTime
+	b.Sequence = doge.MaxTxInSequenceNum - 1
+	return spend(b, c, utxo, key, address, feeRate, func(sb *doge.ScriptBuilder) {
+		sb.AddOp(doge.OP_FALSE)
+	})
+}
+
+func spend(b *txbuilder.Builder, c *Contract, utxo rpc.UTXO, key *doge.KeyPair, address string, feeRate int64, branch func(*doge.ScriptBuilder)) (*doge.Tx, error) {
+	script := c.Script()
+
+	prevScript, err := hex.DecodeString(utxo.ScriptPubKey)
+	if err != nil {
+		return nil, fmt.Errorf("utxo %s:%d: %w", utxo.TxID, utxo.Vout, err)
+	}
+	if !bytes.Equal(prevScript, doge.PayToScriptHashScript(doge.Hash160(script))) {
+		return nil, fmt.Errorf("utxo %s:%d does not pay to the contract", utxo.TxID, utxo.Vout)
+	}
+
+	b.AddInput(utxo, func(tx *doge.Tx, index int) ([]byte, error) {
+		sig, err := doge.SignInput(tx, index, script, key.PrivateKey, b.HashType)
+		if err != nil {
+			return nil, err
+		}
+		sb := doge.NewScriptBuilder().AddData(sig)
+		branch(sb)
+		return sb.AddData(script).Script(), nil
+	})
+	if err := b.SetChange(address, feeRate); err != nil {
+		return nil, err
+	}
+
+	return b.Build()
+}
+
+// ExtractPreimage looks through a block fetched with verbosity 2 for a
+// transaction claiming the contract and returns the preimage it revealed,
+// with the claiming txid. ok is false if the block holds no claim.
+func ExtractPreimage(block *rpc.Block, c *Contract) (preimage []byte, txid string, ok bool) {
+	for _, tx := range block.Tx {
+		for _, in := range tx.VIn {
+			if preimage, ok := preimageFromScriptSig(in.ScriptSig.Hex, c); ok {
+				return preimage, tx.TxID, true
+			}
+		}
+	}
+	return nil, "", false
+}
+
+// ExtractPreimageFromTx is ExtractPreimage for a decoded transaction, e.g.
+// one seen in the mempool.
+func ExtractPreimageFromTx(tx *doge.Tx, c *Contract) ([]byte, bool) {
+	for _, in := range tx.TxIn {
+		if preimage, ok := preimageFromScriptSig(hex.EncodeToString(in.ScriptSig), c); ok {
+			return preimage, true
+		}
+	}
+	return nil, false
+}
+
+// preimageFromScriptSig matches <sig> <preimage> OP_TRUE <redeem script>.
+func preimageFromScriptSig(scriptSig string, c *Contract) ([]byte, bool) {
+	ops, err := doge.ParseScriptHex(scriptSig)
+	if err != nil || len(ops) != 4 || ops[2].Op != doge.OP_TRUE {
+		return nil, false
+	}
+	if !bytes.Equal(ops[3].Data, c.Script()) {
+		return nil, false
+	}
+
+	preimage := ops[1].Data
+	sum := sha256.Sum256(preimage)
+	if !bytes.Equal(sum[:], c.Hash) {
+		return nil, false
+	}
+	return preimage, true
+}
+
+// pushValue returns the value a push opcode leaves on the stack, including
+// the small integer opcodes.
+func pushValue(op doge.ScriptOp) []byte {
+	if op.Op >= doge.OP_1 && op.Op <= doge.OP_16 {
+		return []byte{op.Op - doge.OP_1 + 1}
+	}
+	return op.Data
+}
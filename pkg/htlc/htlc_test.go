@@ -0,0 +1,202 @@
+package htlc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/dogecoinfoundation/dogetest/pkg/doge"
+	"github.com/dogecoinfoundation/dogetest/pkg/rpc"
+	"github.com/dogecoinfoundation/dogetest/pkg/txbuilder"
+)
+
+const fundingTxID = "4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b"
+
+type swap struct {
+	contract  *Contract
+	preimage  []byte
+	recipient *doge.KeyPair
+	refund    *doge.KeyPair
+	utxo      rpc.UTXO
+	payTo     string
+}
+
+// newSwap returns a contract locked until height 500 and an output of
+// 10 DOGE funding it.
+func newSwap(t *testing.T) *swap {
+	t.Helper()
+
+	key := func() *doge.KeyPair {
+		k, err := doge.GenerateKeyPair(&doge.RegTestParams)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return k
+	}
+	preimage, hash, err := NewPreimage()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &swap{preimage: preimage, recipient: key(), refund: key()}
+	s.contract = &Contract{Hash: hash, Recipient: s.recipient.PubKey(), Refund: s.refund.PubKey(), LockTime: 500}
+	s.utxo = rpc.UTXO{
+		TxID:         fundingTxID,
+		Amount:       10,
+		ScriptPubKey: hex.EncodeToString(doge.PayToScriptHashScript(doge.Hash160(s.contract.Script()))),
+	}
+	s.payTo = key().Address().String()
+	return s
+}
+
+// verify runs the only input of tx against the contract output with the
+// node's mempool rules, which include CHECKLOCKTIMEVERIFY.
+func (s *swap) verify(tx *doge.Tx) error {
+	prevScript, _ := hex.DecodeString(s.utxo.ScriptPubKey)
+	_, err := doge.VerifyScript(tx.TxIn[0].ScriptSig, prevScript, tx, 0, doge.StandardVerifyFlags)
+	return err
+}
+
+func wantScriptError(t *testing.T, err error, want doge.ScriptError) {
+	t.Helper()
+	var got doge.ScriptError
+	if !errors.As(err, &got) || got != want {
+		t.Fatalf("err = %v, want %v", err, want)
+	}
+}
+
+func TestParseContract(t *testing.T) {
+	s := newSwap(t)
+
+	for _, lockTime := range []uint32{1, 16, 500, doge.LockTimeThreshold + 1, 0xffffffff} {
+		c := *s.contract
+		c.LockTime = lockTime
+
+		got, err := ParseContract(c.Script())
+		if err != nil {
+			t.Fatalf("lock time %d: %v", lockTime, err)
+		}
+		if !bytes.Equal(got.Hash, c.Hash) || !bytes.Equal(got.Recipient, c.Recipient) ||
+			!bytes.Equal(got.Refund, c.Refund) || got.LockTime != c.LockTime {
+			t.Errorf("lock time %d: parsed %+v, want %+v", lockTime, got, c)
+		}
+	}
+
+	short := *s.contract
+	short.Hash = short.Hash[:20]
+	notContracts := map[string][]byte{
+		"p2pkh":          doge.PayToPubKeyHashScript(doge.Hash160(s.recipient.PubKey())),
+		"short hash":     short.Script(),
+		"trailing op":    append(s.contract.Script(), doge.OP_NOP),
+		"preimage size":  bytes.Replace(s.contract.Script(), []byte{doge.OP_SIZE, 0x01, PreimageSize}, []byte{doge.OP_SIZE, 0x01, 16}, 1),
+		"truncated push": s.contract.Script()[:10],
+	}
+	for name, script := range notContracts {
+		if _, err := ParseContract(script); err == nil {
+			t.Errorf("%s: parsed a script that is not a contract", name)
+		}
+	}
+}
+
+func TestClaimTx(t *testing.T) {
+	s := newSwap(t)
+
+	tx, err := ClaimTx(s.contract, s.utxo, s.recipient, s.preimage, s.payTo, txbuilder.DefaultFeeRate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.verify(tx); err != nil {
+		t.Fatalf("claim does not verify: %v", err)
+	}
+
+	wrong := bytes.Repeat([]byte{0x42}, PreimageSize)
+	if _, err := ClaimTx(s.contract, s.utxo, s.recipient, wrong, s.payTo, txbuilder.DefaultFeeRate); !errors.Is(err, ErrWrongPreimage) {
+		t.Errorf("ClaimTx with a wrong preimage = %v, want ErrWrongPreimage", err)
+	}
+	if _, err := ClaimTx(s.contract, s.utxo, s.refund, s.preimage, s.payTo, txbuilder.DefaultFeeRate); err == nil {
+		t.Error("ClaimTx signed with the refund key")
+	}
+
+	// The script itself rejects a wrong preimage; the signature does not
+	// cover the scriptSig, so only the hash check fails.
+	forged := bytes.Replace(tx.TxIn[0].ScriptSig, s.preimage, wrong, 1)
+	tx.TxIn[0].ScriptSig = forged
+	wantScriptError(t, s.verify(tx), doge.ErrScriptEqualVerify)
+}
+
+func TestRefundTx(t *testing.T) {
+	s := newSwap(t)
+
+	tx, err := RefundTx(s.contract, s.utxo, s.refund, s.payTo, txbuilder.DefaultFeeRate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.LockTime != s.contract.LockTime {
+		t.Errorf("LockTime = %d, want the contract's %d", tx.LockTime, s.contract.LockTime)
+	}
+	if err := s.verify(tx); err != nil {
+		t.Fatalf("refund does not verify: %v", err)
+	}
+
+	if _, err := RefundTx(s.contract, s.utxo, s.recipient, s.payTo, txbuilder.DefaultFeeRate); err == nil {
+		t.Error("RefundTx signed with the recipient key")
+	}
+
+	// A refund before the lock time fails CHECKLOCKTIMEVERIFY, which runs
+	// before the signature check.
+	tx.LockTime = s.contract.LockTime - 1
+	wantScriptError(t, s.verify(tx), doge.ErrScriptUnsatisfiedLockTime)
+
+	// So does one that opts out of nLockTime with a final sequence.
+	tx.LockTime = s.contract.LockTime
+	tx.TxIn[0].Sequence = doge.MaxTxInSequenceNum
+	wantScriptError(t, s.verify(tx), doge.ErrScriptUnsatisfiedLockTime)
+}
+
+func TestExtractPreimage(t *testing.T) {
+	s := newSwap(t)
+	other := newSwap(t)
+
+	claim := func(s *swap) rpc.RawTxn {
+		t.Helper()
+		tx, err := ClaimTx(s.contract, s.utxo, s.recipient, s.preimage, s.payTo, txbuilder.DefaultFeeRate)
+		if err != nil {
+			t.Fatal(err)
+		}
+		txid, err := tx.TxID()
+		if err != nil {
+			t.Fatal(err)
+		}
+		raw := rpc.RawTxn{TxID: txid}
+		for _, in := range tx.TxIn {
+			raw.VIn = append(raw.VIn, rpc.RawTxnVIn{ScriptSig: rpc.RawTxnScriptSig{Hex: hex.EncodeToString(in.ScriptSig)}})
+		}
+		return raw
+	}
+
+	coinbase := rpc.RawTxn{TxID: "coinbase", VIn: []rpc.RawTxnVIn{{ScriptSig: rpc.RawTxnScriptSig{Hex: "0101"}}}}
+	ours := claim(s)
+	block := &rpc.Block{Tx: []rpc.RawTxn{coinbase, claim(other), ours}}
+
+	preimage, txid, ok := ExtractPreimage(block, s.contract)
+	if !ok {
+		t.Fatal("no preimage found")
+	}
+	if !bytes.Equal(preimage, s.preimage) || txid != ours.TxID {
+		t.Errorf("got preimage %x in %s, want %x in %s", preimage, txid, s.preimage, ours.TxID)
+	}
+
+	block.Tx = block.Tx[:2]
+	if _, _, ok := ExtractPreimage(block, s.contract); ok {
+		t.Error("found a preimage in a block without a claim of the contract")
+	}
+
+	tx, err := ClaimTx(s.contract, s.utxo, s.recipient, s.preimage, s.payTo, txbuilder.DefaultFeeRate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if preimage, ok := ExtractPreimageFromTx(tx, s.contract); !ok || !bytes.Equal(preimage, s.preimage) {
+		t.Errorf("ExtractPreimageFromTx = %x, %v, want the preimage", preimage, ok)
+	}
+}
@@ -0,0 +1,232 @@
+package rpc
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type BlockIteratorConfig struct {
+	// Verbosity selects how much of each block is decoded: 1 fills only the
+	// txids in Block.Tx, 2 decodes every transaction. Defaults to 2.
+	Verbosity int
+	// Concurrency is the number of blocks fetched in parallel. Defaults to 1.
+	Concurrency int
+	// Prefetch is how many blocks may be fetched ahead of the consumer.
+	// Defaults to Concurrency.
+	Prefetch int
+	// Follow keeps the iterator waiting for new blocks once it reaches the
+	// tip, instead of stopping there.
+	Follow bool
+	// PollInterval is how often the tip is polled while following.
+	// Defaults to one second.
+	PollInterval time.Duration
+}
+
+// BlockIterator streams blocks in height order. Use it like bufio.Scanner:
+//
+//	it := rpc.NewBlockIterator(ctx, transport, 0, -1, rpc.BlockIteratorConfig{})
+//	defer it.Close()
+//	for it.Next() {
+//		block := it.Block()
+//	}
+//	if err := it.Err(); err != nil { ... }
+type BlockIterator struct {
+	transport *RpcTransport
+	config    BlockIteratorConfig
+	from      int64
+	to        int64
+
+	ctx     context.Context
+	cancel  context.CancelFunc
+	pending chan chan blockResult
+	wg      sync.WaitGroup
+
+	block *Block
+	err   error
+}
+
+type blockResult struct {
+	block *Block
+	err   error
+}
+
+// NewBlockIterator returns an iterator over the blocks at heights from..to
+// inclusive. A negative to means "up to the tip"; with Follow set the
+// iterator then never ends on its own and stops only when ctx is cancelled
+// or Close is called. Without Follow, a to beyond the current tip is
+// clamped to the tip.
+func NewBlockIterator(ctx context.Context, t *RpcTransport, from int64, to int64, config BlockIteratorConfig) *BlockIterator {
+	if config.Verbosity == 0 {
+		config.Verbosity = 2
+	}
+	if config.Concurrency <= 0 {
+		config.Concurrency = 1
+	}
+	if config.Prefetch < config.Concurrency {
+		config.Prefetch = config.Concurrency
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = time.Second
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	it := &BlockIterator{
+		transport: t,
+		config:    config,
+		from:      from,
+		to:        to,
+		ctx:       ctx,
+		cancel:    cancel,
+		pending:   make(chan chan blockResult, config.Prefetch),
+	}
+
+	it.wg.Add(1)
+	go it.produce()
+
+	return it
+}
+
+// Next advances to the next block. It returns false at the end of the range,
+// on error or when the context is cancelled; check Err afterwards.
+func (it *BlockIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	// Blocks fetched ahead are not delivered once ctx is done.
+	if err := it.ctx.Err(); err != nil {
+		it.fail(err)
+		return false
+	}
+
+	select {
+	case future, ok := <-it.pending:
+		if !ok {
+			it.block = nil
+			return false
+		}
+
+		select {
+		case res := <-future:
+			if res.err != nil {
+				it.fail(res.err)
+				return false
+			}
+			it.block = res.block
+			return true
+		case <-it.ctx.Done():
+			it.fail(it.ctx.Err())
+			return false
+		}
+	case <-it.ctx.Done():
+		it.fail(it.ctx.Err())
+		return false
+	}
+}
+
+// Block returns the block produced by the last successful call to Next.
+func (it *BlockIterator) Block() *Block {
+	return it.block
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *BlockIterator) Err() error {
+	return it.err
+}
+
+// Close stops the iterator and waits for in-flight requests to finish.
+func (it *BlockIterator) Close() {
+	it.cancel()
+	it.wg.Wait()
+}
+
+func (it *BlockIterator) fail(err error) {
+	it.err = err
+	it.block = nil
+	it.cancel()
+}
+
+// produce schedules fetches in height order. Each height gets its own
+// single-use result channel, queued on pending in order, so fetches may
+// complete out of order while the consumer still sees ascending heights.
+func (it *BlockIterator) produce() {
+	defer it.wg.Done()
+	defer close(it.pending)
+
+	sem := make(chan struct{}, it.config.Concurrency)
+	tip := int64(-1)
+
+	for height := it.from; it.to < 0 || height <= it.to; height++ {
+		for height > tip {
+			count, err := it.transport.GetBlockCount()
+			if err != nil {
+				it.push(blockResult{err: err})
+				return
+			}
+			tip = count
+
+			if height <= tip {
+				break
+			}
+			if !it.config.Follow {
+				return
+			}
+
+			select {
+			case <-time.After(it.config.PollInterval):
+			case <-it.ctx.Done():
+				return
+			}
+		}
+
+		future := make(chan blockResult, 1)
+		select {
+		case it.pending <- future:
+		case <-it.ctx.Done():
+			return
+		}
+
+		select {
+		case sem <- struct{}{}:
+		case <-it.ctx.Done():
+			return
+		}
+
+		it.wg.Add(1)
+		go func(height int64) {
+			defer it.wg.Done()
+			defer func() { <-sem }()
+
+			future <- it.fetch(height)
+		}(height)
+	}
+}
+
+func (it *BlockIterator) push(res blockResult) {
+	future := make(chan blockResult, 1)
+	future <- res
+
+	select {
+	case it.pending <- future:
+	case <-it.ctx.Done():
+	}
+}
+
+func (it *BlockIterator) fetch(height int64) blockResult {
+	if err := it.ctx.Err(); err != nil {
+		return blockResult{err: err}
+	}
+
+	hash, err := it.transport.GetBlockHash(height)
+	if err != nil {
+		return blockResult{err: err}
+	}
+
+	block, err := it.transport.GetBlockVerbosity(hash, it.config.Verbosity)
+	if err != nil {
+		return blockResult{err: err}
+	}
+
+	return blockResult{block: block}
+}
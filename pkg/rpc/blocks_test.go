@@ -0,0 +1,223 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// chainNode serves getblockcount, getblockhash and getblock for a chain of
+// blocks named block<height>, up to tip.
+type chainNode struct {
+	tip atomic.Int64
+	// delay is how long getblock takes for a height.
+	delay func(height int64) time.Duration
+	// fetched counts getblock requests.
+	fetched atomic.Int64
+}
+
+func newChainNode(t *testing.T, tip int64, delay func(height int64) time.Duration) (*chainNode, *RpcTransport) {
+	t.Helper()
+
+	node := &chainNode{delay: delay}
+	node.tip.Store(tip)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+			Id     uint64            `json:"id"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+			return
+		}
+
+		var result any
+		switch req.Method {
+		case "getblockcount":
+			result = node.tip.Load()
+		case "getblockhash":
+			var height int64
+			json.Unmarshal(req.Params[0], &height)
+			result = fmt.Sprintf("block%d", height)
+		case "getblock":
+			var hash string
+			json.Unmarshal(req.Params[0], &hash)
+			height, _ := strconv.ParseInt(strings.TrimPrefix(hash, "block"), 10, 64)
+			node.fetched.Add(1)
+			if node.delay != nil {
+				time.Sleep(node.delay(height))
+			}
+			result = map[string]any{"hash": hash, "height": height}
+		default:
+			t.Errorf("unexpected %s", req.Method)
+		}
+		json.NewEncoder(w).Encode(map[string]any{"result": result, "error": nil, "id": req.Id})
+	}))
+	t.Cleanup(server.Close)
+
+	return node, NewRpcTransport(&Config{RpcUrl: server.URL})
+}
+
+// heights collects the heights it yields until Next returns false.
+func heights(t *testing.T, it *BlockIterator) []int64 {
+	t.Helper()
+
+	var got []int64
+	for it.Next() {
+		got = append(got, it.Block().Height)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return got
+}
+
+func span(from int64, to int64) []int64 {
+	var s []int64
+	for h := from; h <= to; h++ {
+		s = append(s, h)
+	}
+	return s
+}
+
+func TestBlockIteratorOrder(t *testing.T) {
+	// Lower blocks take longer, so fetches finish out of order.
+	_, transport := newChainNode(t, 19, func(height int64) time.Duration {
+		return time.Duration(20-height) * time.Millisecond
+	})
+
+	it := NewBlockIterator(context.Background(), transport, 0, -1, BlockIteratorConfig{Concurrency: 4, Prefetch: 8})
+	defer it.Close()
+
+	if got := heights(t, it); !reflect.DeepEqual(got, span(0, 19)) {
+		t.Errorf("heights = %v, want 0..19 in order", got)
+	}
+}
+
+func TestBlockIteratorRange(t *testing.T) {
+	_, transport := newChainNode(t, 5, nil)
+
+	tests := []struct {
+		name     string
+		from, to int64
+		want     []int64
+	}{
+		{"to the tip", 0, -1, span(0, 5)},
+		{"beyond the tip", 2, 100, span(2, 5)},
+		{"inside the chain", 1, 3, span(1, 3)},
+		{"from above the tip", 7, -1, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			it := NewBlockIterator(context.Background(), transport, tt.from, tt.to, BlockIteratorConfig{Concurrency: 2})
+			defer it.Close()
+
+			if got := heights(t, it); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("heights = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBlockIteratorCancel(t *testing.T) {
+	node, transport := newChainNode(t, 1000, func(int64) time.Duration {
+		return 5 * time.Millisecond
+	})
+	before := runtime.NumGoroutine()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	it := NewBlockIterator(ctx, transport, 0, -1, BlockIteratorConfig{Concurrency: 2, Prefetch: 4})
+
+	for range 3 {
+		if !it.Next() {
+			t.Fatalf("Next stopped early: %v", it.Err())
+		}
+	}
+	cancel()
+
+	if it.Next() {
+		t.Fatal("Next returned a block after cancel")
+	}
+	if !errors.Is(it.Err(), context.Canceled) {
+		t.Errorf("Err = %v, want context.Canceled", it.Err())
+	}
+	if it.Block() != nil {
+		t.Error("Block is not nil after cancel")
+	}
+
+	done := make(chan struct{})
+	go func() {
+		it.Close()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close did not return after cancel")
+	}
+
+	// Only the blocks fetched ahead of the consumer were requested.
+	if n := node.fetched.Load(); n > 3+4+2 {
+		t.Errorf("fetched %d blocks, want at most the prefetch after 3", n)
+	}
+
+	// The iterator's goroutines are gone once idle HTTP connections,
+	// which the transport shares through http.DefaultClient, are closed.
+	http.DefaultClient.CloseIdleConnections()
+	deadline := time.Now().Add(2 * time.Second)
+	for runtime.NumGoroutine() > before {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d goroutines after Close, %d before", runtime.NumGoroutine(), before)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestBlockIteratorFollow(t *testing.T) {
+	node, transport := newChainNode(t, 2, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	it := NewBlockIterator(ctx, transport, 0, -1, BlockIteratorConfig{Follow: true, PollInterval: 5 * time.Millisecond})
+	defer it.Close()
+
+	var got []int64
+	for len(got) < 5 && it.Next() {
+		got = append(got, it.Block().Height)
+		if it.Block().Height == 2 {
+			// Blocks mined once the iterator is at the tip.
+			go func() {
+				time.Sleep(20 * time.Millisecond)
+				node.tip.Store(4)
+			}()
+		}
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, span(0, 4)) {
+		t.Errorf("heights = %v, want 0..4", got)
+	}
+
+	// With nothing more mined, Next waits until ctx is done.
+	cancel()
+	if it.Next() {
+		t.Fatalf("Next returned block %d past the tip", it.Block().Height)
+	}
+	if !errors.Is(it.Err(), context.Canceled) {
+		t.Errorf("Err = %v, want context.Canceled", it.Err())
+	}
+}
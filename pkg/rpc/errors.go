@@ -0,0 +1,67 @@
+package rpc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Error codes returned by Dogecoin Core (see rpc/protocol.h).
+const (
+	ErrCodeMiscError            = -1
+	ErrCodeTypeError            = -3
+	ErrCodeInvalidAddressOrKey  = -5
+	ErrCodeInvalidParameter     = -8
+	ErrCodeDeserializationError = -22
+	ErrCodeVerifyError          = -25
+	ErrCodeVerifyRejected       = -26
+	ErrCodeVerifyAlreadyInChain = -27
+	ErrCodeInWarmup             = -28
+	ErrCodeMethodNotFound       = -32601
+
+	ErrCodeWalletError               = -4
+	ErrCodeWalletInsufficientFunds   = -6
+	ErrCodeWalletKeypoolRanOut       = -12
+	ErrCodeWalletUnlockNeeded        = -13
+	ErrCodeWalletPassphraseIncorrect = -14
+	ErrCodeWalletWrongEncState       = -15
+	ErrCodeWalletEncryptionFailed    = -16
+	ErrCodeWalletAlreadyUnlocked     = -17
+)
+
+// Error is an error object returned by the node in response to a call.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	enc, _ := json.Marshal(e)
+	return fmt.Sprintf("json-rpc: error from Core Node: %v", string(enc))
+}
+
+// IsErrorCode reports whether err is an *Error from the node with the given code.
+func IsErrorCode(err error, code int) bool {
+	var rpcErr *Error
+	if errors.As(err, &rpcErr) {
+		return rpcErr.Code == code
+	}
+
+	return false
+}
+
+// coreError converts the "error" member of a response into an error,
+// preferring the typed *Error when the node sent the usual {code, message}.
+func coreError(raw any) error {
+	enc, err := json.Marshal(raw)
+	if err != nil {
+		return fmt.Errorf("json-rpc: error from Core Node: %v", raw)
+	}
+
+	var rpcErr Error
+	if json.Unmarshal(enc, &rpcErr) == nil && rpcErr.Message != "" {
+		return &rpcErr
+	}
+
+	return fmt.Errorf("json-rpc: error from Core Node: %v", string(enc))
+}
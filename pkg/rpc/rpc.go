@@ -1,5 +1,7 @@
 package rpc
 
+//go:generate go run ../../cmd/rpcgen -in testdata/help.txt -out rpc_gen.go
+
 import (
 	"bytes"
 	"encoding/json"
@@ -7,6 +9,7 @@ import (
 	"io"
 	"net/http"
 	"net/rpc"
+	"reflect"
 	"sync/atomic"
 )
 
@@ -17,9 +20,11 @@ type rpcRequest struct {
 }
 
 type rpcResponse struct {
-	Id     uint64           `json:"id"`
-	Result *json.RawMessage `json:"result"`
-	Error  any              `json:"error"`
+	Id uint64 `json:"id"`
+	// Result is empty when the response has no result member, and the
+	// JSON null for RPCs that return nothing, like addnode or setban.
+	Result json.RawMessage `json:"result"`
+	Error  any             `json:"error"`
 }
 
 type RpcTransport struct {
@@ -47,63 +52,19 @@ type Info struct {
 }
 
 func (t *RpcTransport) GetInfo() (*Info, error) {
-	res, err := t.Request("getinfo", []any{})
-	if err != nil {
-		return nil, err
-	}
-
-	var result *Info
-	err = json.Unmarshal(*res, &result)
-	if err != nil {
-		return nil, fmt.Errorf("json-rpc unmarshal error: %v | %v", err, string(*res))
-	}
-
-	return result, nil
+	return Call[*Info](t, "getinfo")
 }
 
 func (t *RpcTransport) Generate(i int) ([]string, error) {
-	res, err := t.Request("generate", []any{i})
-	if err != nil {
-		return nil, err
-	}
-
-	var result []string
-	err = json.Unmarshal(*res, &result)
-	if err != nil {
-		return nil, fmt.Errorf("json-rpc unmarshal error: %v | %v", err, string(*res))
-	}
-
-	return result, nil
+	return Call[[]string](t, "generate", i)
 }
 
 func (t *RpcTransport) ListUnspent(address string) ([]UTXO, error) {
-	res, err := t.Request("listunspent", []any{0, 999999999, []string{address}})
-	if err != nil {
-		return nil, err
-	}
-
-	var result []UTXO
-	err = json.Unmarshal(*res, &result)
-	if err != nil {
-		return nil, fmt.Errorf("json-rpc unmarshal error: %v | %v", err, string(*res))
-	}
-
-	return result, nil
+	return Call[[]UTXO](t, "listunspent", 0, 999999999, []string{address})
 }
 
 func (t *RpcTransport) DumpPrivKey(address string) (string, error) {
-	res, err := t.Request("dumpprivkey", []any{address})
-	if err != nil {
-		return "", err
-	}
-
-	var result string
-	err = json.Unmarshal(*res, &result)
-	if err != nil {
-		return "", fmt.Errorf("json-rpc unmarshal error: %v | %v", err, string(*res))
-	}
-
-	return result, nil
+	return Call[string](t, "dumpprivkey", address)
 }
 
 func NewRpcTransport(config *Config) *RpcTransport {
@@ -111,18 +72,7 @@ func NewRpcTransport(config *Config) *RpcTransport {
 }
 
 func (t *RpcTransport) GetNewAddress() (string, error) {
-	res, err := t.Request("getnewaddress", []any{})
-	if err != nil {
-		return "", err
-	}
-
-	var result string
-	err = json.Unmarshal(*res, &result)
-	if err != nil {
-		return "", fmt.Errorf("json-rpc unmarshal error: %v | %v", err, string(*res))
-	}
-
-	return result, nil
+	return Call[string](t, "getnewaddress")
 }
 
 func (t *RpcTransport) SendToAddress(address string, amount float64) error {
@@ -143,92 +93,147 @@ func (t *RpcTransport) GenerateToAddress(address string, amount int) error {
 }
 
 func (t *RpcTransport) GetBlock(hash string) (*Block, error) {
-	res, err := t.Request("getblock", []any{hash, 2})
-	if err != nil {
-		return nil, err
-	}
+	return Call[*Block](t, "getblock", hash, 2)
+}
 
-	var result *Block
-	err = json.Unmarshal(*res, &result)
-	if err != nil {
-		return nil, fmt.Errorf("json-rpc unmarshal error: %v | %v", err, string(*res))
+// GetBlockVerbosity fetches a block at the given verbosity: 1 fills only the
+// txids in Block.Tx, 2 (as used by GetBlock) decodes every transaction.
+func (t *RpcTransport) GetBlockVerbosity(hash string, verbosity int) (*Block, error) {
+	if verbosity == 1 {
+		return Call[*Block](t, "getblock", hash, true)
 	}
 
-	return result, nil
+	return Call[*Block](t, "getblock", hash, verbosity)
 }
 
 func (t *RpcTransport) GetBlockHash(height int64) (string, error) {
-	res, err := t.Request("getblockhash", []any{height})
-	if err != nil {
-		return "", err
-	}
+	return Call[string](t, "getblockhash", height)
+}
 
-	var result string
-	err = json.Unmarshal(*res, &result)
+func (t *RpcTransport) GetBlockHeader(blockHash string) (header *BlockHeader, err error) {
+	return Call[*BlockHeader](t, "getblockheader", blockHash, true)
+}
+
+func (t *RpcTransport) GetBlockCount() (int64, error) {
+	result, err := Call[int64](t, "getblockcount")
 	if err != nil {
-		return "", fmt.Errorf("json-rpc unmarshal error: %v | %v", err, string(*res))
+		return -1, err
 	}
 
 	return result, nil
 }
+func (t *RpcTransport) GetBestBlockHash() (string, error) {
+	return Call[string](t, "getbestblockhash")
+}
 
-func (t *RpcTransport) GetBlockHeader(blockHash string) (header *BlockHeader, err error) {
-	res, err := t.Request("getblockheader", []any{blockHash, true})
-	if err != nil {
-		return nil, err
-	}
+func (t *RpcTransport) GetRawMempool() ([]string, error) {
+	return Call[[]string](t, "getrawmempool")
+}
 
-	var result *BlockHeader
-	err = json.Unmarshal(*res, &result)
-	if err != nil {
-		return nil, fmt.Errorf("json-rpc unmarshal error: %v | %v", err, string(*res))
-	}
+func (t *RpcTransport) GetTransaction(txid string) (*WalletTransaction, error) {
+	return Call[*WalletTransaction](t, "gettransaction", txid, true)
+}
 
-	return result, nil
+// GetRawTransaction returns the hex-encoded transaction txid. Without
+// -txindex the node only finds mempool transactions and those with unspent
+// outputs.
+func (t *RpcTransport) GetRawTransaction(txid string) (string, error) {
+	return Call[string](t, "getrawtransaction", txid, false)
 }
 
-func (t *RpcTransport) GetBlockCount() (int64, error) {
-	res, err := t.Request("getblockcount", []any{})
+// SendRawTransaction submits a serialised, hex-encoded transaction and
+// returns its txid.
+func (t *RpcTransport) SendRawTransaction(hex string) (string, error) {
+	return Call[string](t, "sendrawtransaction", hex)
+}
+
+func (t *RpcTransport) DecodeScript(hex string) (*DecodedScript, error) {
+	return Call[*DecodedScript](t, "decodescript", hex)
+}
+
+func (t *RpcTransport) ValidateAddress(address string) (*ValidatedAddress, error) {
+	return Call[*ValidatedAddress](t, "validateaddress", address)
+}
+
+func (t *RpcTransport) GetBlockchainInfo() (*BlockchainInfo, error) {
+	return Call[*BlockchainInfo](t, "getblockchaininfo")
+}
+
+// GetPeerInfo returns the node's connected peers.
+func (t *RpcTransport) GetPeerInfo() ([]PeerInfo, error) {
+	return Call[[]PeerInfo](t, "getpeerinfo")
+}
+
+// SetMockTime fixes the node's clock at timestamp, in seconds since the
+// epoch, or returns it to the system clock when timestamp is 0. The call is
+// hidden from help and only accepted on regtest.
+func (t *RpcTransport) SetMockTime(timestamp int64) error {
+	_, err := t.Request("setmocktime", []any{timestamp})
+	return err
+}
+
+// Call sends method with params and decodes the result into T. It is the
+// building block for every typed binding on RpcTransport.
+func Call[T any](t *RpcTransport, method string, params ...any) (T, error) {
+	var result T
+
+	if params == nil {
+		params = []any{}
+	}
+
+	res, err := t.Request(method, params)
 	if err != nil {
-		return -1, err
+		return result, err
 	}
 
-	var result int64
 	err = json.Unmarshal(*res, &result)
 	if err != nil {
-		return -1, fmt.Errorf("json-rpc unmarshal error: %v | %v", err, string(*res))
+		var zero T
+		return zero, fmt.Errorf("json-rpc unmarshal error: %v | %v", err, string(*res))
 	}
 
 	return result, nil
 }
-func (t *RpcTransport) GetBestBlockHash() (string, error) {
-	res, err := t.Request("getbestblockhash", []any{})
-	if err != nil {
-		return "", err
-	}
 
-	var result string
-	err = json.Unmarshal(*res, &result)
-	if err != nil {
-		return "", fmt.Errorf("json-rpc unmarshal error: %v | %v", err, string(*res))
+// trimParams drops trailing nil arguments so that optional parameters the
+// caller did not set fall back to the node's defaults. Core reads arguments
+// by position and rejects a null for most of them, so an unset argument
+// before a set one is replaced by its default from defaults, Core's default
+// as stated in help, or is an error when that is nil.
+func trimParams(method string, params []any, defaults []any) ([]any, error) {
+	n := len(params)
+	for n > 0 && isNil(params[n-1]) {
+		n--
+	}
+
+	trimmed := make([]any, n)
+	for i, param := range params[:n] {
+		if !isNil(param) {
+			trimmed[i] = param
+			continue
+		}
+
+		if i >= len(defaults) || defaults[i] == nil {
+			return nil, fmt.Errorf("%s: argument %d has no default and must be set when a later argument is", method, i+1)
+		}
+		trimmed[i] = defaults[i]
 	}
 
-	return result, nil
+	return trimmed, nil
 }
 
-func (t *RpcTransport) GetBlockchainInfo() (*BlockchainInfo, error) {
-	res, err := t.Request("getblockchaininfo", []any{})
-	if err != nil {
-		return nil, err
+func isNil(v any) bool {
+	if v == nil {
+		return true
 	}
 
-	var result *BlockchainInfo
-	err = json.Unmarshal(*res, &result)
-	if err != nil {
-		return nil, fmt.Errorf("json-rpc unmarshal error: %v | %v", err, string(*res))
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface:
+		return rv.IsNil()
 	}
 
-	return result, nil
+	return false
 }
 
 func (t *RpcTransport) Request(method string, params []any) (*json.RawMessage, error) {
@@ -265,6 +270,12 @@ func (t *RpcTransport) Request(method string, params []any) (*json.RawMessage, e
 	}
 	// check for error response
 	if res.StatusCode != 200 {
+		// Core reports failed calls with a non-200 status and the
+		// usual error object in the body.
+		var rpcres rpcResponse
+		if json.Unmarshal(res_bytes, &rpcres) == nil && rpcres.Error != nil {
+			return nil, coreError(rpcres.Error)
+		}
 		return nil, fmt.Errorf("json-rpc error status: %v | %v", res.StatusCode, string(res_bytes))
 	}
 	// cannot use json.NewDecoder: "The decoder introduces its own buffering
@@ -278,16 +289,11 @@ func (t *RpcTransport) Request(method string, params []any) (*json.RawMessage, e
 		return nil, fmt.Errorf("json-rpc wrong ID returned: %v vs %v", rpcres.Id, body.Id)
 	}
 	if rpcres.Error != nil {
-		enc, err := json.Marshal(rpcres.Error)
-		if err == nil {
-			return nil, fmt.Errorf("json-rpc: error from Core Node: %v", string(enc))
-		} else {
-			return nil, fmt.Errorf("json-rpc: error from Core Node: %v", rpcres.Error)
-		}
+		return nil, coreError(rpcres.Error)
 	}
-	if rpcres.Result == nil {
+	if len(rpcres.Result) == 0 {
 		return nil, fmt.Errorf("json-rpc no result or error was returned")
 	}
 
-	return rpcres.Result, nil
+	return &rpcres.Result, nil
 }
@@ -0,0 +1,1499 @@
+// Code generated by rpcgen from testdata/help.txt; DO NOT EDIT.
+
+package rpc
+
+import "encoding/json"
+
+// == Blockchain ==
+
+// GetChainTips calls the getchaintips RPC.
+//
+//	getchaintips
+//
+// Return information about all known tips in the block tree, including the main chain as well as orphaned branches.
+func (t *RpcTransport) GetChainTips() (json.RawMessage, error) {
+	return Call[json.RawMessage](t, "getchaintips")
+}
+
+// GetDifficulty calls the getdifficulty RPC.
+//
+//	getdifficulty
+//
+// Returns the proof-of-work difficulty as a multiple of the minimum difficulty.
+func (t *RpcTransport) GetDifficulty() (json.RawMessage, error) {
+	return Call[json.RawMessage](t, "getdifficulty")
+}
+
+// GetMempoolAncestorsParams holds the arguments of the getmempoolancestors RPC.
+// Optional arguments left nil are omitted from the request, or sent as
+// Core's default when a later argument is set.
+type GetMempoolAncestorsParams struct {
+	TxID    string // The transaction id (must be in mempool)
+	Verbose *bool  // true for a json object, false for array of transaction ids
+}
+
+// GetMempoolAncestors calls the getmempoolancestors RPC.
+//
+//	getmempoolancestors txid (verbose)
+//
+// If txid is in the mempool, returns all in-mempool ancestors.
+func (t *RpcTransport) GetMempoolAncestors(p GetMempoolAncestorsParams) (json.RawMessage, error) {
+	params, err := trimParams("getmempoolancestors", []any{p.TxID, p.Verbose}, []any{nil, false})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "getmempoolancestors", params...)
+}
+
+// GetMempoolDescendantsParams holds the arguments of the getmempooldescendants RPC.
+// Optional arguments left nil are omitted from the request, or sent as
+// Core's default when a later argument is set.
+type GetMempoolDescendantsParams struct {
+	TxID    string // The transaction id (must be in mempool)
+	Verbose *bool  // true for a json object, false for array of transaction ids
+}
+
+// GetMempoolDescendants calls the getmempooldescendants RPC.
+//
+//	getmempooldescendants txid (verbose)
+//
+// If txid is in the mempool, returns all in-mempool descendants.
+func (t *RpcTransport) GetMempoolDescendants(p GetMempoolDescendantsParams) (json.RawMessage, error) {
+	params, err := trimParams("getmempooldescendants", []any{p.TxID, p.Verbose}, []any{nil, false})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "getmempooldescendants", params...)
+}
+
+// GetMempoolEntryParams holds the arguments of the getmempoolentry RPC.
+type GetMempoolEntryParams struct {
+	TxID string // The transaction id (must be in mempool)
+}
+
+// GetMempoolEntry calls the getmempoolentry RPC.
+//
+//	getmempoolentry txid
+//
+// Returns mempool data for given transaction
+func (t *RpcTransport) GetMempoolEntry(p GetMempoolEntryParams) (json.RawMessage, error) {
+	params, err := trimParams("getmempoolentry", []any{p.TxID}, []any{nil})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "getmempoolentry", params...)
+}
+
+// GetMempoolInfo calls the getmempoolinfo RPC.
+//
+//	getmempoolinfo
+//
+// Returns details on the active state of the TX memory pool.
+func (t *RpcTransport) GetMempoolInfo() (json.RawMessage, error) {
+	return Call[json.RawMessage](t, "getmempoolinfo")
+}
+
+// GetTxOutParams holds the arguments of the gettxout RPC.
+// Optional arguments left nil are omitted from the request, or sent as
+// Core's default when a later argument is set.
+type GetTxOutParams struct {
+	TxID           string // The transaction id
+	N              int64  // vout number
+	IncludeMempool *bool  // Whether to include the mempool
+}
+
+// GetTxOut calls the gettxout RPC.
+//
+//	gettxout "txid" n ( includemempool )
+//
+// Returns details about an unspent transaction output.
+func (t *RpcTransport) GetTxOut(p GetTxOutParams) (json.RawMessage, error) {
+	params, err := trimParams("gettxout", []any{p.TxID, p.N, p.IncludeMempool}, []any{nil, nil, nil})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "gettxout", params...)
+}
+
+// GetTxOutProofParams holds the arguments of the gettxoutproof RPC.
+// Optional arguments left nil are omitted from the request, or sent as
+// Core's default when a later argument is set.
+type GetTxOutProofParams struct {
+	TxIDs     []any   // A json array of txids to filter
+	BlockHash *string // If specified, looks for txid in the block with this hash
+}
+
+// GetTxOutProof calls the gettxoutproof RPC.
+//
+//	gettxoutproof ["txid",...] ( blockhash )
+//
+// Returns a hex-encoded proof that "txid" was included in a block.
+// NOTE: By default this function only works sometimes. This is when there is an
+// unspent output in the utxo for this transaction. To make it always work,
+// you need to maintain a transaction index, using the -txindex command line option or
+// specify the block in which the transaction is included manually (by blockhash).
+func (t *RpcTransport) GetTxOutProof(p GetTxOutProofParams) (json.RawMessage, error) {
+	params, err := trimParams("gettxoutproof", []any{p.TxIDs, p.BlockHash}, []any{nil, nil})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "gettxoutproof", params...)
+}
+
+// GetTxOutSetInfo calls the gettxoutsetinfo RPC.
+//
+//	gettxoutsetinfo
+//
+// Returns statistics about the unspent transaction output set.
+// Note this call may take some time.
+func (t *RpcTransport) GetTxOutSetInfo() (json.RawMessage, error) {
+	return Call[json.RawMessage](t, "gettxoutsetinfo")
+}
+
+// PreciousBlockParams holds the arguments of the preciousblock RPC.
+type PreciousBlockParams struct {
+	BlockHash string // the hash of the block to mark as precious
+}
+
+// PreciousBlock calls the preciousblock RPC.
+//
+//	preciousblock "blockhash"
+//
+// Treats a block as if it were received before others with the same work.
+// A later preciousblock call can override the effect of an earlier one.
+// The effects of preciousblock are not retained across restarts.
+func (t *RpcTransport) PreciousBlock(p PreciousBlockParams) (json.RawMessage, error) {
+	params, err := trimParams("preciousblock", []any{p.BlockHash}, []any{nil})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "preciousblock", params...)
+}
+
+// PruneBlockchainParams holds the arguments of the pruneblockchain RPC.
+type PruneBlockchainParams struct {
+	Height int64 // The block height to prune up to. May be set to a discrete height, or a unix timestamp
+}
+
+// PruneBlockchain calls the pruneblockchain RPC.
+//
+//	pruneblockchain
+func (t *RpcTransport) PruneBlockchain(p PruneBlockchainParams) (json.RawMessage, error) {
+	params, err := trimParams("pruneblockchain", []any{p.Height}, []any{nil})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "pruneblockchain", params...)
+}
+
+// VerifyChainParams holds the arguments of the verifychain RPC.
+// Optional arguments left nil are omitted from the request, or sent as
+// Core's default when a later argument is set.
+type VerifyChainParams struct {
+	CheckLevel *int64 // How thorough the block verification is.
+	NBlocks    *int64 // The number of blocks to check.
+}
+
+// VerifyChain calls the verifychain RPC.
+//
+//	verifychain ( checklevel nblocks )
+//
+// Verifies blockchain database.
+func (t *RpcTransport) VerifyChain(p VerifyChainParams) (json.RawMessage, error) {
+	params, err := trimParams("verifychain", []any{p.CheckLevel, p.NBlocks}, []any{3, 288})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "verifychain", params...)
+}
+
+// VerifyTxOutProofParams holds the arguments of the verifytxoutproof RPC.
+type VerifyTxOutProofParams struct {
+	Proof string // The hex-encoded proof generated by gettxoutproof
+}
+
+// VerifyTxOutProof calls the verifytxoutproof RPC.
+//
+//	verifytxoutproof "proof"
+//
+// Verifies that a proof points to a transaction in a block, returning the transaction it commits to
+// and throwing an RPC error if the block is not in our best chain
+func (t *RpcTransport) VerifyTxOutProof(p VerifyTxOutProofParams) (json.RawMessage, error) {
+	params, err := trimParams("verifytxoutproof", []any{p.Proof}, []any{nil})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "verifytxoutproof", params...)
+}
+
+// == Control ==
+
+// GetMemoryInfo calls the getmemoryinfo RPC.
+//
+//	getmemoryinfo
+//
+// Returns an object containing information about memory usage.
+func (t *RpcTransport) GetMemoryInfo() (json.RawMessage, error) {
+	return Call[json.RawMessage](t, "getmemoryinfo")
+}
+
+// HelpParams holds the arguments of the help RPC.
+// Optional arguments left nil are omitted from the request, or sent as
+// Core's default when a later argument is set.
+type HelpParams struct {
+	Command *string // The command to get help on
+}
+
+// Help calls the help RPC.
+//
+//	help ( "command" )
+//
+// List all commands, or get help for a specified command.
+func (t *RpcTransport) Help(p HelpParams) (json.RawMessage, error) {
+	params, err := trimParams("help", []any{p.Command}, []any{nil})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "help", params...)
+}
+
+// Stop calls the stop RPC.
+//
+//	stop
+//
+// Stop Dogecoin server.
+func (t *RpcTransport) Stop() (json.RawMessage, error) {
+	return Call[json.RawMessage](t, "stop")
+}
+
+// == Mining ==
+
+// GetAuxBlockParams holds the arguments of the getauxblock RPC.
+// Optional arguments left nil are omitted from the request, or sent as
+// Core's default when a later argument is set.
+type GetAuxBlockParams struct {
+	Hash   *string // hash of the block to submit
+	Auxpow *string // serialised auxpow found
+}
+
+// GetAuxBlock calls the getauxblock RPC.
+//
+//	getauxblock (hash auxpow)
+//
+// Create or submit a merge-mined block.
+// Without arguments, create a new block and return information
+// required to merge-mine it.  With arguments, submit a solved
+// auxpow for a previously returned block.
+func (t *RpcTransport) GetAuxBlock(p GetAuxBlockParams) (json.RawMessage, error) {
+	params, err := trimParams("getauxblock", []any{p.Hash, p.Auxpow}, []any{nil, nil})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "getauxblock", params...)
+}
+
+// GetBlockTemplateParams holds the arguments of the getblocktemplate RPC.
+// Optional arguments left nil are omitted from the request, or sent as
+// Core's default when a later argument is set.
+type GetBlockTemplateParams struct {
+	TemplateRequest map[string]any // A json object in the following spec
+}
+
+// GetBlockTemplate calls the getblocktemplate RPC.
+//
+//	getblocktemplate ( TemplateRequest )
+//
+// If the request parameters include a 'mode' key, that is used to explicitly select between the default 'template' request or a 'proposal'.
+// It returns data needed to construct a block to work on.
+func (t *RpcTransport) GetBlockTemplate(p GetBlockTemplateParams) (json.RawMessage, error) {
+	params, err := trimParams("getblocktemplate", []any{p.TemplateRequest}, []any{nil})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "getblocktemplate", params...)
+}
+
+// GetMiningInfo calls the getmininginfo RPC.
+//
+//	getmininginfo
+//
+// Returns a json object containing mining-related information.
+func (t *RpcTransport) GetMiningInfo() (json.RawMessage, error) {
+	return Call[json.RawMessage](t, "getmininginfo")
+}
+
+// GetNetworkHashPSParams holds the arguments of the getnetworkhashps RPC.
+// Optional arguments left nil are omitted from the request, or sent as
+// Core's default when a later argument is set.
+type GetNetworkHashPSParams struct {
+	NBlocks *int64 // The number of blocks, or -1 for blocks since last difficulty change.
+	Height  *int64 // To estimate at the time of the given height.
+}
+
+// GetNetworkHashPS calls the getnetworkhashps RPC.
+//
+//	getnetworkhashps ( nblocks height )
+//
+// Returns the estimated network hashes per second based on the last n blocks.
+// Pass in [blocks] to override # of blocks, -1 specifies since last difficulty change.
+// Pass in [height] to estimate the network speed at the time when a certain block was found.
+func (t *RpcTransport) GetNetworkHashPS(p GetNetworkHashPSParams) (json.RawMessage, error) {
+	params, err := trimParams("getnetworkhashps", []any{p.NBlocks, p.Height}, []any{120, -1})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "getnetworkhashps", params...)
+}
+
+// PrioritiseTransactionParams holds the arguments of the prioritisetransaction RPC.
+type PrioritiseTransactionParams struct {
+	TxID          string  // The transaction id.
+	PriorityDelta float64 // The priority to add or subtract.
+	FeeDelta      float64 // The fee value (in koinu) to add (or subtract, if negative).
+}
+
+// PrioritiseTransaction calls the prioritisetransaction RPC.
+//
+//	prioritisetransaction <txid> <priority delta> <fee delta>
+//
+// Accepts the transaction into mined blocks at a higher (or lower) priority
+func (t *RpcTransport) PrioritiseTransaction(p PrioritiseTransactionParams) (json.RawMessage, error) {
+	params, err := trimParams("prioritisetransaction", []any{p.TxID, p.PriorityDelta, p.FeeDelta}, []any{nil, nil, nil})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "prioritisetransaction", params...)
+}
+
+// SubmitBlockParams holds the arguments of the submitblock RPC.
+// Optional arguments left nil are omitted from the request, or sent as
+// Core's default when a later argument is set.
+type SubmitBlockParams struct {
+	HexData    string  // the hex-encoded block data to submit
+	Parameters *string // object of optional parameters
+}
+
+// SubmitBlock calls the submitblock RPC.
+//
+//	submitblock "hexdata" ( "jsonparametersobject" )
+//
+// Attempts to submit new block to network.
+// The 'jsonparametersobject' parameter is currently ignored.
+// See https://en.bitcoin.it/wiki/BIP_0022 for full specification.
+func (t *RpcTransport) SubmitBlock(p SubmitBlockParams) (json.RawMessage, error) {
+	params, err := trimParams("submitblock", []any{p.HexData, p.Parameters}, []any{nil, nil})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "submitblock", params...)
+}
+
+// == Network ==
+
+// AddNodeParams holds the arguments of the addnode RPC.
+type AddNodeParams struct {
+	Node    string // The node (see getpeerinfo for nodes)
+	Command string // 'add' to add a node to the list, 'remove' to remove a node from the list, 'onetry' to try a connection to the node once
+}
+
+// AddNode calls the addnode RPC.
+//
+//	addnode "node" "add|remove|onetry"
+//
+// Attempts add or remove a node from the addnode list.
+// Or try a connection to a node once.
+func (t *RpcTransport) AddNode(p AddNodeParams) (json.RawMessage, error) {
+	params, err := trimParams("addnode", []any{p.Node, p.Command}, []any{nil, nil})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "addnode", params...)
+}
+
+// ClearBanned calls the clearbanned RPC.
+//
+//	clearbanned
+//
+// Clear all banned IPs.
+func (t *RpcTransport) ClearBanned() (json.RawMessage, error) {
+	return Call[json.RawMessage](t, "clearbanned")
+}
+
+// DisconnectNodeParams holds the arguments of the disconnectnode RPC.
+type DisconnectNodeParams struct {
+	Address string // The IP address/port of the node
+}
+
+// DisconnectNode calls the disconnectnode RPC.
+//
+//	disconnectnode "address"
+//
+// Immediately disconnects from the specified node.
+func (t *RpcTransport) DisconnectNode(p DisconnectNodeParams) (json.RawMessage, error) {
+	params, err := trimParams("disconnectnode", []any{p.Address}, []any{nil})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "disconnectnode", params...)
+}
+
+// GetAddedNodeInfoParams holds the arguments of the getaddednodeinfo RPC.
+// Optional arguments left nil are omitted from the request, or sent as
+// Core's default when a later argument is set.
+type GetAddedNodeInfoParams struct {
+	Node *string // If provided, return information about this specific node, otherwise all nodes are returned.
+}
+
+// GetAddedNodeInfo calls the getaddednodeinfo RPC.
+//
+//	getaddednodeinfo ( "node" )
+//
+// Returns information about the given added node, or all added nodes
+// (note that onetry addnodes are not listed here)
+func (t *RpcTransport) GetAddedNodeInfo(p GetAddedNodeInfoParams) (json.RawMessage, error) {
+	params, err := trimParams("getaddednodeinfo", []any{p.Node}, []any{nil})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "getaddednodeinfo", params...)
+}
+
+// GetConnectionCount calls the getconnectioncount RPC.
+//
+//	getconnectioncount
+//
+// Returns the number of connections to other nodes.
+func (t *RpcTransport) GetConnectionCount() (json.RawMessage, error) {
+	return Call[json.RawMessage](t, "getconnectioncount")
+}
+
+// GetNetTotals calls the getnettotals RPC.
+//
+//	getnettotals
+//
+// Returns information about network traffic, including bytes in, bytes out,
+// and current time.
+func (t *RpcTransport) GetNetTotals() (json.RawMessage, error) {
+	return Call[json.RawMessage](t, "getnettotals")
+}
+
+// GetNetworkInfo calls the getnetworkinfo RPC.
+//
+//	getnetworkinfo
+//
+// Returns an object containing various state info regarding P2P networking.
+func (t *RpcTransport) GetNetworkInfo() (json.RawMessage, error) {
+	return Call[json.RawMessage](t, "getnetworkinfo")
+}
+
+// ListBanned calls the listbanned RPC.
+//
+//	listbanned
+//
+// List all banned IPs/Subnets.
+func (t *RpcTransport) ListBanned() (json.RawMessage, error) {
+	return Call[json.RawMessage](t, "listbanned")
+}
+
+// Ping calls the ping RPC.
+//
+//	ping
+//
+// Requests that a ping be sent to all other nodes, to measure ping time.
+// Results provided in getpeerinfo, pingtime and pingwait fields are decimal seconds.
+// Ping command is handled in queue with all other commands, so it measures processing backlog, not just network ping.
+func (t *RpcTransport) Ping() (json.RawMessage, error) {
+	return Call[json.RawMessage](t, "ping")
+}
+
+// SetBanParams holds the arguments of the setban RPC.
+// Optional arguments left nil are omitted from the request, or sent as
+// Core's default when a later argument is set.
+type SetBanParams struct {
+	Subnet   string // The IP/Subnet (see getpeerinfo for nodes ip) with a optional netmask (default is /32 = single ip)
+	Command  string // 'add' to add a IP/Subnet to the list, 'remove' to remove a IP/Subnet from the list
+	BanTime  *int64 // time in seconds how long (or until when if [absolute] is set) the ip is banned (0 or empty means using the default time of 24h which can also be overwritten by the -bantime startup argument)
+	Absolute *bool  // If set, the bantime must be a absolute timestamp in seconds since epoch (Jan 1 1970 GMT)
+}
+
+// SetBan calls the setban RPC.
+//
+//	setban "subnet" "add|remove" (bantime) (absolute)
+//
+// Attempts add or remove a IP/Subnet from the banned list.
+func (t *RpcTransport) SetBan(p SetBanParams) (json.RawMessage, error) {
+	params, err := trimParams("setban", []any{p.Subnet, p.Command, p.BanTime, p.Absolute}, []any{nil, nil, nil, nil})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "setban", params...)
+}
+
+// SetNetworkActiveParams holds the arguments of the setnetworkactive RPC.
+type SetNetworkActiveParams struct {
+	State bool // true to enable networking, false to disable
+}
+
+// SetNetworkActive calls the setnetworkactive RPC.
+//
+//	setnetworkactive true|false
+//
+// Disable/enable all p2p network activity.
+func (t *RpcTransport) SetNetworkActive(p SetNetworkActiveParams) (json.RawMessage, error) {
+	params, err := trimParams("setnetworkactive", []any{p.State}, []any{nil})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "setnetworkactive", params...)
+}
+
+// == Rawtransactions ==
+
+// CreateRawTransactionParams holds the arguments of the createrawtransaction RPC.
+// Optional arguments left nil are omitted from the request, or sent as
+// Core's default when a later argument is set.
+type CreateRawTransactionParams struct {
+	Inputs   []any          // A json array of json objects
+	Outputs  map[string]any // a json object with outputs
+	LockTime *int64         // Raw locktime. Non-0 value also locktime-activates inputs
+}
+
+// CreateRawTransaction calls the createrawtransaction RPC.
+//
+//	createrawtransaction [{"txid":"id","vout":n},...] {"address":amount,"data":"hex",...} ( locktime )
+//
+// Create a transaction spending the given inputs and creating new outputs.
+// Outputs can be addresses or data.
+// Returns hex-encoded raw transaction.
+// Note that the transaction's inputs are not signed, and
+// it is not stored in the wallet or transmitted to the network.
+func (t *RpcTransport) CreateRawTransaction(p CreateRawTransactionParams) (json.RawMessage, error) {
+	params, err := trimParams("createrawtransaction", []any{p.Inputs, p.Outputs, p.LockTime}, []any{nil, nil, 0})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "createrawtransaction", params...)
+}
+
+// DecodeRawTransactionParams holds the arguments of the decoderawtransaction RPC.
+type DecodeRawTransactionParams struct {
+	HexString string // The transaction hex string
+}
+
+// DecodeRawTransaction calls the decoderawtransaction RPC.
+//
+//	decoderawtransaction "hexstring"
+//
+// Return a JSON object representing the serialized, hex-encoded transaction.
+func (t *RpcTransport) DecodeRawTransaction(p DecodeRawTransactionParams) (json.RawMessage, error) {
+	params, err := trimParams("decoderawtransaction", []any{p.HexString}, []any{nil})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "decoderawtransaction", params...)
+}
+
+// FundRawTransactionParams holds the arguments of the fundrawtransaction RPC.
+// Optional arguments left nil are omitted from the request, or sent as
+// Core's default when a later argument is set.
+type FundRawTransactionParams struct {
+	HexString string // The hex string of the raw transaction
+	Options   map[string]any
+}
+
+// FundRawTransaction calls the fundrawtransaction RPC.
+//
+//	fundrawtransaction "hexstring" ( options )
+//
+// Add inputs to a transaction until it has enough in value to meet its out value.
+// This will not modify existing inputs, and will add at most one change output to the outputs.
+// No existing outputs will be modified unless "subtractFeeFromOutputs" is specified.
+// Note that inputs which were signed may need to be resigned after completion since in/outputs have been added.
+// The inputs added will not be signed, use signrawtransaction for that.
+func (t *RpcTransport) FundRawTransaction(p FundRawTransactionParams) (json.RawMessage, error) {
+	params, err := trimParams("fundrawtransaction", []any{p.HexString, p.Options}, []any{nil, nil})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "fundrawtransaction", params...)
+}
+
+// SignRawTransactionParams holds the arguments of the signrawtransaction RPC.
+// Optional arguments left nil are omitted from the request, or sent as
+// Core's default when a later argument is set.
+type SignRawTransactionParams struct {
+	HexString   string  // The transaction hex string
+	PrevTxs     []any   // An json array of previous dependent transaction outputs
+	PrivKeys    []any   // A json array of base58-encoded private keys for signing
+	SighashType *string // The signature hash type. Must be one of
+}
+
+// SignRawTransaction calls the signrawtransaction RPC.
+//
+//	signrawtransaction "hexstring" ( [{"txid":"id","vout":n,"scriptPubKey":"hex","redeemScript":"hex"},...] ["privatekey1",...] sighashtype )
+//
+// Sign inputs for raw transaction (serialized, hex-encoded).
+// The second optional argument (may be null) is an array of previous transaction outputs that
+// this transaction depends on but may not yet be in the block chain.
+// The third optional argument (may be null) is an array of base58-encoded private
+// keys that, if given, will be the only keys used to sign the transaction.
+func (t *RpcTransport) SignRawTransaction(p SignRawTransactionParams) (json.RawMessage, error) {
+	params, err := trimParams("signrawtransaction", []any{p.HexString, p.PrevTxs, p.PrivKeys, p.SighashType}, []any{nil, nil, nil, "ALL"})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "signrawtransaction", params...)
+}
+
+// == Util ==
+
+// CreateMultisigParams holds the arguments of the createmultisig RPC.
+type CreateMultisigParams struct {
+	NRequired int64 // The number of required signatures out of the n keys or addresses.
+	Keys      []any // A json array of keys which are dogecoin addresses or hex-encoded public keys
+}
+
+// CreateMultisig calls the createmultisig RPC.
+//
+//	createmultisig nrequired ["key",...]
+//
+// Creates a multi-signature address with n signature of m keys required.
+// It returns a json object with the address and redeemScript.
+func (t *RpcTransport) CreateMultisig(p CreateMultisigParams) (json.RawMessage, error) {
+	params, err := trimParams("createmultisig", []any{p.NRequired, p.Keys}, []any{nil, nil})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "createmultisig", params...)
+}
+
+// EstimateFeeParams holds the arguments of the estimatefee RPC.
+type EstimateFeeParams struct {
+	NBlocks int64
+}
+
+// EstimateFee calls the estimatefee RPC.
+//
+//	estimatefee nblocks
+//
+// Estimates the approximate fee per kilobyte needed for a transaction to begin
+// confirmation within nblocks blocks.
+func (t *RpcTransport) EstimateFee(p EstimateFeeParams) (json.RawMessage, error) {
+	params, err := trimParams("estimatefee", []any{p.NBlocks}, []any{nil})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "estimatefee", params...)
+}
+
+// EstimatePriorityParams holds the arguments of the estimatepriority RPC.
+type EstimatePriorityParams struct {
+	NBlocks int64
+}
+
+// EstimatePriority calls the estimatepriority RPC.
+//
+//	estimatepriority nblocks
+//
+// DEPRECATED. Estimates the approximate priority a zero-fee transaction needs to begin
+// confirmation within nblocks blocks.
+func (t *RpcTransport) EstimatePriority(p EstimatePriorityParams) (json.RawMessage, error) {
+	params, err := trimParams("estimatepriority", []any{p.NBlocks}, []any{nil})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "estimatepriority", params...)
+}
+
+// EstimateSmartFeeParams holds the arguments of the estimatesmartfee RPC.
+type EstimateSmartFeeParams struct {
+	NBlocks int64
+}
+
+// EstimateSmartFee calls the estimatesmartfee RPC.
+//
+//	estimatesmartfee nblocks
+//
+// WARNING: This interface is unstable and may disappear or change!
+// Estimates the approximate fee per kilobyte needed for a transaction to begin
+// confirmation within nblocks blocks if possible and return the number of blocks
+// for which the estimate is valid.
+func (t *RpcTransport) EstimateSmartFee(p EstimateSmartFeeParams) (json.RawMessage, error) {
+	params, err := trimParams("estimatesmartfee", []any{p.NBlocks}, []any{nil})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "estimatesmartfee", params...)
+}
+
+// EstimateSmartPriorityParams holds the arguments of the estimatesmartpriority RPC.
+type EstimateSmartPriorityParams struct {
+	NBlocks int64
+}
+
+// EstimateSmartPriority calls the estimatesmartpriority RPC.
+//
+//	estimatesmartpriority nblocks
+//
+// DEPRECATED. WARNING: This interface is unstable and may disappear or change!
+// Estimates the approximate priority a zero-fee transaction needs to begin
+// confirmation within nblocks blocks if possible and return the number of blocks
+// for which the estimate is valid.
+func (t *RpcTransport) EstimateSmartPriority(p EstimateSmartPriorityParams) (json.RawMessage, error) {
+	params, err := trimParams("estimatesmartpriority", []any{p.NBlocks}, []any{nil})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "estimatesmartpriority", params...)
+}
+
+// SignMessageWithPrivKeyParams holds the arguments of the signmessagewithprivkey RPC.
+type SignMessageWithPrivKeyParams struct {
+	PrivKey string // The private key to sign the message with.
+	Message string // The message to create a signature of.
+}
+
+// SignMessageWithPrivKey calls the signmessagewithprivkey RPC.
+//
+//	signmessagewithprivkey "privkey" "message"
+//
+// Sign a message with the private key of an address
+func (t *RpcTransport) SignMessageWithPrivKey(p SignMessageWithPrivKeyParams) (json.RawMessage, error) {
+	params, err := trimParams("signmessagewithprivkey", []any{p.PrivKey, p.Message}, []any{nil, nil})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "signmessagewithprivkey", params...)
+}
+
+// VerifyMessageParams holds the arguments of the verifymessage RPC.
+type VerifyMessageParams struct {
+	Address   string // The dogecoin address to use for the signature.
+	Signature string // The signature provided by the signer in base 64 encoding (see signmessage).
+	Message   string // The message that was signed.
+}
+
+// VerifyMessage calls the verifymessage RPC.
+//
+//	verifymessage "address" "signature" "message"
+//
+// Verify a signed message
+func (t *RpcTransport) VerifyMessage(p VerifyMessageParams) (json.RawMessage, error) {
+	params, err := trimParams("verifymessage", []any{p.Address, p.Signature, p.Message}, []any{nil, nil, nil})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "verifymessage", params...)
+}
+
+// == Wallet ==
+
+// AbandonTransactionParams holds the arguments of the abandontransaction RPC.
+type AbandonTransactionParams struct {
+	TxID string // The transaction id
+}
+
+// AbandonTransaction calls the abandontransaction RPC.
+//
+//	abandontransaction "txid"
+//
+// Mark in-wallet transaction <txid> as abandoned
+// This will mark this transaction and all its in-wallet descendants as abandoned which will allow
+// for their inputs to be respent.  It can be used to replace "stuck" or evicted transactions.
+// It only works on transactions which are not included in a block and are not currently in the mempool.
+// It has no effect on transactions which are already conflicted or abandoned.
+func (t *RpcTransport) AbandonTransaction(p AbandonTransactionParams) (json.RawMessage, error) {
+	params, err := trimParams("abandontransaction", []any{p.TxID}, []any{nil})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "abandontransaction", params...)
+}
+
+// AddMultisigAddressParams holds the arguments of the addmultisigaddress RPC.
+// Optional arguments left nil are omitted from the request, or sent as
+// Core's default when a later argument is set.
+type AddMultisigAddressParams struct {
+	NRequired int64   // The number of required signatures out of the n keys or addresses.
+	Keys      []any   // A json array of dogecoin addresses or hex-encoded public keys
+	Account   *string // DEPRECATED. An account to assign the addresses to.
+}
+
+// AddMultisigAddress calls the addmultisigaddress RPC.
+//
+//	addmultisigaddress nrequired ["key",...] ( "account" )
+//
+// Add a nrequired-to-sign multisignature address to the wallet.
+// Each key is a Dogecoin address or hex-encoded public key.
+// If 'account' is specified (DEPRECATED), assign address to that account.
+func (t *RpcTransport) AddMultisigAddress(p AddMultisigAddressParams) (json.RawMessage, error) {
+	params, err := trimParams("addmultisigaddress", []any{p.NRequired, p.Keys, p.Account}, []any{nil, nil, nil})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "addmultisigaddress", params...)
+}
+
+// DumpWalletParams holds the arguments of the dumpwallet RPC.
+type DumpWalletParams struct {
+	Filename string // The filename
+}
+
+// DumpWallet calls the dumpwallet RPC.
+//
+//	dumpwallet "filename"
+//
+// Dumps all wallet keys in a human-readable format.
+func (t *RpcTransport) DumpWallet(p DumpWalletParams) (json.RawMessage, error) {
+	params, err := trimParams("dumpwallet", []any{p.Filename}, []any{nil})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "dumpwallet", params...)
+}
+
+// GetAccountParams holds the arguments of the getaccount RPC.
+type GetAccountParams struct {
+	Address string // The dogecoin address for account lookup.
+}
+
+// GetAccount calls the getaccount RPC.
+//
+//	getaccount "address"
+//
+// DEPRECATED. Returns the account associated with the given address.
+func (t *RpcTransport) GetAccount(p GetAccountParams) (json.RawMessage, error) {
+	params, err := trimParams("getaccount", []any{p.Address}, []any{nil})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "getaccount", params...)
+}
+
+// GetAccountAddressParams holds the arguments of the getaccountaddress RPC.
+type GetAccountAddressParams struct {
+	Account string // The account name for the address. It can also be set to the empty string "" to represent the default account. The account does not need to exist, it will be created and a new address created  if there is no account by the given name.
+}
+
+// GetAccountAddress calls the getaccountaddress RPC.
+//
+//	getaccountaddress "account"
+//
+// DEPRECATED. Returns the current Dogecoin address for receiving payments to this account.
+func (t *RpcTransport) GetAccountAddress(p GetAccountAddressParams) (json.RawMessage, error) {
+	params, err := trimParams("getaccountaddress", []any{p.Account}, []any{nil})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "getaccountaddress", params...)
+}
+
+// GetAddressesByAccountParams holds the arguments of the getaddressesbyaccount RPC.
+type GetAddressesByAccountParams struct {
+	Account string // The account name.
+}
+
+// GetAddressesByAccount calls the getaddressesbyaccount RPC.
+//
+//	getaddressesbyaccount "account"
+//
+// DEPRECATED. Returns the list of addresses for the given account.
+func (t *RpcTransport) GetAddressesByAccount(p GetAddressesByAccountParams) (json.RawMessage, error) {
+	params, err := trimParams("getaddressesbyaccount", []any{p.Account}, []any{nil})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "getaddressesbyaccount", params...)
+}
+
+// GetBalanceParams holds the arguments of the getbalance RPC.
+// Optional arguments left nil are omitted from the request, or sent as
+// Core's default when a later argument is set.
+type GetBalanceParams struct {
+	Account          *string // DEPRECATED. The account string may be given as a
+	MinConf          *int64  // Only include transactions confirmed at least this many times.
+	IncludeWatchonly *bool   // Also include balance in watch-only addresses (see 'importaddress')
+}
+
+// GetBalance calls the getbalance RPC.
+//
+//	getbalance ( "account" minconf include_watchonly )
+//
+// If account is not specified, returns the server's total available balance.
+// If account is specified (DEPRECATED), returns the balance in the account.
+// Note that the account "" is not the same as leaving the parameter out.
+// The server total may be different to the balance in the default "" account.
+func (t *RpcTransport) GetBalance(p GetBalanceParams) (json.RawMessage, error) {
+	params, err := trimParams("getbalance", []any{p.Account, p.MinConf, p.IncludeWatchonly}, []any{nil, 1, false})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "getbalance", params...)
+}
+
+// GetRawChangeAddress calls the getrawchangeaddress RPC.
+//
+//	getrawchangeaddress
+//
+// Returns a new Dogecoin address, for receiving change.
+// This is for use with raw transactions, NOT normal use.
+func (t *RpcTransport) GetRawChangeAddress() (json.RawMessage, error) {
+	return Call[json.RawMessage](t, "getrawchangeaddress")
+}
+
+// GetReceivedByAccountParams holds the arguments of the getreceivedbyaccount RPC.
+// Optional arguments left nil are omitted from the request, or sent as
+// Core's default when a later argument is set.
+type GetReceivedByAccountParams struct {
+	Account string // The selected account, may be the default account using "".
+	MinConf *int64 // Only include transactions confirmed at least this many times.
+}
+
+// GetReceivedByAccount calls the getreceivedbyaccount RPC.
+//
+//	getreceivedbyaccount "account" ( minconf )
+//
+// DEPRECATED. Returns the total amount received by addresses with <account> in transactions with at least [minconf] confirmations.
+func (t *RpcTransport) GetReceivedByAccount(p GetReceivedByAccountParams) (json.RawMessage, error) {
+	params, err := trimParams("getreceivedbyaccount", []any{p.Account, p.MinConf}, []any{nil, 1})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "getreceivedbyaccount", params...)
+}
+
+// GetReceivedByAddressParams holds the arguments of the getreceivedbyaddress RPC.
+// Optional arguments left nil are omitted from the request, or sent as
+// Core's default when a later argument is set.
+type GetReceivedByAddressParams struct {
+	Address string // The dogecoin address for transactions.
+	MinConf *int64 // Only include transactions confirmed at least this many times.
+}
+
+// GetReceivedByAddress calls the getreceivedbyaddress RPC.
+//
+//	getreceivedbyaddress "address" ( minconf )
+//
+// Returns the total amount received by the given address in transactions with at least minconf confirmations.
+func (t *RpcTransport) GetReceivedByAddress(p GetReceivedByAddressParams) (json.RawMessage, error) {
+	params, err := trimParams("getreceivedbyaddress", []any{p.Address, p.MinConf}, []any{nil, 1})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "getreceivedbyaddress", params...)
+}
+
+// GetUnconfirmedBalance calls the getunconfirmedbalance RPC.
+//
+//	getunconfirmedbalance
+//
+// Returns the server's total unconfirmed balance
+func (t *RpcTransport) GetUnconfirmedBalance() (json.RawMessage, error) {
+	return Call[json.RawMessage](t, "getunconfirmedbalance")
+}
+
+// ImportAddressParams holds the arguments of the importaddress RPC.
+// Optional arguments left nil are omitted from the request, or sent as
+// Core's default when a later argument is set.
+type ImportAddressParams struct {
+	Script string  // The hex-encoded script (or address)
+	Label  *string // An optional label
+	Rescan *bool   // Rescan the wallet for transactions
+	P2SH   *bool   // Add the P2SH version of the script as well
+}
+
+// ImportAddress calls the importaddress RPC.
+//
+//	importaddress "address" ( "label" rescan p2sh )
+//
+// Adds a script (in hex) or address that can be watched as if it were in your wallet but cannot be used to spend.
+func (t *RpcTransport) ImportAddress(p ImportAddressParams) (json.RawMessage, error) {
+	params, err := trimParams("importaddress", []any{p.Script, p.Label, p.Rescan, p.P2SH}, []any{nil, "", true, false})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "importaddress", params...)
+}
+
+// ImportMultiParams holds the arguments of the importmulti RPC.
+// Optional arguments left nil are omitted from the request, or sent as
+// Core's default when a later argument is set.
+type ImportMultiParams struct {
+	Requests []any // Data to be imported
+	Options  map[string]any
+}
+
+// ImportMulti calls the importmulti RPC.
+//
+//	importmulti "requests" "options"
+//
+// Import addresses/scripts (with private or public keys, redeem script (P2SH)), rescanning all addresses in one-shot-only (rescan can be disabled via options).
+func (t *RpcTransport) ImportMulti(p ImportMultiParams) (json.RawMessage, error) {
+	params, err := trimParams("importmulti", []any{p.Requests, p.Options}, []any{nil, nil})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "importmulti", params...)
+}
+
+// ImportPrivKeyParams holds the arguments of the importprivkey RPC.
+// Optional arguments left nil are omitted from the request, or sent as
+// Core's default when a later argument is set.
+type ImportPrivKeyParams struct {
+	DogecoinPrivKey string  // The private key (see dumpprivkey)
+	Label           *string // An optional label
+	Rescan          *bool   // Rescan the wallet for transactions
+}
+
+// ImportPrivKey calls the importprivkey RPC.
+//
+//	importprivkey "dogecoinprivkey" ( "label" ) ( rescan )
+//
+// Adds a private key (as returned by dumpprivkey) to your wallet.
+func (t *RpcTransport) ImportPrivKey(p ImportPrivKeyParams) (json.RawMessage, error) {
+	params, err := trimParams("importprivkey", []any{p.DogecoinPrivKey, p.Label, p.Rescan}, []any{nil, "", true})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "importprivkey", params...)
+}
+
+// ImportPrunedFundsParams holds the arguments of the importprunedfunds RPC.
+type ImportPrunedFundsParams struct {
+	RawTransaction string // A raw transaction in hex funding an already-existing address in wallet
+	TxOutProof     string // The hex output from gettxoutproof that contains the transaction
+}
+
+// ImportPrunedFunds calls the importprunedfunds RPC.
+//
+//	importprunedfunds
+//
+// Imports funds without rescan. Corresponding address or script must previously be included in wallet. Aimed towards pruned wallets. The end-user is responsible to import additional transactions that subsequently spend the imported outputs or rescan after the point in the blockchain the transaction is included.
+func (t *RpcTransport) ImportPrunedFunds(p ImportPrunedFundsParams) (json.RawMessage, error) {
+	params, err := trimParams("importprunedfunds", []any{p.RawTransaction, p.TxOutProof}, []any{nil, nil})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "importprunedfunds", params...)
+}
+
+// ImportPubKeyParams holds the arguments of the importpubkey RPC.
+// Optional arguments left nil are omitted from the request, or sent as
+// Core's default when a later argument is set.
+type ImportPubKeyParams struct {
+	PubKey string  // The hex-encoded public key
+	Label  *string // An optional label
+	Rescan *bool   // Rescan the wallet for transactions
+}
+
+// ImportPubKey calls the importpubkey RPC.
+//
+//	importpubkey "pubkey" ( "label" rescan )
+//
+// Adds a public key (in hex) that can be watched as if it were in your wallet but cannot be used to spend.
+func (t *RpcTransport) ImportPubKey(p ImportPubKeyParams) (json.RawMessage, error) {
+	params, err := trimParams("importpubkey", []any{p.PubKey, p.Label, p.Rescan}, []any{nil, "", true})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "importpubkey", params...)
+}
+
+// ImportWalletParams holds the arguments of the importwallet RPC.
+type ImportWalletParams struct {
+	Filename string // The wallet file
+}
+
+// ImportWallet calls the importwallet RPC.
+//
+//	importwallet "filename"
+//
+// Imports keys from a wallet dump file (see dumpwallet).
+func (t *RpcTransport) ImportWallet(p ImportWalletParams) (json.RawMessage, error) {
+	params, err := trimParams("importwallet", []any{p.Filename}, []any{nil})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "importwallet", params...)
+}
+
+// KeypoolRefillParams holds the arguments of the keypoolrefill RPC.
+// Optional arguments left nil are omitted from the request, or sent as
+// Core's default when a later argument is set.
+type KeypoolRefillParams struct {
+	NewSize *int64 // The new keypool size
+}
+
+// KeypoolRefill calls the keypoolrefill RPC.
+//
+//	keypoolrefill ( newsize )
+//
+// Fills the keypool.
+func (t *RpcTransport) KeypoolRefill(p KeypoolRefillParams) (json.RawMessage, error) {
+	params, err := trimParams("keypoolrefill", []any{p.NewSize}, []any{100})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "keypoolrefill", params...)
+}
+
+// ListAccountsParams holds the arguments of the listaccounts RPC.
+// Optional arguments left nil are omitted from the request, or sent as
+// Core's default when a later argument is set.
+type ListAccountsParams struct {
+	MinConf          *int64 // Only include transactions with at least this many confirmations
+	IncludeWatchonly *bool  // Include balances in watch-only addresses (see 'importaddress')
+}
+
+// ListAccounts calls the listaccounts RPC.
+//
+//	listaccounts ( minconf include_watchonly)
+//
+// DEPRECATED. Returns Object that has account names as keys, account balances as values.
+func (t *RpcTransport) ListAccounts(p ListAccountsParams) (json.RawMessage, error) {
+	params, err := trimParams("listaccounts", []any{p.MinConf, p.IncludeWatchonly}, []any{1, false})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "listaccounts", params...)
+}
+
+// ListAddressGroupings calls the listaddressgroupings RPC.
+//
+//	listaddressgroupings
+//
+// Lists groups of addresses which have had their common ownership
+// made public by common use as inputs or as the resulting change
+// in past transactions
+func (t *RpcTransport) ListAddressGroupings() (json.RawMessage, error) {
+	return Call[json.RawMessage](t, "listaddressgroupings")
+}
+
+// ListLockUnspent calls the listlockunspent RPC.
+//
+//	listlockunspent
+//
+// Returns list of temporarily unspendable outputs.
+// See the lockunspent call to lock and unlock transactions for spending.
+func (t *RpcTransport) ListLockUnspent() (json.RawMessage, error) {
+	return Call[json.RawMessage](t, "listlockunspent")
+}
+
+// ListReceivedByAccountParams holds the arguments of the listreceivedbyaccount RPC.
+// Optional arguments left nil are omitted from the request, or sent as
+// Core's default when a later argument is set.
+type ListReceivedByAccountParams struct {
+	MinConf          *int64 // The minimum number of confirmations before payments are included.
+	IncludeEmpty     *bool  // Whether to include accounts that haven't received any payments.
+	IncludeWatchonly *bool  // Whether to include watch-only addresses (see 'importaddress').
+}
+
+// ListReceivedByAccount calls the listreceivedbyaccount RPC.
+//
+//	listreceivedbyaccount ( minconf include_empty include_watchonly)
+//
+// DEPRECATED. List balances by account.
+func (t *RpcTransport) ListReceivedByAccount(p ListReceivedByAccountParams) (json.RawMessage, error) {
+	params, err := trimParams("listreceivedbyaccount", []any{p.MinConf, p.IncludeEmpty, p.IncludeWatchonly}, []any{1, false, false})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "listreceivedbyaccount", params...)
+}
+
+// ListReceivedByAddressParams holds the arguments of the listreceivedbyaddress RPC.
+// Optional arguments left nil are omitted from the request, or sent as
+// Core's default when a later argument is set.
+type ListReceivedByAddressParams struct {
+	MinConf          *int64 // The minimum number of confirmations before payments are included.
+	IncludeEmpty     *bool  // Whether to include addresses that haven't received any payments.
+	IncludeWatchonly *bool  // Whether to include watch-only addresses (see 'importaddress').
+}
+
+// ListReceivedByAddress calls the listreceivedbyaddress RPC.
+//
+//	listreceivedbyaddress ( minconf include_empty include_watchonly)
+//
+// List balances by receiving address.
+func (t *RpcTransport) ListReceivedByAddress(p ListReceivedByAddressParams) (json.RawMessage, error) {
+	params, err := trimParams("listreceivedbyaddress", []any{p.MinConf, p.IncludeEmpty, p.IncludeWatchonly}, []any{1, false, false})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "listreceivedbyaddress", params...)
+}
+
+// ListSinceBlockParams holds the arguments of the listsinceblock RPC.
+// Optional arguments left nil are omitted from the request, or sent as
+// Core's default when a later argument is set.
+type ListSinceBlockParams struct {
+	BlockHash           *string // The block hash to list transactions since
+	TargetConfirmations *int64  // The confirmations required, must be 1 or more
+	IncludeWatchonly    *bool   // Include transactions to watch-only addresses (see 'importaddress')
+}
+
+// ListSinceBlock calls the listsinceblock RPC.
+//
+//	listsinceblock ( "blockhash" target_confirmations include_watchonly)
+//
+// Get all transactions in blocks since block [blockhash], or all transactions if omitted
+func (t *RpcTransport) ListSinceBlock(p ListSinceBlockParams) (json.RawMessage, error) {
+	params, err := trimParams("listsinceblock", []any{p.BlockHash, p.TargetConfirmations, p.IncludeWatchonly}, []any{nil, nil, false})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "listsinceblock", params...)
+}
+
+// ListTransactionsParams holds the arguments of the listtransactions RPC.
+// Optional arguments left nil are omitted from the request, or sent as
+// Core's default when a later argument is set.
+type ListTransactionsParams struct {
+	Account          *string // DEPRECATED. The account name. Should be "*".
+	Count            *int64  // The number of transactions to return
+	Skip             *int64  // The number of transactions to skip
+	IncludeWatchonly *bool   // Include transactions to watch-only addresses (see 'importaddress')
+}
+
+// ListTransactions calls the listtransactions RPC.
+//
+//	listtransactions ( "account" count skip include_watchonly)
+//
+// Returns up to 'count' most recent transactions skipping the first 'from' transactions for account 'account'.
+func (t *RpcTransport) ListTransactions(p ListTransactionsParams) (json.RawMessage, error) {
+	params, err := trimParams("listtransactions", []any{p.Account, p.Count, p.Skip, p.IncludeWatchonly}, []any{nil, 10, 0, false})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "listtransactions", params...)
+}
+
+// LockUnspentParams holds the arguments of the lockunspent RPC.
+// Optional arguments left nil are omitted from the request, or sent as
+// Core's default when a later argument is set.
+type LockUnspentParams struct {
+	Unlock       bool  // Whether to unlock (true) or lock (false) the specified transactions
+	Transactions []any // A json array of objects. Each object the txid (string) vout (numeric)
+}
+
+// LockUnspent calls the lockunspent RPC.
+//
+//	lockunspent unlock ([{"txid":"txid","vout":n},...])
+//
+// Updates list of temporarily unspendable outputs.
+// Temporarily lock (unlock=false) or unlock (unlock=true) specified transaction outputs.
+// If no transaction outputs are specified when unlocking then all current locked transaction outputs are unlocked.
+func (t *RpcTransport) LockUnspent(p LockUnspentParams) (json.RawMessage, error) {
+	params, err := trimParams("lockunspent", []any{p.Unlock, p.Transactions}, []any{nil, nil})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "lockunspent", params...)
+}
+
+// MoveParams holds the arguments of the move RPC.
+// Optional arguments left nil are omitted from the request, or sent as
+// Core's default when a later argument is set.
+type MoveParams struct {
+	FromAccount string  // The name of the account to move funds from. May be the default account using "".
+	ToAccount   string  // The name of the account to move funds to. May be the default account using "".
+	Amount      float64 // Quantity of DOGE to move between accounts.
+	Dummy       *int64  // Ignored. Remains for backward compatibility.
+	Comment     *string // An optional comment, stored in the wallet only.
+}
+
+// Move calls the move RPC.
+//
+//	move "fromaccount" "toaccount" amount ( minconf "comment" )
+//
+// DEPRECATED. Move a specified amount from one account in your wallet to another.
+func (t *RpcTransport) Move(p MoveParams) (json.RawMessage, error) {
+	params, err := trimParams("move", []any{p.FromAccount, p.ToAccount, p.Amount, p.Dummy, p.Comment}, []any{nil, nil, nil, nil, nil})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "move", params...)
+}
+
+// RemovePrunedFundsParams holds the arguments of the removeprunedfunds RPC.
+type RemovePrunedFundsParams struct {
+	TxID string // The hex-encoded id of the transaction you are deleting
+}
+
+// RemovePrunedFunds calls the removeprunedfunds RPC.
+//
+//	removeprunedfunds "txid"
+//
+// Deletes the specified transaction from the wallet. Meant for use with pruned wallets and as a companion to importprunedfunds. This will effect wallet balances.
+func (t *RpcTransport) RemovePrunedFunds(p RemovePrunedFundsParams) (json.RawMessage, error) {
+	params, err := trimParams("removeprunedfunds", []any{p.TxID}, []any{nil})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "removeprunedfunds", params...)
+}
+
+// SendFromParams holds the arguments of the sendfrom RPC.
+// Optional arguments left nil are omitted from the request, or sent as
+// Core's default when a later argument is set.
+type SendFromParams struct {
+	FromAccount string  // The name of the account to send funds from. May be the default account using "".
+	ToAddress   string  // The dogecoin address to send funds to.
+	Amount      float64 // The amount in DOGE (transaction fee is added on top).
+	MinConf     *int64  // Only use funds with at least this many confirmations.
+	Comment     *string // A comment used to store what the transaction is for.
+	CommentTo   *string // An optional comment to store the name of the person or organization
+}
+
+// SendFrom calls the sendfrom RPC.
+//
+//	sendfrom "fromaccount" "toaddress" amount ( minconf "comment" "comment_to" )
+//
+// DEPRECATED (use sendtoaddress). Sent an amount from an account to a dogecoin address.
+func (t *RpcTransport) SendFrom(p SendFromParams) (json.RawMessage, error) {
+	params, err := trimParams("sendfrom", []any{p.FromAccount, p.ToAddress, p.Amount, p.MinConf, p.Comment, p.CommentTo}, []any{nil, nil, nil, 1, nil, nil})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "sendfrom", params...)
+}
+
+// SendManyParams holds the arguments of the sendmany RPC.
+// Optional arguments left nil are omitted from the request, or sent as
+// Core's default when a later argument is set.
+type SendManyParams struct {
+	FromAccount     string         // DEPRECATED. The account to send the funds from. Should be "" for the default account
+	Amounts         map[string]any // A json object with addresses and amounts
+	MinConf         *int64         // Only use the balance confirmed at least this many times.
+	Comment         *string        // A comment
+	SubtractFeeFrom []any          // A json array with addresses.
+}
+
+// SendMany calls the sendmany RPC.
+//
+//	sendmany "fromaccount" {"address":amount,...} ( minconf "comment" ["address",...] )
+//
+// Send multiple times. Amounts are double-precision floating point numbers.
+func (t *RpcTransport) SendMany(p SendManyParams) (json.RawMessage, error) {
+	params, err := trimParams("sendmany", []any{p.FromAccount, p.Amounts, p.MinConf, p.Comment, p.SubtractFeeFrom}, []any{nil, nil, 1, nil, nil})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "sendmany", params...)
+}
+
+// SetAccountParams holds the arguments of the setaccount RPC.
+type SetAccountParams struct {
+	Address string // The dogecoin address to be associated with an account.
+	Account string // The account to assign the address to.
+}
+
+// SetAccount calls the setaccount RPC.
+//
+//	setaccount "address" "account"
+//
+// DEPRECATED. Sets the account associated with the given address.
+func (t *RpcTransport) SetAccount(p SetAccountParams) (json.RawMessage, error) {
+	params, err := trimParams("setaccount", []any{p.Address, p.Account}, []any{nil, nil})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "setaccount", params...)
+}
+
+// SetTxFeeParams holds the arguments of the settxfee RPC.
+type SetTxFeeParams struct {
+	Amount float64 // The transaction fee in DOGE/kB
+}
+
+// SetTxFee calls the settxfee RPC.
+//
+//	settxfee amount
+//
+// Set the transaction fee per kB. Overwrites the paytxfee parameter.
+func (t *RpcTransport) SetTxFee(p SetTxFeeParams) (json.RawMessage, error) {
+	params, err := trimParams("settxfee", []any{p.Amount}, []any{nil})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "settxfee", params...)
+}
+
+// SignMessageParams holds the arguments of the signmessage RPC.
+type SignMessageParams struct {
+	Address string // The dogecoin address to use for the private key.
+	Message string // The message to create a signature of.
+}
+
+// SignMessage calls the signmessage RPC.
+//
+//	signmessage "address" "message"
+//
+// Sign a message with the private key of an address
+func (t *RpcTransport) SignMessage(p SignMessageParams) (json.RawMessage, error) {
+	params, err := trimParams("signmessage", []any{p.Address, p.Message}, []any{nil, nil})
+	if err != nil {
+		return nil, err
+	}
+
+	return Call[json.RawMessage](t, "signmessage", params...)
+}
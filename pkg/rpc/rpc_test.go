@@ -0,0 +1,196 @@
+package rpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// newTestNode serves JSON-RPC requests with reply, which returns the body of
+// the response for a request; %d in it is replaced by the request's id. The
+// requests are recorded in order.
+func newTestNode(t *testing.T, reply func(req rpcRequest) string) (*RpcTransport, *[]rpcRequest) {
+	t.Helper()
+
+	var requests []rpcRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req rpcRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+			return
+		}
+		requests = append(requests, req)
+
+		body := reply(req)
+		if strings.Contains(body, "%d") {
+			body = fmt.Sprintf(body, req.Id)
+		}
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	return NewRpcTransport(&Config{RpcUrl: server.URL}), &requests
+}
+
+func TestNullResult(t *testing.T) {
+	transport, requests := newTestNode(t, func(req rpcRequest) string {
+		return `{"result":null,"error":null,"id":%d}`
+	})
+
+	if err := transport.SetMockTime(1700000000); err != nil {
+		t.Fatalf("SetMockTime: %v", err)
+	}
+
+	command := "onetry"
+	result, err := transport.AddNode(AddNodeParams{Node: "node:18444", Command: command})
+	if err != nil {
+		t.Fatalf("AddNode: %v", err)
+	}
+	if string(result) != "null" {
+		t.Errorf("AddNode result = %s, want null", result)
+	}
+
+	if _, err := transport.SetBan(SetBanParams{Subnet: "172.18.0.3", Command: "add"}); err != nil {
+		t.Fatalf("SetBan: %v", err)
+	}
+	if _, err := transport.ClearBanned(); err != nil {
+		t.Fatalf("ClearBanned: %v", err)
+	}
+
+	if len(*requests) != 4 {
+		t.Fatalf("got %d requests, want 4", len(*requests))
+	}
+}
+
+func TestMissingResult(t *testing.T) {
+	transport, _ := newTestNode(t, func(req rpcRequest) string {
+		return `{"error":null,"id":%d}`
+	})
+
+	_, err := transport.Request("getblockcount", []any{})
+	if err == nil || !strings.Contains(err.Error(), "no result or error") {
+		t.Fatalf("err = %v, want no result error", err)
+	}
+}
+
+func TestCoreError(t *testing.T) {
+	transport, _ := newTestNode(t, func(req rpcRequest) string {
+		return `{"result":null,"error":{"code":-5,"message":"Invalid address"},"id":%d}`
+	})
+
+	_, err := transport.ValidateAddress("nope")
+	if err == nil || !strings.Contains(err.Error(), "Invalid address") {
+		t.Fatalf("err = %v, want Core's error", err)
+	}
+}
+
+func TestTrimParams(t *testing.T) {
+	label := "watch"
+	rescan := false
+
+	tests := []struct {
+		name     string
+		params   []any
+		defaults []any
+		want     []any
+		wantErr  bool
+	}{
+		{
+			name:     "trailing unset",
+			params:   []any{"addr", (*string)(nil), (*bool)(nil)},
+			defaults: []any{nil, "", true},
+			want:     []any{"addr"},
+		},
+		{
+			name:     "all set",
+			params:   []any{"addr", &label, &rescan},
+			defaults: []any{nil, "", true},
+			want:     []any{"addr", &label, &rescan},
+		},
+		{
+			name:     "unset before set takes the default",
+			params:   []any{"addr", (*string)(nil), &rescan},
+			defaults: []any{nil, "", true},
+			want:     []any{"addr", "", &rescan},
+		},
+		{
+			name:     "unset before set without a default",
+			params:   []any{"subnet", "add", (*int64)(nil), &rescan},
+			defaults: []any{nil, nil, nil, nil},
+			wantErr:  true,
+		},
+		{
+			name:     "nil slice is unset",
+			params:   []any{"hex", []any(nil), []any(nil)},
+			defaults: []any{nil, nil, nil},
+			want:     []any{"hex"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := trimParams("method", tt.params, tt.defaults)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("got %v, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGeneratedDefaults(t *testing.T) {
+	transport, requests := newTestNode(t, func(req rpcRequest) string {
+		return `{"result":null,"error":null,"id":%d}`
+	})
+
+	rescan := false
+	_, err := transport.ImportAddress(ImportAddressParams{Script: "nXYZ", Rescan: &rescan})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, _ := json.Marshal((*requests)[0].Params)
+	if string(got) != `["nXYZ","",false]` {
+		t.Errorf("importaddress params = %s, want [\"nXYZ\",\"\",false]", got)
+	}
+}
+
+func TestWalletNullResults(t *testing.T) {
+	transport, requests := newTestNode(t, func(req rpcRequest) string {
+		return `{"result":null,"error":null,"id":%d}`
+	})
+
+	if err := transport.WalletPassphrase("much secret", 60); err != nil {
+		t.Errorf("WalletPassphrase: %v", err)
+	}
+	if err := transport.WalletPassphraseChange("much secret", "very secret"); err != nil {
+		t.Errorf("WalletPassphraseChange: %v", err)
+	}
+	if err := transport.WalletLock(); err != nil {
+		t.Errorf("WalletLock: %v", err)
+	}
+	if err := transport.BackupWallet("/tmp/wallet.bak"); err != nil {
+		t.Errorf("BackupWallet: %v", err)
+	}
+
+	var methods []string
+	for _, req := range *requests {
+		methods = append(methods, req.Method)
+	}
+	want := []string{"walletpassphrase", "walletpassphrasechange", "walletlock", "backupwallet"}
+	if !reflect.DeepEqual(methods, want) {
+		t.Errorf("methods = %v, want %v", methods, want)
+	}
+}
@@ -1,6 +1,10 @@
 package rpc
 
-import "github.com/shopspring/decimal"
+import (
+	"encoding/json"
+
+	"github.com/shopspring/decimal"
+)
 
 type BlockchainInfo struct {
 	Chain                string  `json:"chain"`                // (string) current network name (main, test, regtest)
@@ -49,7 +53,21 @@ type RawTxn struct {
 	LockTime int64        `json:"locktime"` // The lock time
 	VIn      []RawTxnVIn  `json:"vin"`      // Array of transaction inputs (UTXOs to spend)
 	VOut     []RawTxnVOut `json:"vout"`     // Array of transaction outputs (UTXOs to create)
+	Hex      string       `json:"hex"`      // The serialized, hex-encoded transaction
 }
+
+// UnmarshalJSON also accepts a bare txid, which is how getblock lists
+// transactions below verbosity 2.
+func (r *RawTxn) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		*r = RawTxn{}
+		return json.Unmarshal(data, &r.TxID)
+	}
+
+	type rawTxn RawTxn
+	return json.Unmarshal(data, (*rawTxn)(r))
+}
+
 type RawTxnVIn struct {
 	TxID        string          `json:"txid"`        // The transaction id (UTXO)
 	VOut        int             `json:"vout"`        // The output number (UTXO)
@@ -74,6 +92,14 @@ type RawTxnScriptPubKey struct {
 	Addresses []string `json:"addresses"` // Array of dogecoin addresses accepted by the script
 }
 
+type DecodedScript struct {
+	Asm       string   `json:"asm"`       // Script public key
+	Type      string   `json:"type"`      // The output type
+	ReqSigs   int64    `json:"reqSigs"`   // The required signatures
+	Addresses []string `json:"addresses"` // Array of dogecoin addresses
+	P2SH      string   `json:"p2sh"`      // address of P2SH script wrapping this redeem script (not returned if the script is already a P2SH)
+}
+
 type BlockHeader struct {
 	Hash              string          `json:"hash"`              // (string) the block hash (same as provided) (hex)
 	Confirmations     int64           `json:"confirmations"`     // (numeric) The number of confirmations, or -1 if the block is not on the main chain
@@ -107,3 +133,69 @@ type UTXO struct {
 	Safe          bool    `json:"safe"`
 	Confirmations int     `json:"confirmations"`
 }
+
+type WalletTransaction struct {
+	TxID          string  `json:"txid"`          // (string) The transaction id
+	Amount        float64 `json:"amount"`        // (numeric) The transaction amount in DOGE
+	Fee           float64 `json:"fee"`           // (numeric) The amount of the fee in DOGE. This is negative and only available for the 'send' category of transactions
+	Confirmations int64   `json:"confirmations"` // (numeric) The number of confirmations, -1 if the transaction conflicts with the chain
+	BlockHash     string  `json:"blockhash"`     // (string) The block hash
+	BlockIndex    int64   `json:"blockindex"`    // (numeric) The index of the transaction in the block that includes it
+	BlockTime     int64   `json:"blocktime"`     // (numeric) The time in seconds since epoch (1 Jan 1970 GMT)
+	Time          int64   `json:"time"`          // (numeric) The transaction time in seconds since epoch (1 Jan 1970 GMT)
+	TimeReceived  int64   `json:"timereceived"`  // (numeric) The time received in seconds since epoch (1 Jan 1970 GMT)
+	Hex           string  `json:"hex"`           // (string) Raw data for transaction
+}
+
+type WalletInfo struct {
+	WalletVersion      int64   `json:"walletversion"`       // (numeric) the wallet version
+	Balance            float64 `json:"balance"`             // (numeric) the total confirmed balance of the wallet in DOGE
+	UnconfirmedBalance float64 `json:"unconfirmed_balance"` // (numeric) the total unconfirmed balance of the wallet in DOGE
+	ImmatureBalance    float64 `json:"immature_balance"`    // (numeric) the total immature balance of the wallet in DOGE
+	TxCount            int64   `json:"txcount"`             // (numeric) the total number of transactions in the wallet
+	KeypoolOldest      int64   `json:"keypoololdest"`       // (numeric) the timestamp (seconds since Unix epoch) of the oldest pre-generated key in the key pool
+	KeypoolSize        int64   `json:"keypoolsize"`         // (numeric) how many new keys are pre-generated
+	UnlockedUntil      *int64  `json:"unlocked_until"`      // (numeric) the timestamp in seconds since epoch that the wallet is unlocked for transfers, or 0 if the wallet is locked (only present if encrypted)
+	PayTxFee           float64 `json:"paytxfee"`            // (numeric) the transaction fee configuration, set in DOGE/kB
+	HDMasterKeyID      string  `json:"hdmasterkeyid"`       // (string) the Hash160 of the HD master pubkey
+}
+
+func (w *WalletInfo) IsEncrypted() bool {
+	return w.UnlockedUntil != nil
+}
+
+func (w *WalletInfo) IsLocked() bool {
+	return w.UnlockedUntil != nil && *w.UnlockedUntil == 0
+}
+
+type ValidatedAddress struct {
+	IsValid       bool   `json:"isvalid"`       // (boolean) If the address is valid or not. If not, this is the only property returned.
+	Address       string `json:"address"`       // (string) The dogecoin address validated
+	ScriptPubKey  string `json:"scriptPubKey"`  // (string) The hex encoded scriptPubKey generated by the address
+	IsMine        bool   `json:"ismine"`        // (boolean) If the address is yours or not
+	IsWatchOnly   bool   `json:"iswatchonly"`   // (boolean) If the address is watchonly
+	IsScript      bool   `json:"isscript"`      // (boolean) If the key is a script
+	PubKey        string `json:"pubkey"`        // (string) The hex value of the raw public key
+	IsCompressed  bool   `json:"iscompressed"`  // (boolean) If the address is compressed
+	Account       string `json:"account"`       // (string) DEPRECATED. The account associated with the address, "" is the default account
+	HDKeyPath     string `json:"hdkeypath"`     // (string, optional) The HD keypath if the key is HD and available
+	HDMasterKeyID string `json:"hdmasterkeyid"` // (string, optional) The Hash160 of the HD master pubkey
+}
+
+type PeerInfo struct {
+	ID             int64  `json:"id"`             // (numeric) Peer index
+	Addr           string `json:"addr"`           // (string) The ip address and port of the peer
+	AddrLocal      string `json:"addrlocal"`      // (string) local address
+	Services       string `json:"services"`       // (string) The services offered
+	RelayTxes      bool   `json:"relaytxes"`      // (boolean) Whether peer has asked us to relay transactions to it
+	ConnTime       int64  `json:"conntime"`       // (numeric) The connection time in seconds since epoch (Jan 1 1970 GMT)
+	Version        int64  `json:"version"`        // (numeric) The peer version, such as 70015
+	SubVer         string `json:"subver"`         // (string) The string version
+	Inbound        bool   `json:"inbound"`        // (boolean) Inbound (true) or Outbound (false)
+	AddNode        bool   `json:"addnode"`        // (boolean) Whether connection was due to addnode and is using an addnode slot
+	StartingHeight int64  `json:"startingheight"` // (numeric) The starting height (block) of the peer
+	BanScore       int64  `json:"banscore"`       // (numeric) The ban score
+	SyncedHeaders  int64  `json:"synced_headers"` // (numeric) The last header we have in common with this peer
+	SyncedBlocks   int64  `json:"synced_blocks"`  // (numeric) The last block we have in common with this peer
+	Whitelisted    bool   `json:"whitelisted"`    // (boolean) Whether the peer is whitelisted
+}
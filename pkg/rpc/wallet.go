@@ -0,0 +1,56 @@
+package rpc
+
+// EncryptWallet encrypts the wallet with passphrase. The node shuts itself
+// down once encryption completes and has to be restarted before further use.
+func (t *RpcTransport) EncryptWallet(passphrase string) error {
+	_, err := t.Request("encryptwallet", []any{passphrase})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// WalletPassphrase unlocks an encrypted wallet for timeout seconds.
+func (t *RpcTransport) WalletPassphrase(passphrase string, timeout int64) error {
+	_, err := t.Request("walletpassphrase", []any{passphrase, timeout})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// WalletLock removes the wallet encryption key from memory.
+func (t *RpcTransport) WalletLock() error {
+	_, err := t.Request("walletlock", []any{})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// WalletPassphraseChange replaces the wallet passphrase.
+func (t *RpcTransport) WalletPassphraseChange(oldPassphrase string, newPassphrase string) error {
+	_, err := t.Request("walletpassphrasechange", []any{oldPassphrase, newPassphrase})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// BackupWallet copies the wallet file to destination on the node's filesystem.
+func (t *RpcTransport) BackupWallet(destination string) error {
+	_, err := t.Request("backupwallet", []any{destination})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (t *RpcTransport) GetWalletInfo() (*WalletInfo, error) {
+	return Call[*WalletInfo](t, "getwalletinfo")
+}
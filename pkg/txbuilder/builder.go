@@ -0,0 +1,308 @@
+package txbuilder
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/dogecoinfoundation/dogetest/pkg/doge"
+	"github.com/dogecoinfoundation/dogetest/pkg/rpc"
+)
+
+// DefaultFeeRate is the fee rate in koinu per kB used for change outputs,
+// the recommended minimum fee of Dogecoin Core 1.14.
+const DefaultFeeRate int64 = 1_000_000
+
+// DustLimit is the soft dust limit of Dogecoin Core 1.14 in koinu. The node
+// charges an extra fee for every output below it, so Build pays change that
+// small as fee instead.
+const DustLimit int64 = 1_000_000
+
+var ErrInsufficientFunds = errors.New("inputs do not cover outputs and fee")
+
+// SignFunc produces the scriptSig for input index of tx. It is called once
+// all inputs and outputs are in place.
+type SignFunc func(tx *doge.Tx, index int) ([]byte, error)
+
+type input struct {
+	utxo rpc.UTXO
+	sign SignFunc
+}
+
+// Builder assembles and signs a transaction spending rpc.UTXO values, e.g.
+// from RpcTransport.ListUnspent or DogeTest.GetWallet.
+type Builder struct {
+	Version  int32
+	LockTime uint32
+	// Sequence is used for every input that doesn't set its own.
+	Sequence uint32
+	HashType doge.SigHashType
+
+	inputs    []input
+	sequences map[int]uint32
+	outputs   []*doge.TxOut
+
+	changeScript []byte
+	feeRate      int64
+}
+
+func New() *Builder {
+	return &Builder{
+		Version:   1,
+		Sequence:  doge.MaxTxInSequenceNum,
+		HashType:  doge.SigHashAll,
+		sequences: map[int]uint32{},
+	}
+}
+
+// AddP2PKHInput spends a pay-to-pubkey-hash output with the WIF key that
+// owns it, such as Address.PrivateKey from DogeTest.SetupAddresses.
+func (b *Builder) AddP2PKHInput(utxo rpc.UTXO, wif string) error {
+	key, err := doge.KeyPairFromWIF(wif)
+	if err != nil {
+		return err
+	}
+
+	return b.AddP2PKHInputKey(utxo, key)
+}
+
+// AddP2PKHInputKey spends a pay-to-pubkey-hash output of key, e.g. one
+// generated offline with doge.GenerateKeyPair and funded with SendToAddress.
+func (b *Builder) AddP2PKHInputKey(utxo rpc.UTXO, key *doge.KeyPair) error {
+	prevScript, err := hex.DecodeString(utxo.ScriptPubKey)
+	if err != nil {
+		return fmt.Errorf("utxo %s:%d: %w", utxo.TxID, utxo.Vout, err)
+	}
+
+	pubKey := key.PubKey()
+	if !bytes.Equal(prevScript, doge.PayToPubKeyHashScript(doge.Hash160(pubKey))) {
+		return fmt.Errorf("utxo %s:%d is not a P2PKH output of this key", utxo.TxID, utxo.Vout)
+	}
+
+	b.AddInput(utxo, func(tx *doge.Tx, index int) ([]byte, error) {
+		sig, err := doge.SignInput(tx, index, prevScript, key.PrivateKey, b.HashType)
+		if err != nil {
+			return nil, err
+		}
+		return doge.NewScriptBuilder().AddData(sig).AddData(pubKey).Script(), nil
+	})
+
+	return nil
+}
+
+// AddP2SHInput spends a pay-to-script-hash output. The redeem script must be
+// a bare multisig script, in which case wifs must hold at least m of its
+// keys, or a script ending in <pubkey> OP_CHECKSIG with wifs holding that
+// key, such as doge.LockTimeScript and doge.RelativeLockScript. Use AddInput
+// for any other redeem script.
+func (b *Builder) AddP2SHInput(utxo rpc.UTXO, redeemScript []byte, wifs ...string) error {
+	prevScript, err := hex.DecodeString(utxo.ScriptPubKey)
+	if err != nil {
+		return fmt.Errorf("utxo %s:%d: %w", utxo.TxID, utxo.Vout, err)
+	}
+	if !bytes.Equal(prevScript, doge.PayToScriptHashScript(doge.Hash160(redeemScript))) {
+		return fmt.Errorf("utxo %s:%d is not a P2SH output of this redeem script", utxo.TxID, utxo.Vout)
+	}
+
+	type signer struct {
+		key      *secp256k1.PrivateKey
+		position int
+	}
+
+	var signers []signer
+	for _, wif := range wifs {
+		key, compressed, _, err := doge.DecodeWIF(wif)
+		if err != nil {
+			return err
+		}
+
+		position := bytes.Index(redeemScript, doge.SerializePubKey(key, compressed))
+		if position < 0 {
+			return fmt.Errorf("key for %s:%d does not appear in the redeem script", utxo.TxID, utxo.Vout)
+		}
+		signers = append(signers, signer{key: key, position: position})
+	}
+
+	// CHECKMULTISIG expects signatures in the same order as the pubkeys.
+	sort.Slice(signers, func(i, j int) bool {
+		return signers[i].position < signers[j].position
+	})
+
+	multisig := len(redeemScript) > 0 && redeemScript[len(redeemScript)-1] == doge.OP_CHECKMULTISIG
+
+	b.AddInput(utxo, func(tx *doge.Tx, index int) ([]byte, error) {
+		sb := doge.NewScriptBuilder()
+		if multisig {
+			// CHECKMULTISIG pops one extra element, which must be empty (NULLDUMMY).
+			sb.AddOp(doge.OP_0)
+		}
+		for _, s := range signers {
+			sig, err := doge.SignInput(tx, index, redeemScript, s.key, b.HashType)
+			if err != nil {
+				return nil, err
+			}
+			sb.AddData(sig)
+		}
+		return sb.AddData(redeemScript).Script(), nil
+	})
+
+	return nil
+}
+
+// AddInput spends utxo with a caller-supplied scriptSig, for scripts the
+// builder has no template for.
+func (b *Builder) AddInput(utxo rpc.UTXO, sign SignFunc) {
+	b.inputs = append(b.inputs, input{utxo: utxo, sign: sign})
+}
+
+// SetSequence overrides the sequence number of input index.
+func (b *Builder) SetSequence(index int, sequence uint32) {
+	b.sequences[index] = sequence
+}
+
+// AddOutput pays amount koinu to a P2PKH or P2SH address.
+func (b *Builder) AddOutput(address string, amount int64) error {
+	script, err := doge.PayToAddrScript(address)
+	if err != nil {
+		return err
+	}
+
+	b.AddScriptOutput(script, amount)
+	return nil
+}
+
+// AddScriptOutput pays amount koinu to an arbitrary output script.
+func (b *Builder) AddScriptOutput(script []byte, amount int64) {
+	b.outputs = append(b.outputs, &doge.TxOut{Value: amount, ScriptPubKey: script})
+}
+
+// SetChange sends whatever the inputs hold beyond the outputs, minus a fee
+// of feeRate koinu per kB, to address. Without change, or with change below
+// DustLimit, the entire surplus is paid as fee.
+func (b *Builder) SetChange(address string, feeRate int64) error {
+	script, err := doge.PayToAddrScript(address)
+	if err != nil {
+		return err
+	}
+
+	b.changeScript = script
+	b.feeRate = feeRate
+	return nil
+}
+
+// Build assembles and signs the transaction.
+func (b *Builder) Build() (*doge.Tx, error) {
+	if len(b.inputs) == 0 {
+		return nil, errors.New("transaction has no inputs")
+	}
+
+	var inputTotal, outputTotal int64
+	for _, in := range b.inputs {
+		inputTotal += doge.ToKoinu(in.utxo.Amount)
+	}
+	for _, out := range b.outputs {
+		outputTotal += out.Value
+	}
+	if inputTotal < outputTotal {
+		return nil, fmt.Errorf("%w: inputs %d, outputs %d koinu", ErrInsufficientFunds, inputTotal, outputTotal)
+	}
+
+	tx := doge.NewTx()
+	tx.Version = b.Version
+	tx.LockTime = b.LockTime
+	for i, in := range b.inputs {
+		sequence, ok := b.sequences[i]
+		if !ok {
+			sequence = b.Sequence
+		}
+		tx.TxIn = append(tx.TxIn, &doge.TxIn{
+			PrevTxID:  in.utxo.TxID,
+			PrevIndex: uint32(in.utxo.Vout),
+			Sequence:  sequence,
+		})
+	}
+	for _, out := range b.outputs {
+		tx.TxOut = append(tx.TxOut, &doge.TxOut{Value: out.Value, ScriptPubKey: out.ScriptPubKey})
+	}
+
+	if b.changeScript == nil {
+		if err := b.sign(tx); err != nil {
+			return nil, err
+		}
+		return tx, nil
+	}
+
+	// Sign once with the whole surplus as change to learn the size, then
+	// again with the fee deducted. Signatures may differ in length by a byte
+	// or two, which the per-input margin usually covers; if the new
+	// signatures still need more, deduct that and sign again.
+	surplus := inputTotal - outputTotal
+	change := &doge.TxOut{Value: surplus, ScriptPubKey: b.changeScript}
+	tx.TxOut = append(tx.TxOut, change)
+	fee, err := b.signForFee(tx)
+	if err != nil {
+		return nil, err
+	}
+	for surplus-fee >= DustLimit {
+		change.Value = surplus - fee
+		need, err := b.signForFee(tx)
+		if err != nil {
+			return nil, err
+		}
+		if need <= fee {
+			return tx, nil
+		}
+		fee = need
+	}
+
+	// Change below the dust limit costs more than it is worth; without it
+	// the transaction is smaller and the whole surplus is the fee.
+	tx.TxOut = tx.TxOut[:len(tx.TxOut)-1]
+	fee, err = b.signForFee(tx)
+	if err != nil {
+		return nil, err
+	}
+	if surplus < fee {
+		return nil, fmt.Errorf("%w: surplus %d, fee %d koinu", ErrInsufficientFunds, surplus, fee)
+	}
+	return tx, nil
+}
+
+// signForFee signs tx and returns the fee it needs at the builder's rate.
+func (b *Builder) signForFee(tx *doge.Tx) (int64, error) {
+	if err := b.sign(tx); err != nil {
+		return 0, err
+	}
+
+	data, err := tx.Serialize()
+	if err != nil {
+		return 0, err
+	}
+	size := int64(len(data) + 2*len(tx.TxIn))
+	return (size*b.feeRate + 999) / 1000, nil
+}
+
+func (b *Builder) sign(tx *doge.Tx) error {
+	for i, in := range b.inputs {
+		scriptSig, err := in.sign(tx, i)
+		if err != nil {
+			return fmt.Errorf("sign input %d: %w", i, err)
+		}
+		tx.TxIn[i].ScriptSig = scriptSig
+	}
+
+	return nil
+}
+
+// Broadcast submits tx to the node with sendrawtransaction and returns its
+// txid.
+func Broadcast(t *rpc.RpcTransport, tx *doge.Tx) (string, error) {
+	txHex, err := tx.Hex()
+	if err != nil {
+		return "", err
+	}
+	return t.SendRawTransaction(txHex)
+}
@@ -0,0 +1,253 @@
+package txbuilder
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/dogecoinfoundation/dogetest/pkg/doge"
+	"github.com/dogecoinfoundation/dogetest/pkg/rpc"
+)
+
+func newTestKey(t *testing.T) *doge.KeyPair {
+	t.Helper()
+	key, err := doge.GenerateKeyPair(&doge.RegTestParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+// utxo returns output vout of a fake transaction paying amount DOGE to
+// script.
+func utxo(vout int, amount float64, script []byte) rpc.UTXO {
+	return rpc.UTXO{
+		TxID:         "4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b",
+		Vout:         vout,
+		Amount:       amount,
+		ScriptPubKey: hex.EncodeToString(script),
+	}
+}
+
+// verify checks every input of tx against the utxos it spends with the
+// node's mempool rules and returns the fee paid.
+func verify(t *testing.T, tx *doge.Tx, utxos ...rpc.UTXO) int64 {
+	t.Helper()
+
+	var prevOuts []*doge.TxOut
+	var fee int64
+	for _, u := range utxos {
+		script, err := hex.DecodeString(u.ScriptPubKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		prevOuts = append(prevOuts, &doge.TxOut{Value: doge.ToKoinu(u.Amount), ScriptPubKey: script})
+		fee += doge.ToKoinu(u.Amount)
+	}
+	if err := doge.VerifyTx(tx, prevOuts, doge.StandardVerifyFlags); err != nil {
+		t.Fatal(err)
+	}
+	for _, out := range tx.TxOut {
+		fee -= out.Value
+	}
+	return fee
+}
+
+// wantFee checks that fee pays at least DefaultFeeRate for the size of tx,
+// and not much more: the builder's margin of two bytes per input, plus the
+// bytes by which the signatures the fee was computed from were longer.
+func wantFee(t *testing.T, tx *doge.Tx, fee int64) {
+	t.Helper()
+
+	data, err := tx.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	size := int64(len(data))
+	lo := (size*DefaultFeeRate + 999) / 1000
+	hi := ((size+5*int64(len(tx.TxIn)))*DefaultFeeRate + 999) / 1000
+	if fee < lo || fee > hi {
+		t.Errorf("fee %d for %d bytes, want %d..%d", fee, size, lo, hi)
+	}
+}
+
+func TestBuildP2PKH(t *testing.T) {
+	key, to := newTestKey(t), newTestKey(t)
+	p2pkh := doge.PayToPubKeyHashScript(doge.Hash160(key.PubKey()))
+	utxos := []rpc.UTXO{utxo(0, 3, p2pkh), utxo(1, 4.5, p2pkh)}
+
+	b := New()
+	for _, u := range utxos {
+		if err := b.AddP2PKHInput(u, key.WIF()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := b.AddOutput(to.Address().String(), 5*doge.KoinuPerDoge); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.SetChange(key.Address().String(), DefaultFeeRate); err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tx.TxOut) != 2 || !bytes.Equal(tx.TxOut[1].ScriptPubKey, p2pkh) {
+		t.Fatalf("outputs %v, want the payment and change to the key", tx.TxOut)
+	}
+	wantFee(t, tx, verify(t, tx, utxos...))
+
+	if err := b.AddP2PKHInput(utxo(2, 1, p2pkh), to.WIF()); err == nil {
+		t.Error("added an input with a key that does not own it")
+	}
+}
+
+func TestBuildP2SH(t *testing.T) {
+	keys := []*doge.KeyPair{newTestKey(t), newTestKey(t), newTestKey(t)}
+	multisig, err := doge.MultiSigScript(2, [][]byte{keys[0].PubKey(), keys[1].PubKey(), keys[2].PubKey()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	timelock := doge.LockTimeScript(100, keys[0].PubKey())
+
+	multisigUTXO := utxo(0, 2, doge.PayToScriptHashScript(doge.Hash160(multisig)))
+	timelockUTXO := utxo(1, 2, doge.PayToScriptHashScript(doge.Hash160(timelock)))
+
+	b := New()
+	b.LockTime = 100
+	b.Sequence = doge.MaxTxInSequenceNum - 1
+	// Keys given out of order are sorted for CHECKMULTISIG.
+	if err := b.AddP2SHInput(multisigUTXO, multisig, keys[2].WIF(), keys[0].WIF()); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.AddP2SHInput(timelockUTXO, timelock, keys[0].WIF()); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.SetChange(keys[1].Address().String(), DefaultFeeRate); err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantFee(t, tx, verify(t, tx, multisigUTXO, timelockUTXO))
+
+	if err := b.AddP2SHInput(multisigUTXO, timelock, keys[0].WIF()); err == nil {
+		t.Error("added an input with a redeem script it does not pay to")
+	}
+	if err := b.AddP2SHInput(timelockUTXO, timelock, keys[1].WIF()); err == nil {
+		t.Error("added an input with a key missing from the redeem script")
+	}
+}
+
+// TestBuildFeeConverges builds with many signatures over and over: their
+// lengths vary between signing passes, and the fee must still cover the
+// final size every time.
+func TestBuildFeeConverges(t *testing.T) {
+	keys := []*doge.KeyPair{newTestKey(t), newTestKey(t), newTestKey(t)}
+	multisig, err := doge.MultiSigScript(3, [][]byte{keys[0].PubKey(), keys[1].PubKey(), keys[2].PubKey()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	prevScript := doge.PayToScriptHashScript(doge.Hash160(multisig))
+	utxos := []rpc.UTXO{utxo(0, 1, prevScript), utxo(1, 1, prevScript), utxo(2, 1, prevScript)}
+
+	for range 50 {
+		b := New()
+		for _, u := range utxos {
+			if err := b.AddP2SHInput(u, multisig, keys[0].WIF(), keys[1].WIF(), keys[2].WIF()); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := b.SetChange(keys[0].Address().String(), DefaultFeeRate); err != nil {
+			t.Fatal(err)
+		}
+		tx, err := b.Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		data, err := tx.Serialize()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fee, need := verify(t, tx, utxos...), int64(len(data))*DefaultFeeRate/1000; fee < need {
+			t.Fatalf("fee %d for %d bytes, want at least %d", fee, len(data), need)
+		}
+	}
+}
+
+func TestBuildDustChange(t *testing.T) {
+	key := newTestKey(t)
+	p2pkh := doge.PayToPubKeyHashScript(doge.Hash160(key.PubKey()))
+	in := utxo(0, 1, p2pkh)
+
+	build := func(amount int64) *doge.Tx {
+		t.Helper()
+		b := New()
+		if err := b.AddP2PKHInputKey(in, key); err != nil {
+			t.Fatal(err)
+		}
+		b.AddScriptOutput(p2pkh, amount)
+		if err := b.SetChange(key.Address().String(), DefaultFeeRate); err != nil {
+			t.Fatal(err)
+		}
+		tx, err := b.Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tx
+	}
+
+	// Well above the dust limit after a fee of about 0.0023 DOGE.
+	tx := build(doge.KoinuPerDoge / 2)
+	if len(tx.TxOut) != 2 || tx.TxOut[1].Value < DustLimit {
+		t.Fatalf("outputs %v, want change of at least %d", tx.TxOut, DustLimit)
+	}
+
+	// The surplus covers the fee but leaves less than the dust limit.
+	tx = build(doge.KoinuPerDoge - DustLimit)
+	if len(tx.TxOut) != 1 {
+		t.Fatalf("outputs %v, want the dust change paid as fee", tx.TxOut)
+	}
+	if fee := verify(t, tx, in); fee != DustLimit {
+		t.Errorf("fee = %d, want the whole surplus %d", fee, DustLimit)
+	}
+}
+
+func TestBuildInsufficientFunds(t *testing.T) {
+	key := newTestKey(t)
+	p2pkh := doge.PayToPubKeyHashScript(doge.Hash160(key.PubKey()))
+
+	tests := []struct {
+		name   string
+		amount int64
+	}{
+		{"outputs exceed inputs", doge.KoinuPerDoge + 1},
+		{"surplus below the fee", doge.KoinuPerDoge - 1000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New()
+			if err := b.AddP2PKHInputKey(utxo(0, 1, p2pkh), key); err != nil {
+				t.Fatal(err)
+			}
+			b.AddScriptOutput(p2pkh, tt.amount)
+			if err := b.SetChange(key.Address().String(), DefaultFeeRate); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := b.Build(); !errors.Is(err, ErrInsufficientFunds) {
+				t.Errorf("err = %v, want ErrInsufficientFunds", err)
+			}
+		})
+	}
+
+	if _, err := New().Build(); err == nil {
+		t.Error("built a transaction without inputs")
+	}
+}